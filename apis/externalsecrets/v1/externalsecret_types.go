@@ -76,6 +76,18 @@ const (
 	DeletionPolicyRetain ExternalSecretDeletionPolicy = "Retain"
 )
 
+// ExternalSecretDriftPolicy defines what happens if the target Secret drifted from the last sync.
+// +kubebuilder:validation:Enum=Repair;Report
+type ExternalSecretDriftPolicy string
+
+const (
+	// DriftPolicyRepair refreshes the target right away when it drifted, restoring the rendered Secret.
+	DriftPolicyRepair ExternalSecretDriftPolicy = "Repair"
+
+	// DriftPolicyReport only reports the drift, the target is restored by the next refresh.
+	DriftPolicyReport ExternalSecretDriftPolicy = "Report"
+)
+
 // ExternalSecretTemplateMetadata defines metadata fields for the Secret blueprint.
 type ExternalSecretTemplateMetadata struct {
 	// +optional
@@ -206,6 +218,95 @@ type ExternalSecretTarget struct {
 	// Immutable defines if the final secret will be immutable
 	// +optional
 	Immutable bool `json:"immutable,omitempty"`
+
+	// Manifest defines the kind of resource to be managed instead of a Secret,
+	// e.g. a ConfigMap or a custom resource.
+	// Defaults to a Secret if not set.
+	// Requires the controller to run with --unsafe-allow-generic-targets.
+	// +optional
+	Manifest *ExternalSecretTargetManifest `json:"manifest,omitempty"`
+
+	// History keeps the last rendered versions of the target Secret,
+	// so the target can be pinned to an older version, e.g. after a bad rotation at the provider.
+	// Only supported for Secret targets.
+	// +optional
+	History *ExternalSecretTargetHistory `json:"history,omitempty"`
+
+	// Validation defines rules the data fetched from the providers must pass before the template is applied.
+	// If a rule fails, the target is left untouched and keeps its last valid data.
+	// +optional
+	Validation *ExternalSecretValidation `json:"validation,omitempty"`
+
+	// DriftPolicy defines what happens if the type, data, or the labels and annotations set by the template
+	// of the target Secret were changed since the last sync.
+	// The target is compared against the hash of the Secret written by the last sync, kept in status.targetHash,
+	// the providers are not called for it: changes of the remote values are picked up by the next refresh.
+	// With Report, a target which lost the managed label or the data-hash annotation is still refreshed.
+	// Drift is only detected for the creation policies Owner and Orphan.
+	// Defaults to "Repair"
+	// +optional
+	DriftPolicy ExternalSecretDriftPolicy `json:"driftPolicy,omitempty"`
+}
+
+// ExternalSecretValidation defines rules the fetched data is validated against.
+type ExternalSecretValidation struct {
+	// Rules which all must pass.
+	// +kubebuilder:validation:MinItems=1
+	Rules []ExternalSecretValidationRule `json:"rules"`
+}
+
+// ExternalSecretValidationRule validates the fetched data with either a regular expression or a CEL expression.
+type ExternalSecretValidationRule struct {
+	// Key of the fetched data the rule applies to.
+	// If omitted, a regex is matched against every key and an expression is evaluated once for all data.
+	// A rule fails if the key does not exist.
+	// +optional
+	Key string `json:"key,omitempty"`
+
+	// Regex the value must match.
+	// +optional
+	Regex string `json:"regex,omitempty"`
+
+	// Expression is a CEL expression which must evaluate to true.
+	// The variables `key` and `value` hold the key and value the rule applies to,
+	// `data` holds all fetched data and `now` the current time.
+	// Besides the standard CEL functions, isPEM(string), isJSON(string)
+	// and certNotAfter(string) for the earliest expiry of the certificates of a PEM or DER value are available.
+	// +optional
+	Expression string `json:"expression,omitempty"`
+
+	// Message is reported if the rule fails, instead of the regex or expression.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// ExternalSecretTargetHistory defines how many versions of the target Secret are kept.
+// Every version is stored in its own immutable Secret, named <target>-history-<version>.
+type ExternalSecretTargetHistory struct {
+	// Limit is the number of versions to keep, including the current one.
+	// +optional
+	// +kubebuilder:default=10
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	Limit int32 `json:"limit,omitempty"`
+}
+
+// ExternalSecretTargetManifest defines a non-Secret resource to be managed by the ExternalSecret.
+type ExternalSecretTargetManifest struct {
+	// APIVersion of the target resource, e.g. "v1" or "argoproj.io/v1alpha1".
+	// +kubebuilder:validation:MinLength:=1
+	APIVersion string `json:"apiVersion"`
+
+	// Kind of the target resource, e.g. "ConfigMap".
+	// +kubebuilder:validation:MinLength:=1
+	Kind string `json:"kind"`
+
+	// Template is a YAML or JSON document defining the body of the target resource,
+	// i.e. everything except apiVersion, kind and metadata.
+	// It is rendered with the template engine, using the secret data as input.
+	// Required for every kind except ConfigMap.
+	// +optional
+	Template string `json:"template,omitempty"`
 }
 
 // ExternalSecretData defines the connection between the Kubernetes Secret key (spec.data.<key>) and the Provider data.
@@ -223,6 +324,15 @@ type ExternalSecretData struct {
 	// SourceRef allows you to override the source
 	// from which the value will be pulled.
 	SourceRef *StoreSourceRef `json:"sourceRef,omitempty"`
+
+	// Optional marks this entry as optional. If the remote key does not exist,
+	// the key is skipped (or set to Default, if specified) instead of failing the ExternalSecret.
+	// +optional
+	Optional bool `json:"optional,omitempty"`
+
+	// Default is the value used for this key if the entry is optional and the remote key does not exist.
+	// +optional
+	Default *string `json:"default,omitempty"`
 }
 
 // ExternalSecretDataRemoteRef defines Provider data location.
@@ -302,6 +412,15 @@ type ExternalSecretDataFromRemoteRef struct {
 	// When sourceRef points to a generator Extract or Find is not supported.
 	// The generator returns a static map of values
 	SourceRef *StoreGeneratorSourceRef `json:"sourceRef,omitempty"`
+
+	// Optional marks this entry as optional. If the remote secret does not exist,
+	// it is skipped (or Default is used, if specified) instead of failing the ExternalSecret.
+	// +optional
+	Optional bool `json:"optional,omitempty"`
+
+	// Default is the set of key/value pairs used if the entry is optional and the remote secret does not exist.
+	// +optional
+	Default map[string]string `json:"default,omitempty"`
 }
 
 // +kubebuilder:validation:MinProperties=1
@@ -417,6 +536,12 @@ type ExternalSecretSpec struct {
 	// +optional
 	SecretStoreRef SecretStoreRef `json:"secretStoreRef,omitempty"`
 
+	// FallbackStoreRefs are tried in order if secretStoreRef is unavailable, i.e. its client
+	// can not be created or the provider fails with a transport error, a timeout or a server error.
+	// They apply to all entries which do not set their own sourceRef.storeRef.
+	// +optional
+	FallbackStoreRefs []SecretStoreRef `json:"fallbackStoreRefs,omitempty"`
+
 	// +kubebuilder:default={creationPolicy:Owner,deletionPolicy:Retain}
 	// +optional
 	Target ExternalSecretTarget `json:"target,omitempty"`
@@ -445,6 +570,45 @@ type ExternalSecretSpec struct {
 	// If multiple entries are specified, the Secret keys are merged in the specified order
 	// +optional
 	DataFrom []ExternalSecretDataFromRemoteRef `json:"dataFrom,omitempty"`
+
+	// DryRun runs the full fetch and template pipeline without writing the target.
+	// The changes that would be applied are written to status.dryRun and emitted as an event.
+	// +optional
+	DryRun bool `json:"dryRun,omitempty"`
+
+	// Rollout restarts workloads whenever the data of the target Secret changes.
+	// Requires the controller to run with --enable-rollout-triggers.
+	// +optional
+	Rollout *ExternalSecretRollout `json:"rollout,omitempty"`
+}
+
+// ExternalSecretRollout defines the workloads to restart when the data of the target Secret changes.
+// Workloads are restarted by setting an annotation on their pod template, like `kubectl rollout restart` does.
+type ExternalSecretRollout struct {
+	// Workloads lists the workloads to restart by kind and name.
+	// +optional
+	Workloads []ExternalSecretRolloutWorkload `json:"workloads,omitempty"`
+
+	// Selector selects the Deployments, StatefulSets and DaemonSets to restart by label.
+	// +optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+
+	// MinInterval is the minimum time between two restarts.
+	// Changes within the interval are rolled out once it has elapsed.
+	// +optional
+	MinInterval *metav1.Duration `json:"minInterval,omitempty"`
+}
+
+// ExternalSecretRolloutWorkload references a workload in the namespace of the ExternalSecret.
+type ExternalSecretRolloutWorkload struct {
+	// Kind of the workload.
+	// +kubebuilder:validation:Enum=Deployment;StatefulSet;DaemonSet
+	Kind string `json:"kind"`
+
+	// Name of the workload.
+	// +kubebuilder:validation:MinLength:=1
+	// +kubebuilder:validation:MaxLength:=253
+	Name string `json:"name"`
 }
 
 // StoreSourceRef allows you to override the SecretStore source
@@ -499,6 +663,7 @@ type ExternalSecretConditionType string
 const (
 	ExternalSecretReady   ExternalSecretConditionType = "Ready"
 	ExternalSecretDeleted ExternalSecretConditionType = "Deleted"
+	ExternalSecretDrifted ExternalSecretConditionType = "Drifted"
 )
 
 type ExternalSecretStatusCondition struct {
@@ -524,6 +689,18 @@ const (
 	ConditionReasonSecretDeleted = "SecretDeleted"
 	// ConditionReasonSecretMissing indicates that the secret is missing.
 	ConditionReasonSecretMissing = "SecretMissing"
+	// ConditionReasonDryRun indicates that the changes were computed but not applied.
+	ConditionReasonDryRun = "DryRun"
+	// ConditionReasonSecretPinned indicates that the secret is pinned to a version of its history.
+	ConditionReasonSecretPinned = "SecretPinned"
+	// ConditionReasonSecretValidationFailed indicates that the fetched data did not pass the validation rules.
+	ConditionReasonSecretValidationFailed = "SecretValidationFailed"
+	// ConditionReasonSecretSyncThrottled indicates that the rate limit of a store was exceeded.
+	ConditionReasonSecretSyncThrottled = "SecretSyncThrottled"
+	// ConditionReasonSecretDrifted indicates that the target secret was changed since the last sync.
+	ConditionReasonSecretDrifted = "SecretDrifted"
+	// ConditionReasonDriftRepaired indicates that the target secret matches the last sync again.
+	ConditionReasonDriftRepaired = "DriftRepaired"
 
 	ReasonUpdateFailed          = "UpdateFailed"
 	ReasonDeprecated            = "ParameterDeprecated"
@@ -531,6 +708,14 @@ const (
 	ReasonUpdated               = "Updated"
 	ReasonDeleted               = "Deleted"
 	ReasonMissingProviderSecret = "MissingProviderSecret"
+	ReasonDryRun                = "DryRun"
+	ReasonPinned                = "Pinned"
+	ReasonRolloutTriggered      = "RolloutTriggered"
+	ReasonRolloutFailed         = "RolloutFailed"
+	ReasonStoreFallback         = "StoreFallback"
+	ReasonThrottled             = "Throttled"
+	ReasonDrifted               = "Drifted"
+	ReasonExpiring              = "Expiring"
 )
 
 type ExternalSecretStatus struct {
@@ -547,6 +732,176 @@ type ExternalSecretStatus struct {
 
 	// Binding represents a servicebinding.io Provisioned Service reference to the secret
 	Binding corev1.LocalObjectReference `json:"binding,omitempty"`
+
+	// MissingKeys lists the optional entries whose remote secret did not exist during the last sync.
+	// +optional
+	MissingKeys []ExternalSecretMissingKey `json:"missingKeys,omitempty"`
+
+	// Keys describes the sync of the entries of spec.dataFrom and spec.data, in the order of the spec.
+	// It holds at most 100 entries, failed entries are kept first if there are more.
+	// +optional
+	Keys []ExternalSecretKeyStatus `json:"keys,omitempty"`
+
+	// ActiveStoreRef is the store which served the data of secretStoreRef during the last sync,
+	// if fallbackStoreRefs are set.
+	// +optional
+	ActiveStoreRef *SecretStoreRef `json:"activeStoreRef,omitempty"`
+
+	// DryRun holds the changes the last dry-run would have applied to the target.
+	// +optional
+	DryRun *ExternalSecretDryRunStatus `json:"dryRun,omitempty"`
+
+	// History lists the versions of the target Secret which are kept, oldest first.
+	// +optional
+	History []ExternalSecretHistoryVersion `json:"history,omitempty"`
+
+	// PinnedVersion is the version of the history the target Secret is pinned to.
+	// +optional
+	PinnedVersion int64 `json:"pinnedVersion,omitempty"`
+
+	// Rollout holds the state of the last restart of the workloads in spec.rollout.
+	// +optional
+	Rollout *ExternalSecretRolloutStatus `json:"rollout,omitempty"`
+
+	// TargetHash is the hash of the target Secret written by the last sync, used to detect drift.
+	// +optional
+	TargetHash string `json:"targetHash,omitempty"`
+}
+
+// ExternalSecretRolloutStatus describes the last restart of the workloads.
+type ExternalSecretRolloutStatus struct {
+	// DataHash is the hash of the data of the target Secret the workloads were last restarted for.
+	DataHash string `json:"dataHash"`
+
+	// LastRolloutTime is the time the workloads were last restarted.
+	// It is not set until the data changes for the first time.
+	// +optional
+	LastRolloutTime *metav1.Time `json:"lastRolloutTime,omitempty"`
+}
+
+// ExternalSecretHistoryVersion describes a rendered version of the target Secret.
+type ExternalSecretHistoryVersion struct {
+	// Version is incremented every time the rendered data of the target changes.
+	Version int64 `json:"version"`
+
+	// SecretName is the name of the immutable Secret holding the data of this version.
+	SecretName string `json:"secretName"`
+
+	// DataHash is the hash of the data of this version.
+	DataHash string `json:"dataHash"`
+
+	// CreationTime is the time this version was recorded.
+	CreationTime metav1.Time `json:"creationTime"`
+}
+
+// +kubebuilder:validation:Enum=Create;Update;Delete;None
+type ExternalSecretDryRunAction string
+
+const (
+	ExternalSecretDryRunActionCreate ExternalSecretDryRunAction = "Create"
+	ExternalSecretDryRunActionUpdate ExternalSecretDryRunAction = "Update"
+	ExternalSecretDryRunActionDelete ExternalSecretDryRunAction = "Delete"
+	ExternalSecretDryRunActionNone   ExternalSecretDryRunAction = "None"
+)
+
+// +kubebuilder:validation:Enum=Added;Removed;Changed
+type ExternalSecretDryRunKeyChange string
+
+const (
+	ExternalSecretDryRunKeyAdded   ExternalSecretDryRunKeyChange = "Added"
+	ExternalSecretDryRunKeyRemoved ExternalSecretDryRunKeyChange = "Removed"
+	ExternalSecretDryRunKeyChanged ExternalSecretDryRunKeyChange = "Changed"
+)
+
+// ExternalSecretDryRunStatus describes the changes a dry-run would have applied to the target.
+type ExternalSecretDryRunStatus struct {
+	// PlanTime is the time the changes were computed.
+	PlanTime metav1.Time `json:"planTime"`
+
+	// Action is the action that would be taken on the target.
+	Action ExternalSecretDryRunAction `json:"action"`
+
+	// Keys lists the data keys that would be added, removed or changed.
+	// +optional
+	Keys []ExternalSecretDryRunKey `json:"keys,omitempty"`
+
+	// Labels the target would have.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Annotations the target would have.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// ExternalSecretDryRunKey describes a change to a single data key.
+// Values are never included, only their keyed hashes.
+type ExternalSecretDryRunKey struct {
+	Key    string                        `json:"key"`
+	Change ExternalSecretDryRunKeyChange `json:"change"`
+
+	// Hash of the value the key would have, keyed by a secret of the controller and the UID of the ExternalSecret.
+	// Empty for removed keys.
+	// +optional
+	Hash string `json:"hash,omitempty"`
+}
+
+// ExternalSecretKeyStatus describes the sync of an entry of spec.data or spec.dataFrom.
+type ExternalSecretKeyStatus struct {
+	// Path is the location of the entry in the spec, e.g. spec.data[0] or spec.dataFrom[1].
+	Path string `json:"path"`
+
+	// SecretKey is the key of the entry in the target. Not set for spec.dataFrom.
+	// +optional
+	SecretKey string `json:"secretKey,omitempty"`
+
+	// StoreRef is the store the entry was fetched from.
+	// +optional
+	StoreRef *SecretStoreRef `json:"storeRef,omitempty"`
+
+	// GeneratorRef is the generator the entry was generated by.
+	// +optional
+	GeneratorRef *GeneratorRef `json:"generatorRef,omitempty"`
+
+	// RemoteKey is the key of the remote secret.
+	// +optional
+	RemoteKey string `json:"remoteKey,omitempty"`
+
+	// Version is the version of the remote secret, if known.
+	// It is the version returned by the provider, or the version set in the remoteRef.
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// LastModified is the time the version of the remote secret was created, if the provider returns it.
+	// +optional
+	LastModified *metav1.Time `json:"lastModified,omitempty"`
+
+	// ExpiresAt is the time the entry expires, if known. It is the expiry returned by the provider,
+	// or the earliest expiry of the X.509 certificates in the values of the entry.
+	// +optional
+	ExpiresAt *metav1.Time `json:"expiresAt,omitempty"`
+
+	// LastSyncTime is the time the entry was last fetched successfully.
+	// +optional
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+
+	// Error is the error of the last attempt to fetch the entry, empty if it succeeded.
+	// +optional
+	Error string `json:"error,omitempty"`
+}
+
+// ExternalSecretMissingKey describes an optional entry whose remote secret did not exist.
+type ExternalSecretMissingKey struct {
+	// Path is the location of the entry in the spec, e.g. spec.data[0] or spec.dataFrom[1].
+	Path string `json:"path"`
+
+	// RemoteKey is the key which could not be found at the provider.
+	// +optional
+	RemoteKey string `json:"remoteKey,omitempty"`
+
+	// Defaulted is true if the default value of the entry was used instead.
+	// +optional
+	Defaulted bool `json:"defaulted,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -578,6 +933,27 @@ const (
 
 	// LabelOwner points to the owning ExternalSecret resource when CreationPolicy=Owner.
 	LabelOwner = "reconcile.external-secrets.io/created-by"
+
+	// LabelHistoryOf points to the ExternalSecret resource a history Secret belongs to.
+	LabelHistoryOf = "reconcile.external-secrets.io/history-of"
+
+	// AnnotationHistoryVersion is set on history Secrets to the version they hold.
+	AnnotationHistoryVersion = "reconcile.external-secrets.io/history-version"
+
+	// AnnotationHistoryType is set on history Secrets to the type of the target Secret.
+	// History Secrets are always Opaque, so no other controller acts on them.
+	AnnotationHistoryType = "reconcile.external-secrets.io/history-type"
+
+	// AnnotationPinnedVersion pins the target Secret to a version of its history.
+	// While set, the provider is not queried and the data of that version is applied instead.
+	AnnotationPinnedVersion = "external-secrets.io/pinned-version"
+
+	// AnnotationRestartedAt is set on the pod template of workloads when they are restarted by spec.rollout.
+	AnnotationRestartedAt = "external-secrets.io/restarted-at"
+
+	// AnnotationForceSync refreshes an ExternalSecret whenever its value changes.
+	// It is set by users and by change notifications.
+	AnnotationForceSync = "force-sync"
 )
 
 // +kubebuilder:object:root=true
@@ -18,7 +18,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"regexp"
 
+	"github.com/google/cel-go/cel"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 )
@@ -48,6 +50,10 @@ func validateExternalSecret(obj runtime.Object) (admission.Warnings, error) {
 		errs = errors.Join(errs, err)
 	}
 
+	if err := validateManifest(es); err != nil {
+		errs = errors.Join(errs, err)
+	}
+
 	if len(es.Spec.Data) == 0 && len(es.Spec.DataFrom) == 0 {
 		errs = errors.Join(errs, errors.New("either data or dataFrom should be specified"))
 	}
@@ -66,10 +72,118 @@ func validateExternalSecret(obj runtime.Object) (admission.Warnings, error) {
 		}
 	}
 
+	if err := validateOptionalDefaults(es); err != nil {
+		errs = errors.Join(errs, err)
+	}
+
+	if err := validateRollout(es); err != nil {
+		errs = errors.Join(errs, err)
+	}
+
+	if err := validateValidationRules(es); err != nil {
+		errs = errors.Join(errs, err)
+	}
+
+	if err := validateFallbackStores(es); err != nil {
+		errs = errors.Join(errs, err)
+	}
+
 	errs = validateDuplicateKeys(es, errs)
 	return nil, errs
 }
 
+func validateOptionalDefaults(es *ExternalSecret) error {
+	var errs error
+	for i, data := range es.Spec.Data {
+		if data.Default != nil && !data.Optional {
+			errs = errors.Join(errs, fmt.Errorf("spec.data[%d]: default can only be set if optional is true", i))
+		}
+	}
+	for i, ref := range es.Spec.DataFrom {
+		if len(ref.Default) > 0 && !ref.Optional {
+			errs = errors.Join(errs, fmt.Errorf("spec.dataFrom[%d]: default can only be set if optional is true", i))
+		}
+	}
+
+	return errs
+}
+
+func validateRollout(es *ExternalSecret) error {
+	rollout := es.Spec.Rollout
+	if rollout == nil {
+		return nil
+	}
+
+	var errs error
+	if len(rollout.Workloads) == 0 && rollout.Selector == nil {
+		errs = errors.Join(errs, errors.New("rollout requires workloads or a selector"))
+	}
+	if es.Spec.Target.Manifest != nil {
+		errs = errors.Join(errs, fmt.Errorf("rollout is not supported for kind %s", es.Spec.Target.Manifest.Kind))
+	}
+
+	return errs
+}
+
+func validateFallbackStores(es *ExternalSecret) error {
+	if len(es.Spec.FallbackStoreRefs) == 0 {
+		return nil
+	}
+	if es.Spec.SecretStoreRef.Name == "" {
+		return errors.New("fallbackStoreRefs require secretStoreRef to be set")
+	}
+
+	var errs error
+	seen := map[SecretStoreRef]bool{normalizeStoreRef(es.Spec.SecretStoreRef): true}
+	for i, ref := range es.Spec.FallbackStoreRefs {
+		if seen[normalizeStoreRef(ref)] {
+			errs = errors.Join(errs, fmt.Errorf("spec.fallbackStoreRefs[%d]: duplicate store %q", i, ref.Name))
+		}
+		seen[normalizeStoreRef(ref)] = true
+	}
+
+	return errs
+}
+
+func normalizeStoreRef(ref SecretStoreRef) SecretStoreRef {
+	if ref.Kind == "" {
+		ref.Kind = SecretStoreKind
+	}
+	return ref
+}
+
+// validateValidationRules only checks the syntax of expressions,
+// as the functions and variables available to them are declared by the controller.
+func validateValidationRules(es *ExternalSecret) error {
+	validation := es.Spec.Target.Validation
+	if validation == nil {
+		return nil
+	}
+
+	env, err := cel.NewEnv()
+	if err != nil {
+		return err
+	}
+	var errs error
+	for i, rule := range validation.Rules {
+		if (rule.Regex == "") == (rule.Expression == "") {
+			errs = errors.Join(errs, fmt.Errorf("spec.target.validation.rules[%d]: exactly one of regex or expression must be set", i))
+			continue
+		}
+		if rule.Regex != "" {
+			if _, err := regexp.Compile(rule.Regex); err != nil {
+				errs = errors.Join(errs, fmt.Errorf("spec.target.validation.rules[%d]: invalid regex: %w", i, err))
+			}
+			continue
+		}
+		if _, iss := env.Parse(rule.Expression); iss.Err() != nil {
+			errs = errors.Join(errs, fmt.Errorf("spec.target.validation.rules[%d]: invalid expression: %w", i, iss.Err()))
+		}
+	}
+
+	return errs
+}
+
 func validateSourceRef(ref ExternalSecretDataFromRemoteRef) error {
 	if ref.SourceRef != nil && ref.SourceRef.GeneratorRef == nil && ref.SourceRef.SecretStoreRef == nil {
 		return errors.New("generatorRef or storeRef must be set when using sourceRef in dataFrom")
@@ -109,6 +223,30 @@ func validatePolicies(es *ExternalSecret) error {
 	return errs
 }
 
+func validateManifest(es *ExternalSecret) error {
+	manifest := es.Spec.Target.Manifest
+	if manifest == nil {
+		return nil
+	}
+
+	var errs error
+	if manifest.APIVersion == "v1" && manifest.Kind == "Secret" {
+		errs = errors.Join(errs, errors.New("manifest must not be used for Secrets, remove target.manifest instead"))
+	}
+	isConfigMap := manifest.APIVersion == "v1" && manifest.Kind == "ConfigMap"
+	if !isConfigMap && manifest.Template == "" {
+		errs = errors.Join(errs, fmt.Errorf("manifest.template is required for kind %s", manifest.Kind))
+	}
+	if !isConfigMap && es.Spec.Target.Immutable {
+		errs = errors.Join(errs, fmt.Errorf("immutable is not supported for kind %s", manifest.Kind))
+	}
+	if es.Spec.Target.History != nil {
+		errs = errors.Join(errs, fmt.Errorf("history is not supported for kind %s", manifest.Kind))
+	}
+
+	return errs
+}
+
 func validateDuplicateKeys(es *ExternalSecret, errs error) error {
 	if es.Spec.Target.DeletionPolicy == DeletionPolicyRetain {
 		seenKeys := make(map[string]struct{})
@@ -18,6 +18,7 @@ import (
 	"testing"
 
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
 )
 
 const (
@@ -202,6 +203,214 @@ either data or dataFrom should be specified`,
 			},
 			expectedErr: "duplicate secretKey found: SERVICE_NAME",
 		},
+		{
+			name: "configmap manifest without template",
+			obj: &ExternalSecret{
+				Spec: ExternalSecretSpec{
+					Target: ExternalSecretTarget{
+						Manifest: &ExternalSecretTargetManifest{
+							APIVersion: "v1",
+							Kind:       "ConfigMap",
+						},
+						Immutable: true,
+					},
+					Data: []ExternalSecretData{
+						{},
+					},
+				},
+			},
+		},
+		{
+			name: "custom resource manifest without template",
+			obj: &ExternalSecret{
+				Spec: ExternalSecretSpec{
+					Target: ExternalSecretTarget{
+						Manifest: &ExternalSecretTargetManifest{
+							APIVersion: "argoproj.io/v1alpha1",
+							Kind:       "Application",
+						},
+						Immutable: true,
+					},
+					Data: []ExternalSecretData{
+						{},
+					},
+				},
+			},
+			expectedErr: "manifest.template is required for kind Application\nimmutable is not supported for kind Application",
+		},
+		{
+			name: "optional entries with defaults",
+			obj: &ExternalSecret{
+				Spec: ExternalSecretSpec{
+					Data: []ExternalSecretData{
+						{SecretKey: "a", Optional: true, Default: ptr.To("value")},
+					},
+					DataFrom: []ExternalSecretDataFromRemoteRef{
+						{
+							Extract:  &ExternalSecretDataRemoteRef{Key: "b"},
+							Optional: true,
+							Default:  map[string]string{"b": "value"},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "defaults without optional",
+			obj: &ExternalSecret{
+				Spec: ExternalSecretSpec{
+					Data: []ExternalSecretData{
+						{SecretKey: "a", Default: ptr.To("value")},
+					},
+					DataFrom: []ExternalSecretDataFromRemoteRef{
+						{
+							Extract: &ExternalSecretDataRemoteRef{Key: "b"},
+							Default: map[string]string{"b": "value"},
+						},
+					},
+				},
+			},
+			expectedErr: "spec.data[0]: default can only be set if optional is true\nspec.dataFrom[0]: default can only be set if optional is true",
+		},
+		{
+			name: "secret manifest",
+			obj: &ExternalSecret{
+				Spec: ExternalSecretSpec{
+					Target: ExternalSecretTarget{
+						Manifest: &ExternalSecretTargetManifest{
+							APIVersion: "v1",
+							Kind:       "Secret",
+							Template:   "type: Opaque",
+						},
+					},
+					Data: []ExternalSecretData{
+						{},
+					},
+				},
+			},
+			expectedErr: "manifest must not be used for Secrets, remove target.manifest instead",
+		},
+		{
+			name: "configmap manifest with history",
+			obj: &ExternalSecret{
+				Spec: ExternalSecretSpec{
+					Target: ExternalSecretTarget{
+						Manifest: &ExternalSecretTargetManifest{
+							APIVersion: "v1",
+							Kind:       "ConfigMap",
+						},
+						History: &ExternalSecretTargetHistory{
+							Limit: 3,
+						},
+					},
+					Data: []ExternalSecretData{
+						{},
+					},
+				},
+			},
+			expectedErr: "history is not supported for kind ConfigMap",
+		},
+		{
+			name: "rollout without workloads",
+			obj: &ExternalSecret{
+				Spec: ExternalSecretSpec{
+					Rollout: &ExternalSecretRollout{},
+					Data: []ExternalSecretData{
+						{},
+					},
+				},
+			},
+			expectedErr: "rollout requires workloads or a selector",
+		},
+		{
+			name: "rollout with workloads",
+			obj: &ExternalSecret{
+				Spec: ExternalSecretSpec{
+					Rollout: &ExternalSecretRollout{
+						Workloads: []ExternalSecretRolloutWorkload{
+							{Kind: "Deployment", Name: "app"},
+						},
+					},
+					Data: []ExternalSecretData{
+						{},
+					},
+				},
+			},
+		},
+		{
+			name: "invalid validation rules",
+			obj: &ExternalSecret{
+				Spec: ExternalSecretSpec{
+					Target: ExternalSecretTarget{
+						Validation: &ExternalSecretValidation{
+							Rules: []ExternalSecretValidationRule{
+								{Key: "foo"},
+								{Key: "foo", Regex: "("},
+								{Key: "foo", Expression: "isPEM(value"},
+								{Key: "foo", Regex: "^a$", Expression: "isPEM(value)"},
+							},
+						},
+					},
+					Data: []ExternalSecretData{
+						{},
+					},
+				},
+			},
+			expectedErr: "spec.target.validation.rules[0]: exactly one of regex or expression must be set\n" +
+				"spec.target.validation.rules[1]: invalid regex: error parsing regexp: missing closing ): `(`\n" +
+				"spec.target.validation.rules[2]: invalid expression: ERROR: <input>:1:12: Syntax error: missing ')' at '<EOF>'\n" +
+				" | isPEM(value\n" +
+				" | ...........^\n" +
+				"spec.target.validation.rules[3]: exactly one of regex or expression must be set",
+		},
+		{
+			name: "fallback stores without secretStoreRef",
+			obj: &ExternalSecret{
+				Spec: ExternalSecretSpec{
+					FallbackStoreRefs: []SecretStoreRef{
+						{Name: "secondary"},
+					},
+					Data: []ExternalSecretData{
+						{},
+					},
+				},
+			},
+			expectedErr: "fallbackStoreRefs require secretStoreRef to be set",
+		},
+		{
+			name: "duplicate fallback stores",
+			obj: &ExternalSecret{
+				Spec: ExternalSecretSpec{
+					SecretStoreRef: SecretStoreRef{Name: "primary"},
+					FallbackStoreRefs: []SecretStoreRef{
+						{Name: "primary", Kind: SecretStoreKind},
+						{Name: "primary", Kind: ClusterSecretStoreKind},
+					},
+					Data: []ExternalSecretData{
+						{},
+					},
+				},
+			},
+			expectedErr: "spec.fallbackStoreRefs[0]: duplicate store \"primary\"",
+		},
+		{
+			name: "validation rules",
+			obj: &ExternalSecret{
+				Spec: ExternalSecretSpec{
+					Target: ExternalSecretTarget{
+						Validation: &ExternalSecretValidation{
+							Rules: []ExternalSecretValidationRule{
+								{Key: "tls.crt", Expression: "certNotAfter(value) - now > duration('168h')"},
+								{Regex: "^[a-z]+$"},
+							},
+						},
+					},
+					Data: []ExternalSecretData{
+						{},
+					},
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
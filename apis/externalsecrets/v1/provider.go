@@ -16,6 +16,7 @@ package v1
 
 import (
 	"context"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -96,6 +97,104 @@ type SecretsClient interface {
 	Close(ctx context.Context) error
 }
 
+// +kubebuilder:object:root=false
+// +kubebuilder:object:generate:false
+// +k8s:deepcopy-gen:interfaces=nil
+// +k8s:deepcopy-gen=nil
+
+// BatchSecretsClient is implemented by clients which can fetch multiple secrets in a single request,
+// e.g. using a bulk endpoint of the provider.
+// It is used for the entries of spec.data, which are otherwise fetched one by one with GetSecret.
+type BatchSecretsClient interface {
+	SecretsClient
+
+	// GetSecrets returns the secrets of the given refs, in the order of refs.
+	// errs holds the errors of single refs with the same semantics as GetSecret,
+	// e.g. NoSecretError if the secret of a ref does not exist.
+	// err is returned if the secrets could not be fetched at all.
+	GetSecrets(ctx context.Context, refs []ExternalSecretDataRemoteRef) (secrets [][]byte, errs []error, err error)
+}
+
+// +kubebuilder:object:root=false
+// +kubebuilder:object:generate:false
+// +k8s:deepcopy-gen:interfaces=nil
+// +k8s:deepcopy-gen=nil
+
+// PoolableClient is implemented by clients which can be reused across reconciles
+// by the client pool of the controller. A pooled client is never used concurrently.
+type PoolableClient interface {
+	SecretsClient
+
+	// Reset is called before a pooled client is reused and must drop
+	// any state of the previous reconcile, e.g. cached responses.
+	Reset()
+}
+
+// +kubebuilder:object:root=false
+// +kubebuilder:object:generate:false
+// +k8s:deepcopy-gen:interfaces=nil
+// +k8s:deepcopy-gen=nil
+
+// MetadataSecretsClient is implemented by clients which can return the version metadata
+// of a secret along with its value, e.g. the version id and creation date of the version.
+type MetadataSecretsClient interface {
+	SecretsClient
+
+	// GetSecretWithMetadata returns a single secret with the same semantics as GetSecret,
+	// and the metadata of the version it was read from. The metadata may be nil if it is unknown.
+	GetSecretWithMetadata(ctx context.Context, ref ExternalSecretDataRemoteRef) ([]byte, *SecretMetadata, error)
+}
+
+// +kubebuilder:object:root=false
+// +kubebuilder:object:generate:false
+// +k8s:deepcopy-gen:interfaces=nil
+// +k8s:deepcopy-gen=nil
+
+// MetadataBatchSecretsClient is implemented by batch clients which can return the version metadata
+// of the secrets along with their values.
+type MetadataBatchSecretsClient interface {
+	BatchSecretsClient
+
+	// GetSecretsWithMetadata returns the secrets of the given refs with the same semantics as GetSecrets,
+	// and the metadata of the versions they were read from. The metadata of a ref may be nil if it is unknown.
+	GetSecretsWithMetadata(ctx context.Context, refs []ExternalSecretDataRemoteRef) (secrets [][]byte, metadata []*SecretMetadata, errs []error, err error)
+}
+
+// +kubebuilder:object:generate=false
+
+// SecretMetadata describes the version of a secret in the provider.
+type SecretMetadata struct {
+	// Version is the version of the secret in the provider, empty if the provider has no versions.
+	Version string
+	// LastModified is the time the version was created, zero if it is unknown.
+	LastModified time.Time
+	// Expires is the time the secret expires, zero if it does not expire or it is unknown.
+	Expires time.Time
+}
+
+// GetSecretWithMetadata returns a single secret and its metadata if the client implements
+// MetadataSecretsClient, otherwise the secret and nil metadata.
+func GetSecretWithMetadata(ctx context.Context, c SecretsClient, ref ExternalSecretDataRemoteRef) ([]byte, *SecretMetadata, error) {
+	if mc, ok := c.(MetadataSecretsClient); ok {
+		return mc.GetSecretWithMetadata(ctx, ref)
+	}
+	secret, err := c.GetSecret(ctx, ref)
+	return secret, nil, err
+}
+
+// GetSecretsWithMetadata returns the secrets of the given refs and their metadata if the client implements
+// MetadataBatchSecretsClient, otherwise the secrets and nil metadata.
+func GetSecretsWithMetadata(ctx context.Context, c BatchSecretsClient, refs []ExternalSecretDataRemoteRef) ([][]byte, []*SecretMetadata, []error, error) {
+	if mc, ok := c.(MetadataBatchSecretsClient); ok {
+		return mc.GetSecretsWithMetadata(ctx, refs)
+	}
+	secrets, errs, err := c.GetSecrets(ctx, refs)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return secrets, make([]*SecretMetadata, len(refs)), errs, nil
+}
+
 var NoSecretErr = NoSecretError{}
 
 // NoSecretError shall be returned when a GetSecret can not find the
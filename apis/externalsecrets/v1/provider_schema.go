@@ -97,6 +97,15 @@ func GetProvider(s GenericStore) (Provider, error) {
 	return f, nil
 }
 
+// GetProviderName returns the name of the provider configured in the store, e.g. "aws".
+func GetProviderName(s GenericStore) (string, error) {
+	spec := s.GetSpec()
+	if spec == nil {
+		return "", fmt.Errorf("no spec found in %#v", s)
+	}
+	return getProviderName(spec.Provider)
+}
+
 // getProviderName returns the name of the configured provider
 // or an error if the provider is not configured.
 func getProviderName(storeSpec *SecretStoreProvider) (string, error) {
@@ -37,6 +37,19 @@ type SecretStoreSpec struct {
 	// +optional
 	RefreshInterval int `json:"refreshInterval,omitempty"`
 
+	// Used to limit the rate of calls to the provider, shared by all ExternalSecrets and PushSecrets using this store.
+	// +optional
+	RateLimit *SecretStoreRateLimit `json:"rateLimit,omitempty"`
+
+	// Used to spread the periodic refreshes of ExternalSecrets using this store over time.
+	// +optional
+	RefreshBudget *SecretStoreRefreshBudget `json:"refreshBudget,omitempty"`
+
+	// Used to configure caching of provider responses across reconciles.
+	// Only takes effect if the controller runs with --enable-response-cache.
+	// +optional
+	ResponseCache *SecretStoreResponseCache `json:"responseCache,omitempty"`
+
 	// Used to constraint a ClusterSecretStore to specific namespaces. Relevant only to ClusterSecretStore
 	// +optional
 	Conditions []ClusterSecretStoreCondition `json:"conditions,omitempty"`
@@ -247,6 +260,42 @@ type CAProvider struct {
 	Namespace *string `json:"namespace,omitempty"`
 }
 
+// SecretStoreResponseCache configures how long responses of the provider are cached.
+type SecretStoreResponseCache struct {
+	// TTL of the cached responses. Defaults to the --response-cache-ttl of the controller.
+	// Set to 0s to disable caching for this store.
+	// +optional
+	TTL *metav1.Duration `json:"ttl,omitempty"`
+}
+
+// SecretStoreRateLimit limits the calls to the provider with a token bucket.
+// Identical reads which are in flight at the same time are coalesced into a single call,
+// also for stores without a rate limit.
+type SecretStoreRateLimit struct {
+	// QPS is the number of calls per second.
+	// +kubebuilder:validation:Minimum=1
+	QPS int32 `json:"qps"`
+
+	// Burst is the maximum number of calls exceeding QPS at once. Defaults to QPS.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Burst int32 `json:"burst,omitempty"`
+
+	// MaxWait is the maximum time a call waits for the rate limit.
+	// Calls which would wait longer fail, and the sync is retried later. Defaults to 10s.
+	// +optional
+	MaxWait *metav1.Duration `json:"maxWait,omitempty"`
+}
+
+// SecretStoreRefreshBudget limits the periodic refreshes of the ExternalSecrets whose spec.secretStoreRef is the store.
+// Refreshes exceeding the budget are postponed until the budget allows them, unlike calls exceeding the rate limit they don't fail.
+// Refreshes of new or changed ExternalSecrets are never postponed.
+type SecretStoreRefreshBudget struct {
+	// RefreshesPerMinute is the maximum number of periodic refreshes per minute.
+	// +kubebuilder:validation:Minimum=1
+	RefreshesPerMinute int32 `json:"refreshesPerMinute"`
+}
+
 type SecretStoreRetrySettings struct {
 	MaxRetries    *int32  `json:"maxRetries,omitempty"`
 	RetryInterval *string `json:"retryInterval,omitempty"`
@@ -294,6 +343,9 @@ type SecretStoreStatus struct {
 	Conditions []SecretStoreStatusCondition `json:"conditions,omitempty"`
 	// +optional
 	Capabilities SecretStoreCapabilities `json:"capabilities,omitempty"`
+	// ObservedGeneration is the generation of the store that was last validated.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -1261,6 +1261,11 @@ func (in *ExternalSecretData) DeepCopyInto(out *ExternalSecretData) {
 		*out = new(StoreSourceRef)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Default != nil {
+		in, out := &in.Default, &out.Default
+		*out = new(string)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalSecretData.
@@ -1298,6 +1303,13 @@ func (in *ExternalSecretDataFromRemoteRef) DeepCopyInto(out *ExternalSecretDataF
 		*out = new(StoreGeneratorSourceRef)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Default != nil {
+		in, out := &in.Default, &out.Default
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalSecretDataFromRemoteRef.
@@ -1325,6 +1337,56 @@ func (in *ExternalSecretDataRemoteRef) DeepCopy() *ExternalSecretDataRemoteRef {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalSecretDryRunKey) DeepCopyInto(out *ExternalSecretDryRunKey) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalSecretDryRunKey.
+func (in *ExternalSecretDryRunKey) DeepCopy() *ExternalSecretDryRunKey {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalSecretDryRunKey)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalSecretDryRunStatus) DeepCopyInto(out *ExternalSecretDryRunStatus) {
+	*out = *in
+	in.PlanTime.DeepCopyInto(&out.PlanTime)
+	if in.Keys != nil {
+		in, out := &in.Keys, &out.Keys
+		*out = make([]ExternalSecretDryRunKey, len(*in))
+		copy(*out, *in)
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalSecretDryRunStatus.
+func (in *ExternalSecretDryRunStatus) DeepCopy() *ExternalSecretDryRunStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalSecretDryRunStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ExternalSecretFind) DeepCopyInto(out *ExternalSecretFind) {
 	*out = *in
@@ -1357,6 +1419,59 @@ func (in *ExternalSecretFind) DeepCopy() *ExternalSecretFind {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalSecretHistoryVersion) DeepCopyInto(out *ExternalSecretHistoryVersion) {
+	*out = *in
+	in.CreationTime.DeepCopyInto(&out.CreationTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalSecretHistoryVersion.
+func (in *ExternalSecretHistoryVersion) DeepCopy() *ExternalSecretHistoryVersion {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalSecretHistoryVersion)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalSecretKeyStatus) DeepCopyInto(out *ExternalSecretKeyStatus) {
+	*out = *in
+	if in.StoreRef != nil {
+		in, out := &in.StoreRef, &out.StoreRef
+		*out = new(SecretStoreRef)
+		**out = **in
+	}
+	if in.GeneratorRef != nil {
+		in, out := &in.GeneratorRef, &out.GeneratorRef
+		*out = new(GeneratorRef)
+		**out = **in
+	}
+	if in.LastModified != nil {
+		in, out := &in.LastModified, &out.LastModified
+		*out = (*in).DeepCopy()
+	}
+	if in.ExpiresAt != nil {
+		in, out := &in.ExpiresAt, &out.ExpiresAt
+		*out = (*in).DeepCopy()
+	}
+	if in.LastSyncTime != nil {
+		in, out := &in.LastSyncTime, &out.LastSyncTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalSecretKeyStatus.
+func (in *ExternalSecretKeyStatus) DeepCopy() *ExternalSecretKeyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalSecretKeyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ExternalSecretList) DeepCopyInto(out *ExternalSecretList) {
 	*out = *in
@@ -1418,6 +1533,21 @@ func (in *ExternalSecretMetadata) DeepCopy() *ExternalSecretMetadata {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalSecretMissingKey) DeepCopyInto(out *ExternalSecretMissingKey) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalSecretMissingKey.
+func (in *ExternalSecretMissingKey) DeepCopy() *ExternalSecretMissingKey {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalSecretMissingKey)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ExternalSecretRewrite) DeepCopyInto(out *ExternalSecretRewrite) {
 	*out = *in
@@ -1498,10 +1628,79 @@ func (in *ExternalSecretRewriteTransform) DeepCopy() *ExternalSecretRewriteTrans
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalSecretRollout) DeepCopyInto(out *ExternalSecretRollout) {
+	*out = *in
+	if in.Workloads != nil {
+		in, out := &in.Workloads, &out.Workloads
+		*out = make([]ExternalSecretRolloutWorkload, len(*in))
+		copy(*out, *in)
+	}
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MinInterval != nil {
+		in, out := &in.MinInterval, &out.MinInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalSecretRollout.
+func (in *ExternalSecretRollout) DeepCopy() *ExternalSecretRollout {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalSecretRollout)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalSecretRolloutStatus) DeepCopyInto(out *ExternalSecretRolloutStatus) {
+	*out = *in
+	if in.LastRolloutTime != nil {
+		in, out := &in.LastRolloutTime, &out.LastRolloutTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalSecretRolloutStatus.
+func (in *ExternalSecretRolloutStatus) DeepCopy() *ExternalSecretRolloutStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalSecretRolloutStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalSecretRolloutWorkload) DeepCopyInto(out *ExternalSecretRolloutWorkload) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalSecretRolloutWorkload.
+func (in *ExternalSecretRolloutWorkload) DeepCopy() *ExternalSecretRolloutWorkload {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalSecretRolloutWorkload)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ExternalSecretSpec) DeepCopyInto(out *ExternalSecretSpec) {
 	*out = *in
 	out.SecretStoreRef = in.SecretStoreRef
+	if in.FallbackStoreRefs != nil {
+		in, out := &in.FallbackStoreRefs, &out.FallbackStoreRefs
+		*out = make([]SecretStoreRef, len(*in))
+		copy(*out, *in)
+	}
 	in.Target.DeepCopyInto(&out.Target)
 	if in.RefreshInterval != nil {
 		in, out := &in.RefreshInterval, &out.RefreshInterval
@@ -1522,6 +1721,11 @@ func (in *ExternalSecretSpec) DeepCopyInto(out *ExternalSecretSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(ExternalSecretRollout)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalSecretSpec.
@@ -1546,6 +1750,40 @@ func (in *ExternalSecretStatus) DeepCopyInto(out *ExternalSecretStatus) {
 		}
 	}
 	out.Binding = in.Binding
+	if in.MissingKeys != nil {
+		in, out := &in.MissingKeys, &out.MissingKeys
+		*out = make([]ExternalSecretMissingKey, len(*in))
+		copy(*out, *in)
+	}
+	if in.Keys != nil {
+		in, out := &in.Keys, &out.Keys
+		*out = make([]ExternalSecretKeyStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ActiveStoreRef != nil {
+		in, out := &in.ActiveStoreRef, &out.ActiveStoreRef
+		*out = new(SecretStoreRef)
+		**out = **in
+	}
+	if in.DryRun != nil {
+		in, out := &in.DryRun, &out.DryRun
+		*out = new(ExternalSecretDryRunStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.History != nil {
+		in, out := &in.History, &out.History
+		*out = make([]ExternalSecretHistoryVersion, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(ExternalSecretRolloutStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalSecretStatus.
@@ -1582,6 +1820,21 @@ func (in *ExternalSecretTarget) DeepCopyInto(out *ExternalSecretTarget) {
 		*out = new(ExternalSecretTemplate)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Manifest != nil {
+		in, out := &in.Manifest, &out.Manifest
+		*out = new(ExternalSecretTargetManifest)
+		**out = **in
+	}
+	if in.History != nil {
+		in, out := &in.History, &out.History
+		*out = new(ExternalSecretTargetHistory)
+		**out = **in
+	}
+	if in.Validation != nil {
+		in, out := &in.Validation, &out.Validation
+		*out = new(ExternalSecretValidation)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalSecretTarget.
@@ -1594,6 +1847,36 @@ func (in *ExternalSecretTarget) DeepCopy() *ExternalSecretTarget {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalSecretTargetHistory) DeepCopyInto(out *ExternalSecretTargetHistory) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalSecretTargetHistory.
+func (in *ExternalSecretTargetHistory) DeepCopy() *ExternalSecretTargetHistory {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalSecretTargetHistory)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalSecretTargetManifest) DeepCopyInto(out *ExternalSecretTargetManifest) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalSecretTargetManifest.
+func (in *ExternalSecretTargetManifest) DeepCopy() *ExternalSecretTargetManifest {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalSecretTargetManifest)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ExternalSecretTemplate) DeepCopyInto(out *ExternalSecretTemplate) {
 	*out = *in
@@ -1653,6 +1936,41 @@ func (in *ExternalSecretTemplateMetadata) DeepCopy() *ExternalSecretTemplateMeta
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalSecretValidation) DeepCopyInto(out *ExternalSecretValidation) {
+	*out = *in
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]ExternalSecretValidationRule, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalSecretValidation.
+func (in *ExternalSecretValidation) DeepCopy() *ExternalSecretValidation {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalSecretValidation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalSecretValidationRule) DeepCopyInto(out *ExternalSecretValidationRule) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalSecretValidationRule.
+func (in *ExternalSecretValidationRule) DeepCopy() *ExternalSecretValidationRule {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalSecretValidationRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ExternalSecretValidator) DeepCopyInto(out *ExternalSecretValidator) {
 	*out = *in
@@ -3129,6 +3447,26 @@ func (in *SecretStoreProvider) DeepCopy() *SecretStoreProvider {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretStoreRateLimit) DeepCopyInto(out *SecretStoreRateLimit) {
+	*out = *in
+	if in.MaxWait != nil {
+		in, out := &in.MaxWait, &out.MaxWait
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretStoreRateLimit.
+func (in *SecretStoreRateLimit) DeepCopy() *SecretStoreRateLimit {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretStoreRateLimit)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SecretStoreRef) DeepCopyInto(out *SecretStoreRef) {
 	*out = *in
@@ -3144,6 +3482,41 @@ func (in *SecretStoreRef) DeepCopy() *SecretStoreRef {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretStoreRefreshBudget) DeepCopyInto(out *SecretStoreRefreshBudget) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretStoreRefreshBudget.
+func (in *SecretStoreRefreshBudget) DeepCopy() *SecretStoreRefreshBudget {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretStoreRefreshBudget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretStoreResponseCache) DeepCopyInto(out *SecretStoreResponseCache) {
+	*out = *in
+	if in.TTL != nil {
+		in, out := &in.TTL, &out.TTL
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretStoreResponseCache.
+func (in *SecretStoreResponseCache) DeepCopy() *SecretStoreResponseCache {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretStoreResponseCache)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SecretStoreRetrySettings) DeepCopyInto(out *SecretStoreRetrySettings) {
 	*out = *in
@@ -3182,6 +3555,21 @@ func (in *SecretStoreSpec) DeepCopyInto(out *SecretStoreSpec) {
 		*out = new(SecretStoreRetrySettings)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.RateLimit != nil {
+		in, out := &in.RateLimit, &out.RateLimit
+		*out = new(SecretStoreRateLimit)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RefreshBudget != nil {
+		in, out := &in.RefreshBudget, &out.RefreshBudget
+		*out = new(SecretStoreRefreshBudget)
+		**out = **in
+	}
+	if in.ResponseCache != nil {
+		in, out := &in.ResponseCache, &out.ResponseCache
+		*out = new(SecretStoreResponseCache)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]ClusterSecretStoreCondition, len(*in))
@@ -25,6 +25,9 @@ import (
 const (
 	ReasonSynced  = "Synced"
 	ReasonErrored = "Errored"
+	ReasonDryRun  = "DryRun"
+	// ReasonConflict is used when a remote value was changed outside of the PushSecret.
+	ReasonConflict = "Conflict"
 )
 
 type PushSecretStoreRef struct {
@@ -46,12 +49,26 @@ type PushSecretStoreRef struct {
 	Kind string `json:"kind,omitempty"`
 }
 
-// +kubebuilder:validation:Enum=Replace;IfNotExists
+// +kubebuilder:validation:Enum=Replace;IfNotExists;IfChanged
 type PushSecretUpdatePolicy string
 
 const (
 	PushSecretUpdatePolicyReplace     PushSecretUpdatePolicy = "Replace"
 	PushSecretUpdatePolicyIfNotExists PushSecretUpdatePolicy = "IfNotExists"
+	// PushSecretUpdatePolicyIfChanged reads the remote value and only writes if it differs.
+	PushSecretUpdatePolicyIfChanged PushSecretUpdatePolicy = "IfChanged"
+)
+
+// +kubebuilder:validation:Enum=Overwrite;Stop;Adopt
+type PushSecretConflictPolicy string
+
+const (
+	// PushSecretConflictPolicyOverwrite replaces remote values that were changed outside of the PushSecret.
+	PushSecretConflictPolicyOverwrite PushSecretConflictPolicy = "Overwrite"
+	// PushSecretConflictPolicyStop leaves changed remote values untouched and sets the Conflict condition.
+	PushSecretConflictPolicyStop PushSecretConflictPolicy = "Stop"
+	// PushSecretConflictPolicyAdopt copies changed remote values back into the source Secret.
+	PushSecretConflictPolicyAdopt PushSecretConflictPolicy = "Adopt"
 )
 
 // +kubebuilder:validation:Enum=Delete;None
@@ -83,6 +100,13 @@ type PushSecretSpec struct {
 	// +optional
 	UpdatePolicy PushSecretUpdatePolicy `json:"updatePolicy,omitempty"`
 
+	// ConflictPolicy decides what happens to a remote value that was changed outside of the PushSecret
+	// since it was last written. Changes are detected by comparing the remote value with the hash
+	// recorded in status.syncedPushSecretHashes.
+	// +kubebuilder:default="Overwrite"
+	// +optional
+	ConflictPolicy PushSecretConflictPolicy `json:"conflictPolicy,omitempty"`
+
 	// Deletion Policy to handle Secrets in the provider.
 	// +kubebuilder:default="None"
 	// +optional
@@ -94,9 +118,19 @@ type PushSecretSpec struct {
 	// Secret Data that should be pushed to providers
 	Data []PushSecretData `json:"data,omitempty"`
 
+	// DataFrom pushes every key of the Secret, or the keys matching a regular expression,
+	// each to its own remote key.
+	// +optional
+	DataFrom []PushSecretDataFrom `json:"dataFrom,omitempty"`
+
 	// Template defines a blueprint for the created Secret resource.
 	// +optional
 	Template *esv1.ExternalSecretTemplate `json:"template,omitempty"`
+
+	// DryRun resolves and templates the source secrets without writing to or deleting from the providers.
+	// The remote keys that would be written or deleted are written to status.dryRun and emitted as an event.
+	// +optional
+	DryRun bool `json:"dryRun,omitempty"`
 }
 
 type PushSecretSecret struct {
@@ -113,6 +147,45 @@ type PushSecretSecret struct {
 	Selector *metav1.LabelSelector `json:"selector,omitempty"`
 }
 
+// PushSecretConfigMap selects ConfigMaps to push.
+// Their data and binaryData are pushed like the data of a Secret.
+type PushSecretConfigMap struct {
+	// Name of the ConfigMap.
+	// The ConfigMap must exist in the same namespace as the PushSecret manifest.
+	// +kubebuilder:validation:MinLength:=1
+	// +kubebuilder:validation:MaxLength:=253
+	// +kubebuilder:validation:Pattern:=^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Selector chooses ConfigMaps using a labelSelector.
+	// +optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+}
+
+// PushSecretResource reads fields of a Kubernetes object to push.
+// The object must exist in the same namespace as the PushSecret manifest,
+// and the controller must be allowed to get it.
+type PushSecretResource struct {
+	// APIVersion of the object, e.g. v1 or cert-manager.io/v1.
+	// +kubebuilder:validation:MinLength:=1
+	APIVersion string `json:"apiVersion"`
+
+	// Kind of the object, e.g. Service.
+	// +kubebuilder:validation:MinLength:=1
+	Kind string `json:"kind"`
+
+	// Name of the object.
+	// +kubebuilder:validation:MinLength:=1
+	// +kubebuilder:validation:MaxLength:=253
+	Name string `json:"name"`
+
+	// Fields maps the keys to push to JSONPath expressions evaluated against the object, e.g. {.spec.clusterIP}.
+	// Strings are pushed as they are, other values are encoded as JSON.
+	// +kubebuilder:validation:MinProperties=1
+	Fields map[string]string `json:"fields"`
+}
+
 // +kubebuilder:validation:MinProperties=1
 // +kubebuilder:validation:MaxProperties=1
 type PushSecretSelector struct {
@@ -123,6 +196,15 @@ type PushSecretSelector struct {
 	// Point to a generator to create a Secret.
 	// +optional
 	GeneratorRef *esv1.GeneratorRef `json:"generatorRef,omitempty"`
+
+	// Select ConfigMaps to Push.
+	// +optional
+	ConfigMap *PushSecretConfigMap `json:"configMap,omitempty"`
+
+	// Read fields of a namespaced Kubernetes object to Push.
+	// Requires the controller to run with --unsafe-allow-pushsecret-resources.
+	// +optional
+	Resource *PushSecretResource `json:"resource,omitempty"`
 }
 
 type PushSecretRemoteRef struct {
@@ -163,6 +245,68 @@ type PushSecretData struct {
 	ConversionStrategy PushSecretConversionStrategy `json:"conversionStrategy,omitempty"`
 }
 
+// PushSecretDataFrom pushes a set of keys of the Secret to the provider.
+type PushSecretDataFrom struct {
+	// Match selects the keys of the Secret to push. All keys are pushed if omitted.
+	// +optional
+	Match *PushSecretDataFromMatch `json:"match,omitempty"`
+	// Rewrite computes the remote key from the Secret key. The Secret key is used as is if omitted.
+	// Merge operations are not supported.
+	// +optional
+	Rewrite []esv1.ExternalSecretRewrite `json:"rewrite,omitempty"`
+	// Metadata is metadata attached to every pushed secret.
+	// The structure of metadata is provider specific, please look it up in the provider documentation.
+	// +optional
+	Metadata *apiextensionsv1.JSON `json:"metadata,omitempty"`
+	// +optional
+	// Used to define a conversion Strategy for the secret keys
+	// +kubebuilder:default="None"
+	ConversionStrategy PushSecretConversionStrategy `json:"conversionStrategy,omitempty"`
+	// Bundle pushes the selected keys as a single document instead of one remote key per Secret key.
+	// The rewritten keys are the properties of the document. Their values must be valid UTF-8.
+	// +optional
+	Bundle *PushSecretBundle `json:"bundle,omitempty"`
+}
+
+// +kubebuilder:validation:Enum=JSON;YAML;Dotenv
+type PushSecretBundleFormat string
+
+const (
+	PushSecretBundleFormatJSON   PushSecretBundleFormat = "JSON"
+	PushSecretBundleFormatYAML   PushSecretBundleFormat = "YAML"
+	PushSecretBundleFormatDotenv PushSecretBundleFormat = "Dotenv"
+)
+
+// +kubebuilder:validation:Enum=Replace;Merge
+type PushSecretBundleMergePolicy string
+
+const (
+	PushSecretBundleMergePolicyReplace PushSecretBundleMergePolicy = "Replace"
+	PushSecretBundleMergePolicyMerge   PushSecretBundleMergePolicy = "Merge"
+)
+
+// PushSecretBundle serializes a set of Secret keys into a single remote value.
+type PushSecretBundle struct {
+	// RemoteRef is the remote secret, and optionally the property, the document is pushed to.
+	RemoteRef PushSecretRemoteRef `json:"remoteRef"`
+	// Format of the document.
+	// +kubebuilder:default="JSON"
+	// +optional
+	Format PushSecretBundleFormat `json:"format,omitempty"`
+	// MergePolicy defines how the document is combined with the existing remote document.
+	// Replace overwrites the remote document, Merge only overwrites the properties of the bundle and keeps the others.
+	// +kubebuilder:default="Replace"
+	// +optional
+	MergePolicy PushSecretBundleMergePolicy `json:"mergePolicy,omitempty"`
+}
+
+// PushSecretDataFromMatch selects keys of the Secret.
+type PushSecretDataFromMatch struct {
+	// RegExp matches the Secret keys to push.
+	// +optional
+	RegExp string `json:"regexp,omitempty"`
+}
+
 func (d PushSecretData) GetMetadata() *apiextensionsv1.JSON {
 	return d.Metadata
 }
@@ -184,6 +328,8 @@ type PushSecretConditionType string
 
 const (
 	PushSecretReady PushSecretConditionType = "Ready"
+	// PushSecretConflict is true while remote values that were changed outside of the PushSecret are not overwritten.
+	PushSecretConflict PushSecretConditionType = "Conflict"
 )
 
 // PushSecretStatusCondition indicates the status of the PushSecret.
@@ -216,8 +362,55 @@ type PushSecretStatus struct {
 	// Matches secret stores to PushSecretData that was stored to that secret store.
 	// +optional
 	SyncedPushSecrets SyncedPushSecretsMap `json:"syncedPushSecrets,omitempty"`
+	// SyncedPushSecretHashes holds the hash of the value last written to each remote ref,
+	// keyed like SyncedPushSecrets. It is used to detect remote values changed outside of the PushSecret.
+	// The hashes are keyed by a secret of the controller and the UID of the PushSecret,
+	// so they cannot be used to guess the values.
+	// +optional
+	SyncedPushSecretHashes map[string]map[string]string `json:"syncedPushSecretHashes,omitempty"`
 	// +optional
 	Conditions []PushSecretStatusCondition `json:"conditions,omitempty"`
+	// DryRun holds the changes the last dry-run would have applied to the providers.
+	// +optional
+	DryRun *PushSecretDryRunStatus `json:"dryRun,omitempty"`
+}
+
+// +kubebuilder:validation:Enum=Write;Skip;Delete
+type PushSecretDryRunAction string
+
+const (
+	PushSecretDryRunActionWrite  PushSecretDryRunAction = "Write"
+	PushSecretDryRunActionSkip   PushSecretDryRunAction = "Skip"
+	PushSecretDryRunActionDelete PushSecretDryRunAction = "Delete"
+)
+
+// PushSecretDryRunStatus describes the changes a dry-run would have applied to the providers.
+type PushSecretDryRunStatus struct {
+	// PlanTime is the time the changes were computed.
+	PlanTime metav1.Time `json:"planTime"`
+
+	// Changes lists the remote keys that would be written, skipped or deleted.
+	// +optional
+	Changes []PushSecretDryRunChange `json:"changes,omitempty"`
+}
+
+// PushSecretDryRunChange describes a change to a single remote key.
+// Values are never included, only their keyed hashes.
+type PushSecretDryRunChange struct {
+	// Store is the store the change applies to, in the form Kind/Name.
+	Store string `json:"store"`
+
+	RemoteKey string `json:"remoteKey"`
+
+	// +optional
+	Property string `json:"property,omitempty"`
+
+	Action PushSecretDryRunAction `json:"action"`
+
+	// Hash of the value that would be written, keyed by a secret of the controller and the UID of the PushSecret.
+	// Empty for deleted keys.
+	// +optional
+	Hash string `json:"hash,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -216,6 +216,42 @@ func (in *PushSecret) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PushSecretBundle) DeepCopyInto(out *PushSecretBundle) {
+	*out = *in
+	out.RemoteRef = in.RemoteRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PushSecretBundle.
+func (in *PushSecretBundle) DeepCopy() *PushSecretBundle {
+	if in == nil {
+		return nil
+	}
+	out := new(PushSecretBundle)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PushSecretConfigMap) DeepCopyInto(out *PushSecretConfigMap) {
+	*out = *in
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PushSecretConfigMap.
+func (in *PushSecretConfigMap) DeepCopy() *PushSecretConfigMap {
+	if in == nil {
+		return nil
+	}
+	out := new(PushSecretConfigMap)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PushSecretData) DeepCopyInto(out *PushSecretData) {
 	*out = *in
@@ -237,6 +273,94 @@ func (in *PushSecretData) DeepCopy() *PushSecretData {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PushSecretDataFrom) DeepCopyInto(out *PushSecretDataFrom) {
+	*out = *in
+	if in.Match != nil {
+		in, out := &in.Match, &out.Match
+		*out = new(PushSecretDataFromMatch)
+		**out = **in
+	}
+	if in.Rewrite != nil {
+		in, out := &in.Rewrite, &out.Rewrite
+		*out = make([]externalsecretsv1.ExternalSecretRewrite, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Metadata != nil {
+		in, out := &in.Metadata, &out.Metadata
+		*out = new(apiextensionsv1.JSON)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Bundle != nil {
+		in, out := &in.Bundle, &out.Bundle
+		*out = new(PushSecretBundle)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PushSecretDataFrom.
+func (in *PushSecretDataFrom) DeepCopy() *PushSecretDataFrom {
+	if in == nil {
+		return nil
+	}
+	out := new(PushSecretDataFrom)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PushSecretDataFromMatch) DeepCopyInto(out *PushSecretDataFromMatch) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PushSecretDataFromMatch.
+func (in *PushSecretDataFromMatch) DeepCopy() *PushSecretDataFromMatch {
+	if in == nil {
+		return nil
+	}
+	out := new(PushSecretDataFromMatch)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PushSecretDryRunChange) DeepCopyInto(out *PushSecretDryRunChange) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PushSecretDryRunChange.
+func (in *PushSecretDryRunChange) DeepCopy() *PushSecretDryRunChange {
+	if in == nil {
+		return nil
+	}
+	out := new(PushSecretDryRunChange)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PushSecretDryRunStatus) DeepCopyInto(out *PushSecretDryRunStatus) {
+	*out = *in
+	in.PlanTime.DeepCopyInto(&out.PlanTime)
+	if in.Changes != nil {
+		in, out := &in.Changes, &out.Changes
+		*out = make([]PushSecretDryRunChange, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PushSecretDryRunStatus.
+func (in *PushSecretDryRunStatus) DeepCopy() *PushSecretDryRunStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PushSecretDryRunStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PushSecretList) DeepCopyInto(out *PushSecretList) {
 	*out = *in
@@ -329,6 +453,28 @@ func (in *PushSecretRemoteRef) DeepCopy() *PushSecretRemoteRef {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PushSecretResource) DeepCopyInto(out *PushSecretResource) {
+	*out = *in
+	if in.Fields != nil {
+		in, out := &in.Fields, &out.Fields
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PushSecretResource.
+func (in *PushSecretResource) DeepCopy() *PushSecretResource {
+	if in == nil {
+		return nil
+	}
+	out := new(PushSecretResource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PushSecretSecret) DeepCopyInto(out *PushSecretSecret) {
 	*out = *in
@@ -362,6 +508,16 @@ func (in *PushSecretSelector) DeepCopyInto(out *PushSecretSelector) {
 		*out = new(externalsecretsv1.GeneratorRef)
 		**out = **in
 	}
+	if in.ConfigMap != nil {
+		in, out := &in.ConfigMap, &out.ConfigMap
+		*out = new(PushSecretConfigMap)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Resource != nil {
+		in, out := &in.Resource, &out.Resource
+		*out = new(PushSecretResource)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PushSecretSelector.
@@ -397,6 +553,13 @@ func (in *PushSecretSpec) DeepCopyInto(out *PushSecretSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.DataFrom != nil {
+		in, out := &in.DataFrom, &out.DataFrom
+		*out = make([]PushSecretDataFrom, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.Template != nil {
 		in, out := &in.Template, &out.Template
 		*out = new(externalsecretsv1.ExternalSecretTemplate)
@@ -436,6 +599,24 @@ func (in *PushSecretStatus) DeepCopyInto(out *PushSecretStatus) {
 			(*out)[key] = outVal
 		}
 	}
+	if in.SyncedPushSecretHashes != nil {
+		in, out := &in.SyncedPushSecretHashes, &out.SyncedPushSecretHashes
+		*out = make(map[string]map[string]string, len(*in))
+		for key, val := range *in {
+			var outVal map[string]string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				inVal := (*in)[key]
+				in, out := &inVal, &outVal
+				*out = make(map[string]string, len(*in))
+				for key, val := range *in {
+					(*out)[key] = val
+				}
+			}
+			(*out)[key] = outVal
+		}
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]PushSecretStatusCondition, len(*in))
@@ -443,6 +624,11 @@ func (in *PushSecretStatus) DeepCopyInto(out *PushSecretStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.DryRun != nil {
+		in, out := &in.DryRun, &out.DryRun
+		*out = new(PushSecretDryRunStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PushSecretStatus.
@@ -16,6 +16,7 @@ package v1alpha1
 
 import (
 	"context"
+	"time"
 
 	apiextensions "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -52,3 +53,14 @@ type Generator interface {
 }
 
 type GeneratorProviderState *apiextensions.JSON
+
+// +kubebuilder:object:root=false
+// +kubebuilder:object:generate:false
+// +k8s:deepcopy-gen:interfaces=nil
+// +k8s:deepcopy-gen=nil
+
+// ExpiringGenerator is implemented by generators whose values expire, e.g. access tokens.
+type ExpiringGenerator interface {
+	// Expiry returns the expiry of the values returned by Generate, it is zero if it is not known.
+	Expiry(data map[string][]byte) time.Time
+}
@@ -17,15 +17,21 @@ limitations under the License.
 package controller
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
 	v1 "k8s.io/api/core/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -51,7 +57,10 @@ import (
 	"github.com/external-secrets/external-secrets/pkg/controllers/secretstore"
 	"github.com/external-secrets/external-secrets/pkg/controllers/secretstore/cssmetrics"
 	"github.com/external-secrets/external-secrets/pkg/controllers/secretstore/ssmetrics"
+	ctrlutil "github.com/external-secrets/external-secrets/pkg/controllers/util"
 	"github.com/external-secrets/external-secrets/pkg/feature"
+	"github.com/external-secrets/external-secrets/pkg/notifications"
+	"github.com/external-secrets/external-secrets/pkg/sharding"
 
 	// To allow using gcp auth.
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
@@ -84,8 +93,30 @@ var (
 	enablePushSecretReconciler            bool
 	enableFloodGate                       bool
 	enableGeneratorState                  bool
+	allowGenericTargets                   bool
+	allowPushSecretResources              bool
+	enableRolloutTriggers                 bool
+	notificationsAddr                     string
+	notificationsTokenFile                string
+	notificationsAllowUnauthenticated     bool
+	enableResponseCache                   bool
+	responseCacheSize                     int
+	responseCacheTTL                      time.Duration
+	enableClientPool                      bool
+	clientPoolSize                        int
+	clientPoolMaxAge                      time.Duration
+	shards                                int
+	shardKey                              string
+	shardLeaseNamespace                   string
+	hashKeySecret                         string
+	hashKeyNamespace                      string
+	shardLeaseDuration                    time.Duration
+	shardRenewInterval                    time.Duration
 	enableExtendedMetricLabels            bool
 	storeRequeueInterval                  time.Duration
+	refreshJitter                         int
+	startupStagger                        time.Duration
+	expiryWarningWindow                   time.Duration
 	serviceName, serviceNamespace         string
 	secretName, secretNamespace           string
 	crdNames                              []string
@@ -98,6 +129,8 @@ var (
 
 const (
 	errCreateController = "unable to create controller"
+
+	inClusterNamespaceFile = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
 )
 
 func init() {
@@ -179,6 +212,11 @@ var rootCmd = &cobra.Command{
 			}
 		}
 
+		schedule, err := ctrlutil.NewSchedule(refreshJitter, startupStagger)
+		if err != nil {
+			setupLog.Error(err, "unable to set up refresh scheduling")
+			os.Exit(1)
+		}
 		ssmetrics.SetUpMetrics()
 		if err = (&secretstore.StoreReconciler{
 			Client:          mgr.GetClient(),
@@ -186,6 +224,7 @@ var rootCmd = &cobra.Command{
 			Scheme:          mgr.GetScheme(),
 			ControllerClass: controllerClass,
 			RequeueInterval: storeRequeueInterval,
+			Schedule:        schedule,
 		}).SetupWithManager(mgr, controller.Options{
 			MaxConcurrentReconciles: concurrent,
 			RateLimiter:             ctrlcommon.BuildRateLimiter(),
@@ -201,6 +240,7 @@ var rootCmd = &cobra.Command{
 				Scheme:          mgr.GetScheme(),
 				ControllerClass: controllerClass,
 				RequeueInterval: storeRequeueInterval,
+				Schedule:        schedule,
 			}).SetupWithManager(mgr, controller.Options{
 				MaxConcurrentReconciles: concurrent,
 				RateLimiter:             ctrlcommon.BuildRateLimiter(),
@@ -221,6 +261,49 @@ var rootCmd = &cobra.Command{
 			setupLog.Error(err, errCreateController, "controller", "GeneratorState")
 			os.Exit(1)
 		}
+		var notificationDispatcher *notifications.Dispatcher
+		var responseCache *secretstore.ResponseCache
+		if enableResponseCache {
+			responseCache, err = secretstore.NewResponseCache(responseCacheSize, responseCacheTTL)
+			if err != nil {
+				setupLog.Error(err, "unable to create response cache")
+				os.Exit(1)
+			}
+		}
+		if notificationsAddr != "" {
+			notificationDispatcher, err = setupNotifications(mgr, responseCache)
+			if err != nil {
+				setupLog.Error(err, "unable to set up notifications server")
+				os.Exit(1)
+			}
+		}
+		var clientPool *secretstore.ClientPool
+		if enableClientPool {
+			clientPool, err = secretstore.NewClientPool(clientPoolSize, clientPoolMaxAge)
+			if err != nil {
+				setupLog.Error(err, "unable to create client pool")
+				os.Exit(1)
+			}
+		}
+		hashKey, err := setupHashKey(mgr)
+		if err != nil {
+			setupLog.Error(err, "unable to load hash key")
+			os.Exit(1)
+		}
+		esOpts := controller.Options{
+			MaxConcurrentReconciles: concurrent,
+			RateLimiter:             ctrlcommon.BuildRateLimiter(),
+		}
+		var sharder *sharding.Sharder
+		if shards > 0 {
+			sharder, err = setupSharding(mgr)
+			if err != nil {
+				setupLog.Error(err, "unable to set up sharding")
+				os.Exit(1)
+			}
+			// every replica reconciles the ExternalSecrets of its shards, not only the leader
+			esOpts.NeedLeaderElection = ptr.To(false)
+		}
 		if err = (&externalsecret.Reconciler{
 			Client:                    mgr.GetClient(),
 			SecretClient:              secretClient,
@@ -232,10 +315,16 @@ var rootCmd = &cobra.Command{
 			ClusterSecretStoreEnabled: enableClusterStoreReconciler,
 			EnableFloodGate:           enableFloodGate,
 			EnableGeneratorState:      enableGeneratorState,
-		}).SetupWithManager(mgr, controller.Options{
-			MaxConcurrentReconciles: concurrent,
-			RateLimiter:             ctrlcommon.BuildRateLimiter(),
-		}); err != nil {
+			AllowGenericTargets:       allowGenericTargets,
+			EnableRolloutTriggers:     enableRolloutTriggers,
+			Notifications:             notificationDispatcher,
+			ResponseCache:             responseCache,
+			ClientPool:                clientPool,
+			Shards:                    sharder,
+			Schedule:                  schedule,
+			ExpiryWarningWindow:       expiryWarningWindow,
+			HashKey:                   hashKey,
+		}).SetupWithManager(mgr, esOpts); err != nil {
 			setupLog.Error(err, errCreateController, "controller", "ExternalSecret")
 			os.Exit(1)
 		}
@@ -248,6 +337,10 @@ var rootCmd = &cobra.Command{
 				ControllerClass: controllerClass,
 				RestConfig:      mgr.GetConfig(),
 				RequeueInterval: time.Hour,
+				ClientPool:      clientPool,
+
+				AllowResourceSelector: allowPushSecretResources,
+				HashKey:               hashKey,
 			}).SetupWithManager(mgr, controller.Options{
 				MaxConcurrentReconciles: concurrent,
 				RateLimiter:             ctrlcommon.BuildRateLimiter(),
@@ -314,6 +407,80 @@ func Execute() {
 	cobra.CheckErr(rootCmd.Execute())
 }
 
+// setupNotifications adds the server receiving change notifications from providers to the manager.
+// Notified keys are evicted from the response cache.
+func setupNotifications(mgr ctrl.Manager, responseCache *secretstore.ResponseCache) (*notifications.Dispatcher, error) {
+	var token string
+	if notificationsTokenFile != "" {
+		b, err := os.ReadFile(notificationsTokenFile)
+		if err != nil {
+			return nil, err
+		}
+		token = strings.TrimSpace(string(b))
+	}
+	// anyone who can reach the server could force refreshes of all ExternalSecrets otherwise
+	if token == "" && !notificationsAllowUnauthenticated {
+		return nil, errors.New("notifications require a token, set --notifications-token-file or --notifications-allow-unauthenticated")
+	}
+	log := ctrl.Log.WithName("notifications")
+	dispatcher := notifications.NewDispatcher(mgr.GetClient(), log).WithResponseCache(responseCache)
+	err := mgr.Add(&notifications.Server{
+		Addr:       notificationsAddr,
+		Token:      token,
+		Dispatcher: dispatcher,
+		Sources:    notifications.DefaultSources(),
+		Log:        log,
+
+		AllowUnauthenticated: notificationsAllowUnauthenticated,
+	})
+	return dispatcher, err
+}
+
+// setupHashKey loads the key of the hashes of secret values written to the status of ExternalSecrets and PushSecrets.
+func setupHashKey(mgr ctrl.Manager) ([]byte, error) {
+	namespace := hashKeyNamespace
+	if namespace == "" {
+		ns, err := os.ReadFile(inClusterNamespaceFile)
+		if err != nil {
+			return nil, fmt.Errorf("could not determine the namespace of the hash key, set --hash-key-namespace: %w", err)
+		}
+		namespace = strings.TrimSpace(string(ns))
+	}
+	// the caches are not started yet
+	c, err := client.New(mgr.GetConfig(), client.Options{Scheme: mgr.GetScheme()})
+	if err != nil {
+		return nil, err
+	}
+	return ctrlutil.LoadHashKey(context.Background(), c, c, types.NamespacedName{Name: hashKeySecret, Namespace: namespace})
+}
+
+// setupSharding adds the Sharder holding the shards of this replica to the manager.
+// Replicas of different controller classes share their own set of shards.
+func setupSharding(mgr ctrl.Manager) (*sharding.Sharder, error) {
+	identity, err := os.Hostname()
+	if err != nil {
+		return nil, err
+	}
+	// leases are read right before they are updated, so they must not be cached
+	leaseClient, err := client.New(mgr.GetConfig(), client.Options{Scheme: mgr.GetScheme()})
+	if err != nil {
+		return nil, err
+	}
+	sharder, err := sharding.New(sharding.Options{
+		Shards:        shards,
+		Key:           sharding.Key(shardKey),
+		Group:         "external-secrets-" + controllerClass,
+		Namespace:     shardLeaseNamespace,
+		Identity:      identity,
+		LeaseDuration: shardLeaseDuration,
+		RenewInterval: shardRenewInterval,
+	}, leaseClient, mgr.GetClient(), ctrl.Log.WithName("sharding"))
+	if err != nil {
+		return nil, err
+	}
+	return sharder, mgr.Add(sharder)
+}
+
 func init() {
 	rootCmd.Flags().StringVar(&metricsAddr, "metrics-addr", ":8080", "The address the metric endpoint binds to.")
 	rootCmd.Flags().StringVar(&controllerClass, "controller-class", "default", "The controller is instantiated with a specific controller name and filters ES based on this property")
@@ -335,8 +502,30 @@ func init() {
 	rootCmd.Flags().BoolVar(&enableConfigMapsCache, "enable-configmaps-caching", false, "Enable configmaps caching for ALL configmaps in the cluster (WARNING: can increase memory usage).")
 	rootCmd.Flags().BoolVar(&enableManagedSecretsCache, "enable-managed-secrets-caching", true, "Enable secrets caching for secrets managed by an ExternalSecret")
 	rootCmd.Flags().DurationVar(&storeRequeueInterval, "store-requeue-interval", time.Minute*5, "Default Time duration between reconciling (Cluster)SecretStores")
+	rootCmd.Flags().IntVar(&refreshJitter, "refresh-jitter", 0, "Percentage of the refresh interval added at random to the time until the next refresh of ExternalSecrets and (Cluster)SecretStores, so they don't stay aligned.")
+	rootCmd.Flags().DurationVar(&startupStagger, "startup-stagger", 0, "Window after the start of the controller over which the refreshes of ExternalSecrets and (Cluster)SecretStores which are due are spread. Disabled if 0.")
+	rootCmd.Flags().DurationVar(&expiryWarningWindow, "expiry-warning-window", 0, "Time before the expiry of an entry of an ExternalSecret in which a warning event is emitted on every sync. Disabled if 0.")
 	rootCmd.Flags().BoolVar(&enableFloodGate, "enable-flood-gate", true, "Enable flood gate. External secret will be reconciled only if the ClusterStore or Store have an healthy or unknown state.")
 	rootCmd.Flags().BoolVar(&enableGeneratorState, "enable-generator-state", true, "Whether the Controller should manage GeneratorState")
+	rootCmd.Flags().BoolVar(&enableRolloutTriggers, "enable-rollout-triggers", false, "Enable restarting the workloads in spec.rollout of an ExternalSecret when its data changes (requires granting the controller patch access to Deployments, StatefulSets and DaemonSets).")
+	rootCmd.Flags().StringVar(&notificationsAddr, "notifications-addr", "", "The address the server receiving change notifications from providers binds to, e.g. :8090. ExternalSecrets referencing a changed remote key are refreshed immediately. Disabled if empty.")
+	rootCmd.Flags().StringVar(&notificationsTokenFile, "notifications-token-file", "", "Path to a file holding the token change notifications must present. Required unless --notifications-allow-unauthenticated is set.")
+	rootCmd.Flags().BoolVar(&notificationsAllowUnauthenticated, "notifications-allow-unauthenticated", false, "Accept change notifications without a token (WARNING: anyone who can reach the notifications server can force refreshes of ExternalSecrets).")
+	rootCmd.Flags().BoolVar(&enableResponseCache, "enable-response-cache", false, "Enable caching provider responses across reconciles, so ExternalSecrets reading the same remote ref share a single provider call.")
+	rootCmd.Flags().IntVar(&responseCacheSize, "response-cache-size", 1000, "Maximum number of provider responses held by the response cache.")
+	rootCmd.Flags().DurationVar(&responseCacheTTL, "response-cache-ttl", time.Minute, "Default time provider responses are cached, stores can override it with spec.responseCache.ttl.")
+	rootCmd.Flags().BoolVar(&enableClientPool, "enable-client-pool", false, "Enable reusing provider clients across reconciles, so providers with expensive authentication don't log in for every reconcile. Supported by AWS, Azure Key Vault and Vault.")
+	rootCmd.Flags().IntVar(&clientPoolSize, "client-pool-size", 100, "Maximum number of stores and namespaces the client pool holds clients of.")
+	rootCmd.Flags().DurationVar(&clientPoolMaxAge, "client-pool-max-age", 5*time.Minute, "Time after which pooled clients are closed, must be shorter than the lifetime of the credentials of the providers.")
+	rootCmd.Flags().IntVar(&shards, "shards", 0, "Number of shards ExternalSecrets are split into. Every replica reconciles the ExternalSecrets of the shards it holds a Lease for, shards are rebalanced when replicas come and go. Disabled if 0.")
+	rootCmd.Flags().StringVar(&shardKey, "shard-key", string(sharding.KeyNamespace), "What ExternalSecrets are assigned to shards by, one of 'namespace' or 'uid'.")
+	rootCmd.Flags().StringVar(&hashKeySecret, "hash-key-secret", "external-secrets-hash-key", "Name of the Secret holding the key of the hashes of secret values written to the status of resources. It is created if it does not exist.")
+	rootCmd.Flags().StringVar(&hashKeyNamespace, "hash-key-namespace", "", "Namespace of the hash key Secret, defaults to the namespace the controller runs in.")
+	rootCmd.Flags().StringVar(&shardLeaseNamespace, "shard-lease-namespace", "", "Namespace of the shard Leases, defaults to the namespace the controller runs in.")
+	rootCmd.Flags().DurationVar(&shardLeaseDuration, "shard-lease-duration", 15*time.Second, "Time a replica holds a shard without renewing its Lease, after which other replicas take it over.")
+	rootCmd.Flags().DurationVar(&shardRenewInterval, "shard-renew-interval", 5*time.Second, "Interval in which shard Leases are renewed and shards are rebalanced.")
+	rootCmd.Flags().BoolVar(&allowGenericTargets, "unsafe-allow-generic-targets", false, "Allow ExternalSecrets to manage resources other than Secrets, e.g. ConfigMaps or custom resources (WARNING: requires granting the controller write access to these resources).")
+	rootCmd.Flags().BoolVar(&allowPushSecretResources, "unsafe-allow-pushsecret-resources", false, "Allow PushSecrets to push fields of namespaced resources other than Secrets and ConfigMaps (WARNING: requires granting the controller read access to these resources).")
 	rootCmd.Flags().BoolVar(&enableExtendedMetricLabels, "enable-extended-metric-labels", false, "Enable recommended kubernetes annotations as labels in metrics.")
 	fs := feature.Features()
 	for _, f := range fs {
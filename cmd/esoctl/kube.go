@@ -0,0 +1,151 @@
+/*
+Copyright © 2025 ESO Maintainer team
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/yaml"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	"github.com/external-secrets/external-secrets/apis/externalsecrets/v1alpha1"
+	genv1alpha1 "github.com/external-secrets/external-secrets/apis/generators/v1alpha1"
+)
+
+// newKubeClient creates a client for the cluster of the given kubeconfig and context.
+// Empty values fall back to the default loading rules of kubectl.
+func newKubeClient(kubeconfig, kubeContext string) (client.WithWatch, *runtime.Scheme, clientcmd.ClientConfig, error) {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = kubeconfig
+	clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, &clientcmd.ConfigOverrides{CurrentContext: kubeContext})
+	restConfig, err := clientConfig.ClientConfig()
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("could not load kubeconfig: %w", err)
+	}
+
+	scheme, err := newScheme()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	kubeClient, err := client.NewWithWatch(restConfig, client.Options{Scheme: scheme})
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("could not create kubernetes client: %w", err)
+	}
+	return kubeClient, scheme, clientConfig, nil
+}
+
+func newScheme() (*runtime.Scheme, error) {
+	scheme := runtime.NewScheme()
+	err := errors.Join(
+		clientgoscheme.AddToScheme(scheme),
+		esv1.AddToScheme(scheme),
+		v1alpha1.AddToScheme(scheme),
+		genv1alpha1.AddToScheme(scheme),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not build scheme: %w", err)
+	}
+	return scheme, nil
+}
+
+// withLocalStores returns a client which serves the given stores instead of the ones in the cluster.
+func withLocalStores(kubeClient client.WithWatch, stores []client.Object) client.WithWatch {
+	if len(stores) == 0 {
+		return kubeClient
+	}
+	return interceptor.NewClient(kubeClient, interceptor.Funcs{
+		Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+			for _, store := range stores {
+				if store.GetName() != key.Name || fmt.Sprintf("%T", store) != fmt.Sprintf("%T", obj) {
+					continue
+				}
+				// SecretStores are namespaced, the local manifest may omit the namespace
+				if _, ok := store.(*esv1.SecretStore); ok && store.GetNamespace() != "" && store.GetNamespace() != key.Namespace {
+					continue
+				}
+				switch s := store.(type) {
+				case *esv1.SecretStore:
+					s.DeepCopyInto(obj.(*esv1.SecretStore))
+				case *esv1.ClusterSecretStore:
+					s.DeepCopyInto(obj.(*esv1.ClusterSecretStore))
+				}
+				obj.SetNamespace(key.Namespace)
+				return nil
+			}
+			return c.Get(ctx, key, obj, opts...)
+		},
+	})
+}
+
+func readStore(file string) (client.Object, error) {
+	meta := &metav1.TypeMeta{}
+	if err := readManifest(file, meta); err != nil {
+		return nil, err
+	}
+	var store client.Object
+	switch meta.Kind {
+	case esv1.SecretStoreKind:
+		store = &esv1.SecretStore{}
+	case esv1.ClusterSecretStoreKind:
+		store = &esv1.ClusterSecretStore{}
+	default:
+		return nil, fmt.Errorf("unsupported kind %s, expected %s or %s", meta.Kind, esv1.SecretStoreKind, esv1.ClusterSecretStoreKind)
+	}
+	if err := readManifest(file, store); err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+func readManifest(file string, obj any) error {
+	var (
+		content []byte
+		err     error
+	)
+	if file == "-" {
+		content, err = io.ReadAll(os.Stdin)
+	} else {
+		content, err = os.ReadFile(filepath.Clean(file))
+	}
+	if err != nil {
+		return err
+	}
+	return yaml.Unmarshal(content, obj)
+}
+
+func readStores(files []string) ([]client.Object, error) {
+	stores := make([]client.Object, 0, len(files))
+	for _, f := range files {
+		store, err := readStore(f)
+		if err != nil {
+			return nil, fmt.Errorf("could not read store %s: %w", f, err)
+		}
+		stores = append(stores, store)
+	}
+	return stores, nil
+}
@@ -0,0 +1,476 @@
+/*
+Copyright © 2025 ESO Maintainer team
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	"github.com/external-secrets/external-secrets/apis/externalsecrets/v1alpha1"
+	"github.com/external-secrets/external-secrets/pkg/controllers/secretstore"
+)
+
+const (
+	defaultRemoteKeyTemplate = "{{ .Namespace }}-{{ .Name }}-{{ .Key }}"
+	annotationLastApplied    = "kubectl.kubernetes.io/last-applied-configuration"
+)
+
+var (
+	migrateFiles             []string
+	migrateFromCluster       bool
+	migrateNamespace         string
+	migrateSelector          string
+	migrateStoreFile         string
+	migrateStoreName         string
+	migrateStoreKind         string
+	migrateRemoteKeyTemplate string
+	migratePropertyTemplate  string
+	migrateRefreshInterval   time.Duration
+	migrateSkipPush          bool
+	migrateKubeconfig        string
+	migrateContext           string
+	migrateControllerClass   string
+	migrateOutputFile        string
+)
+
+func init() {
+	rootCmd.AddCommand(migrateCmd)
+	migrateCmd.Flags().StringSliceVarP(&migrateFiles, "file", "f", nil, "Link to a file containing the Secrets to migrate. Use - to read from stdin. Can be repeated")
+	migrateCmd.Flags().BoolVar(&migrateFromCluster, "from-cluster", false, "If set, the Secrets to migrate are read from the cluster")
+	migrateCmd.Flags().StringVarP(&migrateNamespace, "namespace", "n", "", "Namespace of the Secrets. Defaults to the namespace of the manifest or the current context")
+	migrateCmd.Flags().StringVarP(&migrateSelector, "selector", "l", "", "Label selector used to filter the Secrets read from the cluster")
+	migrateCmd.Flags().StringVar(&migrateStoreFile, "store", "", "Link to a file containing the SecretStore or ClusterSecretStore to push to. If not set, the store is read from the cluster")
+	migrateCmd.Flags().StringVar(&migrateStoreName, "store-name", "", "Name of the SecretStore or ClusterSecretStore to push to. Defaults to the name of the store given with --store")
+	migrateCmd.Flags().StringVar(&migrateStoreKind, "store-kind", esv1.SecretStoreKind, "Kind of the store to push to, either SecretStore or ClusterSecretStore")
+	migrateCmd.Flags().StringVar(&migrateRemoteKeyTemplate, "remote-key-template", defaultRemoteKeyTemplate, "Template of the remote key. .Namespace, .Name and .Key of the secret are available")
+	migrateCmd.Flags().StringVar(&migratePropertyTemplate, "property-template", "", "Template of the remote property. .Namespace, .Name and .Key of the secret are available. If empty, no property is used")
+	migrateCmd.Flags().DurationVar(&migrateRefreshInterval, "refresh-interval", time.Hour, "The refresh interval of the generated ExternalSecrets")
+	migrateCmd.Flags().BoolVar(&migrateSkipPush, "skip-push", false, "If set, nothing is pushed to the provider and only the ExternalSecrets are generated")
+	migrateCmd.Flags().StringVar(&migrateKubeconfig, "kubeconfig", "", "Path to the kubeconfig file. Defaults to the KUBECONFIG environment variable or ~/.kube/config")
+	migrateCmd.Flags().StringVar(&migrateContext, "context", "", "The kubeconfig context to use")
+	migrateCmd.Flags().StringVar(&migrateControllerClass, "controller-class", "default", "The controller class used to filter stores, as set on the controller")
+	migrateCmd.Flags().StringVar(&migrateOutputFile, "output", "", "If set, the ExternalSecrets will be written to this file")
+}
+
+var migrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "pushes existing Secrets to a provider and generates the matching ExternalSecrets",
+	Long: `Reads existing Secrets from files or from the cluster and pushes their values to the provider of a SecretStore,
+exactly like a PushSecret would. Then, an ExternalSecret is generated for every Secret, fetching the pushed keys back.
+Any provider which supports both reading and writing secrets can be used.
+Encrypted manifests, e.g. SOPS, must be decrypted first: sops -d secrets.yaml | esoctl migrate -f - ...`,
+	RunE: migrateRun,
+}
+
+// migrationKey is the data available to the remote key and property templates.
+type migrationKey struct {
+	Namespace string
+	Name      string
+	Key       string
+}
+
+func migrateRun(cmd *cobra.Command, _ []string) error {
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if len(migrateFiles) == 0 && !migrateFromCluster {
+		return errors.New("either --file or --from-cluster must be set")
+	}
+
+	remoteKeyTmpl, err := template.New("remoteKey").Option("missingkey=error").Parse(migrateRemoteKeyTemplate)
+	if err != nil {
+		return fmt.Errorf("could not parse remote key template: %w", err)
+	}
+	propertyTmpl, err := template.New("property").Option("missingkey=error").Parse(migratePropertyTemplate)
+	if err != nil {
+		return fmt.Errorf("could not parse property template: %w", err)
+	}
+
+	kubeClient, _, clientConfig, err := newKubeClient(migrateKubeconfig, migrateContext)
+	if err != nil {
+		return err
+	}
+	namespace := migrateNamespace
+	if namespace == "" {
+		namespace, _, err = clientConfig.Namespace()
+		if err != nil {
+			return fmt.Errorf("could not determine namespace: %w", err)
+		}
+	}
+
+	var stores []client.Object
+	if migrateStoreFile != "" {
+		stores, err = readStores([]string{migrateStoreFile})
+		if err != nil {
+			return err
+		}
+		if migrateStoreName == "" {
+			migrateStoreName = stores[0].GetName()
+		}
+		migrateStoreKind = stores[0].GetObjectKind().GroupVersionKind().Kind
+	}
+	if migrateStoreName == "" {
+		return errors.New("either --store or --store-name must be set")
+	}
+	kubeClient = withLocalStores(kubeClient, stores)
+	storeRef := esv1.SecretStoreRef{Name: migrateStoreName, Kind: migrateStoreKind}
+
+	secrets, err := readMigrationSecrets(ctx, kubeClient, namespace)
+	if err != nil {
+		return err
+	}
+
+	externalSecrets, pushes, err := planMigration(secrets, storeRef, remoteKeyTmpl, propertyTmpl)
+	if err != nil {
+		return err
+	}
+
+	if !migrateSkipPush {
+		if err := pushMigration(ctx, kubeClient, storeRef, secrets, pushes, cmd.ErrOrStderr()); err != nil {
+			return err
+		}
+	}
+
+	out := cmd.OutOrStdout()
+	if migrateOutputFile != "" {
+		f, err := os.Create(filepath.Clean(migrateOutputFile))
+		if err != nil {
+			return fmt.Errorf("could not create output file: %w", err)
+		}
+		defer func() {
+			_ = f.Close()
+		}()
+
+		out = f
+	}
+	return writeManifests(out, externalSecrets)
+}
+
+// readMigrationSecrets returns the Secrets to migrate, either from the given files or from the cluster.
+func readMigrationSecrets(ctx context.Context, kubeClient client.Client, namespace string) ([]corev1.Secret, error) {
+	var secrets []corev1.Secret
+	for _, f := range migrateFiles {
+		s, err := readSecrets(f)
+		if err != nil {
+			return nil, fmt.Errorf("could not read secrets from %s: %w", f, err)
+		}
+		secrets = append(secrets, s...)
+	}
+	if migrateFromCluster {
+		selector, err := labels.Parse(migrateSelector)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse selector: %w", err)
+		}
+		list := &corev1.SecretList{}
+		if err := kubeClient.List(ctx, list, client.InNamespace(namespace), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+			return nil, fmt.Errorf("could not list secrets: %w", err)
+		}
+		secrets = append(secrets, list.Items...)
+	}
+
+	filtered := make([]corev1.Secret, 0, len(secrets))
+	for _, s := range secrets {
+		if s.Namespace == "" {
+			s.Namespace = namespace
+		}
+		if !shouldMigrate(&s) {
+			continue
+		}
+		filtered = append(filtered, s)
+	}
+	slices.SortFunc(filtered, func(a, b corev1.Secret) int {
+		return strings.Compare(a.Namespace+"/"+a.Name, b.Namespace+"/"+b.Name)
+	})
+	return filtered, nil
+}
+
+// skippedSecretTypes are the types of Secrets which are managed by Kubernetes or other tools, e.g. Helm releases.
+// Migrating them would make the generated ExternalSecrets fight with these tools over the Secrets.
+var skippedSecretTypes = []corev1.SecretType{
+	corev1.SecretTypeServiceAccountToken,
+	corev1.SecretTypeBootstrapToken,
+	"helm.sh/release.v1",
+}
+
+// shouldMigrate skips Secrets which are managed by Kubernetes, other tools or by ESO already, as well as empty ones.
+func shouldMigrate(secret *corev1.Secret) bool {
+	if slices.Contains(skippedSecretTypes, secret.Type) {
+		return false
+	}
+	if secret.Labels[esv1.LabelManaged] == esv1.LabelManagedValue {
+		return false
+	}
+	return len(secret.Data) > 0
+}
+
+// readSecrets reads all Secrets of a multi-document manifest, including the items of a List.
+func readSecrets(file string) ([]corev1.Secret, error) {
+	var (
+		reader io.Reader
+		err    error
+	)
+	if file == "-" {
+		reader = os.Stdin
+	} else {
+		f, err := os.Open(filepath.Clean(file))
+		if err != nil {
+			return nil, err
+		}
+		defer func() {
+			_ = f.Close()
+		}()
+		reader = f
+	}
+
+	var secrets []corev1.Secret
+	decoder := utilyaml.NewYAMLOrJSONDecoder(reader, 4096)
+	for {
+		obj := &unstructured.Unstructured{}
+		err = decoder.Decode(&obj.Object)
+		if errors.Is(err, io.EOF) {
+			return secrets, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if obj.Object == nil {
+			continue
+		}
+		if _, ok := obj.Object["sops"]; ok {
+			return nil, errors.New("the manifest is encrypted with SOPS, decrypt it first with sops -d")
+		}
+		objs := []unstructured.Unstructured{*obj}
+		if obj.IsList() {
+			list, err := obj.ToList()
+			if err != nil {
+				return nil, err
+			}
+			objs = list.Items
+		}
+		for _, o := range objs {
+			if o.GetKind() != "Secret" {
+				return nil, fmt.Errorf("unsupported kind %s of %s, expected Secret", o.GetKind(), o.GetName())
+			}
+			secret := corev1.Secret{}
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(o.Object, &secret); err != nil {
+				return nil, err
+			}
+			if secret.Data == nil {
+				secret.Data = make(map[string][]byte, len(secret.StringData))
+			}
+			for k, v := range secret.StringData {
+				secret.Data[k] = []byte(v)
+			}
+			secret.StringData = nil
+			secrets = append(secrets, secret)
+		}
+	}
+}
+
+// planMigration returns the ExternalSecrets to generate and the data to push for every Secret, keyed by namespace/name.
+func planMigration(secrets []corev1.Secret, storeRef esv1.SecretStoreRef, remoteKeyTmpl, propertyTmpl *template.Template) ([]*esv1.ExternalSecret, map[string][]v1alpha1.PushSecretData, error) {
+	externalSecrets := make([]*esv1.ExternalSecret, 0, len(secrets))
+	pushes := make(map[string][]v1alpha1.PushSecretData, len(secrets))
+	seen := make(map[v1alpha1.PushSecretRemoteRef]string)
+	for _, secret := range secrets {
+		secretName := secret.Namespace + "/" + secret.Name
+		keys := make([]string, 0, len(secret.Data))
+		for k := range secret.Data {
+			keys = append(keys, k)
+		}
+		slices.Sort(keys)
+
+		es := newMigratedExternalSecret(&secret, storeRef)
+		for _, key := range keys {
+			ref, err := migrationRemoteRef(migrationKey{Namespace: secret.Namespace, Name: secret.Name, Key: key}, remoteKeyTmpl, propertyTmpl)
+			if err != nil {
+				return nil, nil, err
+			}
+			if other, ok := seen[ref]; ok {
+				return nil, nil, fmt.Errorf("remote key %s (property %q) of %s/%s collides with %s, use a more specific --remote-key-template", ref.RemoteKey, ref.Property, secretName, key, other)
+			}
+			seen[ref] = secretName + "/" + key
+
+			pushes[secretName] = append(pushes[secretName], v1alpha1.PushSecretData{
+				Match: v1alpha1.PushSecretMatch{
+					SecretKey: key,
+					RemoteRef: ref,
+				},
+			})
+			es.Spec.Data = append(es.Spec.Data, esv1.ExternalSecretData{
+				SecretKey: key,
+				RemoteRef: esv1.ExternalSecretDataRemoteRef{
+					Key:      ref.RemoteKey,
+					Property: ref.Property,
+				},
+			})
+		}
+		externalSecrets = append(externalSecrets, es)
+	}
+	return externalSecrets, pushes, nil
+}
+
+func migrationRemoteRef(key migrationKey, remoteKeyTmpl, propertyTmpl *template.Template) (v1alpha1.PushSecretRemoteRef, error) {
+	var remoteKey, property bytes.Buffer
+	if err := remoteKeyTmpl.Execute(&remoteKey, key); err != nil {
+		return v1alpha1.PushSecretRemoteRef{}, fmt.Errorf("could not render remote key: %w", err)
+	}
+	if err := propertyTmpl.Execute(&property, key); err != nil {
+		return v1alpha1.PushSecretRemoteRef{}, fmt.Errorf("could not render property: %w", err)
+	}
+	if remoteKey.Len() == 0 {
+		return v1alpha1.PushSecretRemoteRef{}, fmt.Errorf("remote key of %s/%s/%s is empty", key.Namespace, key.Name, key.Key)
+	}
+	return v1alpha1.PushSecretRemoteRef{
+		RemoteKey: remoteKey.String(),
+		Property:  property.String(),
+	}, nil
+}
+
+// newMigratedExternalSecret returns an ExternalSecret which recreates the given Secret, including its type and metadata.
+func newMigratedExternalSecret(secret *corev1.Secret, storeRef esv1.SecretStoreRef) *esv1.ExternalSecret {
+	es := &esv1.ExternalSecret{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: esv1.SchemeGroupVersion.String(),
+			Kind:       esv1.ExtSecretKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      secret.Name,
+			Namespace: secret.Namespace,
+		},
+		Spec: esv1.ExternalSecretSpec{
+			RefreshInterval: &metav1.Duration{Duration: migrateRefreshInterval},
+			SecretStoreRef:  storeRef,
+			Target: esv1.ExternalSecretTarget{
+				Name: secret.Name,
+			},
+		},
+	}
+
+	annotations := make(map[string]string, len(secret.Annotations))
+	for k, v := range secret.Annotations {
+		if k == annotationLastApplied {
+			continue
+		}
+		annotations[k] = v
+	}
+	if (secret.Type != "" && secret.Type != corev1.SecretTypeOpaque) || len(secret.Labels) > 0 || len(annotations) > 0 {
+		es.Spec.Target.Template = &esv1.ExternalSecretTemplate{
+			Type:          secret.Type,
+			EngineVersion: esv1.TemplateEngineV2,
+			MergePolicy:   esv1.MergePolicyReplace,
+		}
+		if len(secret.Labels) > 0 || len(annotations) > 0 {
+			es.Spec.Target.Template.Metadata = esv1.ExternalSecretTemplateMetadata{
+				Labels:      secret.Labels,
+				Annotations: annotations,
+			}
+		}
+	}
+	return es
+}
+
+// pushMigration pushes the data of every Secret to the store, using the same client as the PushSecret controller.
+func pushMigration(ctx context.Context, kubeClient client.Client, storeRef esv1.SecretStoreRef, secrets []corev1.Secret, pushes map[string][]v1alpha1.PushSecretData, log io.Writer) error {
+	mgr := secretstore.NewManager(kubeClient, migrateControllerClass, false)
+	defer func() {
+		_ = mgr.Close(ctx)
+	}()
+
+	for _, secret := range secrets {
+		if err := checkMigrationStore(ctx, kubeClient, storeRef, secret.Namespace); err != nil {
+			return err
+		}
+		secretClient, err := mgr.Get(ctx, storeRef, secret.Namespace, nil)
+		if err != nil {
+			return fmt.Errorf("could not get secrets client for store %s: %w", storeRef.Name, err)
+		}
+		secretName := secret.Namespace + "/" + secret.Name
+		for _, data := range pushes[secretName] {
+			if err := secretClient.PushSecret(ctx, &secret, data); err != nil {
+				return fmt.Errorf("could not push key %s of secret %s: %w", data.GetSecretKey(), secretName, err)
+			}
+		}
+		_, _ = fmt.Fprintf(log, "pushed %d keys of secret %s\n", len(pushes[secretName]), secretName)
+	}
+	return nil
+}
+
+// checkMigrationStore verifies that the provider of the store can both write the pushed secrets and read them back.
+func checkMigrationStore(ctx context.Context, kubeClient client.Client, storeRef esv1.SecretStoreRef, namespace string) error {
+	var store esv1.GenericStore = &esv1.SecretStore{}
+	if storeRef.Kind == esv1.ClusterSecretStoreKind {
+		store = &esv1.ClusterSecretStore{}
+	}
+	if err := kubeClient.Get(ctx, client.ObjectKey{Name: storeRef.Name, Namespace: namespace}, store); err != nil {
+		return fmt.Errorf("could not get store %s: %w", storeRef.Name, err)
+	}
+	provider, err := esv1.GetProvider(store)
+	if err != nil {
+		return err
+	}
+	if provider.Capabilities() != esv1.SecretStoreReadWrite {
+		return fmt.Errorf("store %s is %s, the migration requires a %s store", storeRef.Name, provider.Capabilities(), esv1.SecretStoreReadWrite)
+	}
+	return nil
+}
+
+// writeManifests writes the ExternalSecrets as a multi-document manifest, without status and server-side fields.
+func writeManifests(out io.Writer, externalSecrets []*esv1.ExternalSecret) error {
+	for i, es := range externalSecrets {
+		obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(es)
+		if err != nil {
+			return err
+		}
+		unstructured.RemoveNestedField(obj, "status")
+		unstructured.RemoveNestedField(obj, "metadata", "creationTimestamp")
+		content, err := yaml.Marshal(obj)
+		if err != nil {
+			return fmt.Errorf("could not marshal ExternalSecret %s: %w", es.Name, err)
+		}
+		if i > 0 {
+			if _, err := fmt.Fprintln(out, "---"); err != nil {
+				return err
+			}
+		}
+		if _, err := out.Write(content); err != nil {
+			return err
+		}
+	}
+	return nil
+}
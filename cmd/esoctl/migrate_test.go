@@ -0,0 +1,243 @@
+/*
+Copyright © 2025 ESO Maintainer team
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"text/template"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+)
+
+func TestShouldMigrate(t *testing.T) {
+	data := map[string][]byte{"key": []byte("value")}
+	tests := []struct {
+		name   string
+		secret corev1.Secret
+		want   bool
+	}{
+		{
+			name:   "opaque",
+			secret: corev1.Secret{Type: corev1.SecretTypeOpaque, Data: data},
+			want:   true,
+		},
+		{
+			name:   "tls",
+			secret: corev1.Secret{Type: corev1.SecretTypeTLS, Data: data},
+			want:   true,
+		},
+		{
+			name:   "service account token",
+			secret: corev1.Secret{Type: corev1.SecretTypeServiceAccountToken, Data: data},
+		},
+		{
+			name:   "bootstrap token",
+			secret: corev1.Secret{Type: corev1.SecretTypeBootstrapToken, Data: data},
+		},
+		{
+			name:   "helm release",
+			secret: corev1.Secret{Type: "helm.sh/release.v1", Data: data},
+		},
+		{
+			name: "managed by an ExternalSecret",
+			secret: corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{esv1.LabelManaged: esv1.LabelManagedValue}},
+				Data:       data,
+			},
+		},
+		{
+			name:   "empty",
+			secret: corev1.Secret{Type: corev1.SecretTypeOpaque},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := shouldMigrate(&tt.secret); got != tt.want {
+				t.Errorf("shouldMigrate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadSecrets(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest string
+		want     []string
+		wantErr  bool
+	}{
+		{
+			name: "multiple documents",
+			manifest: `apiVersion: v1
+kind: Secret
+metadata:
+  name: first
+data:
+  key: dmFsdWU=
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: second
+stringData:
+  key: value
+`,
+			want: []string{"first", "second"},
+		},
+		{
+			name: "list",
+			manifest: `apiVersion: v1
+kind: List
+items:
+- apiVersion: v1
+  kind: Secret
+  metadata:
+    name: first
+  stringData:
+    key: value
+`,
+			want: []string{"first"},
+		},
+		{
+			name: "other kind",
+			manifest: `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+`,
+			wantErr: true,
+		},
+		{
+			name: "sops",
+			manifest: `apiVersion: v1
+kind: Secret
+metadata:
+  name: first
+data:
+  key: ENC[AES256_GCM,data:abc]
+sops:
+  version: 3.8.1
+`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := filepath.Join(t.TempDir(), "secrets.yaml")
+			if err := os.WriteFile(file, []byte(tt.manifest), 0o600); err != nil {
+				t.Fatalf("could not write manifest: %v", err)
+			}
+			secrets, err := readSecrets(file)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("readSecrets() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			var names []string
+			for _, s := range secrets {
+				names = append(names, s.Name)
+				if string(s.Data["key"]) != "value" || s.StringData != nil {
+					t.Errorf("unexpected data of %s: %v", s.Name, s.Data)
+				}
+			}
+			if diff := cmp.Diff(tt.want, names); diff != "" {
+				t.Errorf("unexpected secrets (-want, +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPlanMigration(t *testing.T) {
+	storeRef := esv1.SecretStoreRef{Name: "vault", Kind: esv1.ClusterSecretStoreKind}
+	secrets := []corev1.Secret{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "tls",
+				Namespace:   "default",
+				Labels:      map[string]string{"app": "web"},
+				Annotations: map[string]string{annotationLastApplied: "{}"},
+			},
+			Type: corev1.SecretTypeTLS,
+			Data: map[string][]byte{
+				"tls.key": []byte("key"),
+				"tls.crt": []byte("crt"),
+			},
+		},
+	}
+
+	t.Run("one remote key per secret", func(t *testing.T) {
+		remoteKeyTmpl := template.Must(template.New("remoteKey").Parse("{{ .Namespace }}/{{ .Name }}"))
+		propertyTmpl := template.Must(template.New("property").Parse("{{ .Key }}"))
+		externalSecrets, pushes, err := planMigration(secrets, storeRef, remoteKeyTmpl, propertyTmpl)
+		if err != nil {
+			t.Fatalf("planMigration() returned an unexpected error: %v", err)
+		}
+		if len(pushes["default/tls"]) != 2 || pushes["default/tls"][0].Match.RemoteRef.Property != "tls.crt" {
+			t.Errorf("unexpected pushes: %v", pushes)
+		}
+
+		var out bytes.Buffer
+		if err := writeManifests(&out, externalSecrets); err != nil {
+			t.Fatalf("writeManifests() returned an unexpected error: %v", err)
+		}
+		want := `apiVersion: external-secrets.io/v1
+kind: ExternalSecret
+metadata:
+  name: tls
+  namespace: default
+spec:
+  data:
+  - remoteRef:
+      key: default/tls
+      property: tls.crt
+    secretKey: tls.crt
+  - remoteRef:
+      key: default/tls
+      property: tls.key
+    secretKey: tls.key
+  refreshInterval: 1h0m0s
+  secretStoreRef:
+    kind: ClusterSecretStore
+    name: vault
+  target:
+    name: tls
+    template:
+      engineVersion: v2
+      mergePolicy: Replace
+      metadata:
+        labels:
+          app: web
+      type: kubernetes.io/tls
+`
+		if diff := cmp.Diff(want, out.String()); diff != "" {
+			t.Errorf("unexpected manifests (-want, +got)\n%s", diff)
+		}
+	})
+
+	t.Run("colliding remote keys", func(t *testing.T) {
+		remoteKeyTmpl := template.Must(template.New("remoteKey").Parse("{{ .Namespace }}/{{ .Name }}"))
+		propertyTmpl := template.Must(template.New("property").Parse(""))
+		if _, _, err := planMigration(secrets, storeRef, remoteKeyTmpl, propertyTmpl); err == nil {
+			t.Errorf("expected an error for colliding remote keys")
+		}
+	})
+}
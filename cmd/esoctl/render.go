@@ -0,0 +1,256 @@
+/*
+Copyright © 2025 ESO Maintainer team
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/go-logr/logr"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	"github.com/external-secrets/external-secrets/pkg/controllers/externalsecret"
+)
+
+const maskedValue = "*****"
+
+var (
+	renderFile            string
+	renderStoreFiles      []string
+	renderNamespace       string
+	renderKubeconfig      string
+	renderContext         string
+	renderControllerClass string
+	renderMaskValues      bool
+	renderOutputFile      string
+)
+
+func init() {
+	rootCmd.AddCommand(renderCmd)
+	renderCmd.Flags().StringVarP(&renderFile, "file", "f", "", "Link to a file containing the ExternalSecret to render")
+	renderCmd.Flags().StringSliceVar(&renderStoreFiles, "store", nil, "Link to a file containing a SecretStore or ClusterSecretStore. If set, it is used instead of the store in the cluster. Can be repeated")
+	renderCmd.Flags().StringVarP(&renderNamespace, "namespace", "n", "", "Namespace of the ExternalSecret. Defaults to the namespace of the manifest or the current context")
+	renderCmd.Flags().StringVar(&renderKubeconfig, "kubeconfig", "", "Path to the kubeconfig file. Defaults to the KUBECONFIG environment variable or ~/.kube/config")
+	renderCmd.Flags().StringVar(&renderContext, "context", "", "The kubeconfig context to use")
+	renderCmd.Flags().StringVar(&renderControllerClass, "controller-class", "default", "The controller class used to filter stores, as set on the controller")
+	renderCmd.Flags().BoolVar(&renderMaskValues, "mask-values", false, "If set, the values of the resulting Secret or target resource are masked")
+	renderCmd.Flags().StringVar(&renderOutputFile, "output", "", "If set, the output will be written to this file")
+	_ = renderCmd.MarkFlagRequired("file")
+}
+
+var renderCmd = &cobra.Command{
+	Use:   "render",
+	Short: "renders an ExternalSecret end-to-end and prints the resulting Secret or target resource",
+	Long: `Renders an ExternalSecret end-to-end, exactly like the controller would during a reconcile.
+The SecretStore is resolved through the registered providers using the credentials of the current kubeconfig,
+the provider data is fetched and the template is applied. Nothing is written to the cluster.`,
+	RunE: renderRun,
+}
+
+func renderRun(cmd *cobra.Command, _ []string) error {
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	es := &esv1.ExternalSecret{}
+	if err := readManifest(renderFile, es); err != nil {
+		return fmt.Errorf("could not read ExternalSecret: %w", err)
+	}
+	if es.Kind != esv1.ExtSecretKind {
+		return fmt.Errorf("unsupported kind %s, expected %s", es.Kind, esv1.ExtSecretKind)
+	}
+
+	stores, err := readStores(renderStoreFiles)
+	if err != nil {
+		return err
+	}
+
+	kubeClient, scheme, clientConfig, err := newKubeClient(renderKubeconfig, renderContext)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case renderNamespace != "":
+		es.Namespace = renderNamespace
+	case es.Namespace == "":
+		es.Namespace, _, err = clientConfig.Namespace()
+		if err != nil {
+			return fmt.Errorf("could not determine namespace: %w", err)
+		}
+	}
+
+	secret, err := renderExternalSecret(ctx, withLocalStores(kubeClient, stores), scheme, es, cmd.ErrOrStderr())
+	if err != nil {
+		return err
+	}
+	rendered, err := renderTarget(es, secret, renderMaskValues)
+	if err != nil {
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	if renderOutputFile != "" {
+		f, err := os.Create(filepath.Clean(renderOutputFile))
+		if err != nil {
+			return fmt.Errorf("could not create output file: %w", err)
+		}
+		defer func() {
+			_ = f.Close()
+		}()
+
+		out = f
+	}
+
+	content, err := yaml.Marshal(rendered)
+	if err != nil {
+		return fmt.Errorf("could not marshal target: %w", err)
+	}
+	_, err = fmt.Fprintln(out, string(content))
+
+	return err
+}
+
+// renderExternalSecret fetches the provider data of the ExternalSecret and applies its template,
+// using the same code paths as the controller. Events are written to the given writer.
+func renderExternalSecret(ctx context.Context, kubeClient client.Client, scheme *runtime.Scheme, es *esv1.ExternalSecret, events io.Writer) (*corev1.Secret, error) {
+	r := &externalsecret.Reconciler{
+		Client:          kubeClient,
+		SecretClient:    kubeClient,
+		Scheme:          scheme,
+		Log:             logr.Discard(),
+		ControllerClass: renderControllerClass,
+	}
+	r.SetEventRecorder(&writerRecorder{out: events})
+
+	dataMap, err := r.GetProviderSecretData(ctx, es)
+	if err != nil {
+		return nil, fmt.Errorf("could not get secret data from provider: %w", err)
+	}
+
+	name := es.Spec.Target.Name
+	if name == "" {
+		name = es.Name
+	}
+	secret := &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "Secret",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: es.Namespace,
+		},
+		Data: make(map[string][]byte),
+	}
+	if err := r.ApplyTemplate(ctx, es, secret, dataMap); err != nil {
+		return nil, fmt.Errorf("could not apply template: %w", err)
+	}
+
+	return secret, nil
+}
+
+// renderTarget returns the object the controller would write: the Secret,
+// or the ConfigMap or custom resource of spec.target.manifest.
+func renderTarget(es *esv1.ExternalSecret, secret *corev1.Secret, mask bool) (any, error) {
+	target, err := externalsecret.RenderGenericTarget(es, secret)
+	if err != nil {
+		return nil, fmt.Errorf("could not render target manifest: %w", err)
+	}
+	if target != nil {
+		if mask {
+			maskTarget(target)
+		}
+		return target, nil
+	}
+	if mask {
+		maskSecret(secret)
+	}
+	return secret, nil
+}
+
+// maskTarget replaces all values of the body of a generic target, i.e. everything except apiVersion, kind and metadata.
+// The manifest template may transform the secret values, so every value is masked, not only the ones of the Secret.
+func maskTarget(target *unstructured.Unstructured) {
+	for k, v := range target.Object {
+		switch k {
+		case "apiVersion", "kind", "metadata":
+			continue
+		}
+		target.Object[k] = maskValue(v)
+	}
+}
+
+func maskValue(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k := range v {
+			v[k] = maskValue(v[k])
+		}
+		return v
+	case []any:
+		for i := range v {
+			v[i] = maskValue(v[i])
+		}
+		return v
+	default:
+		return maskedValue
+	}
+}
+
+// maskSecret replaces all values of the secret, so it can be shared safely.
+func maskSecret(secret *corev1.Secret) {
+	if len(secret.Data) == 0 {
+		return
+	}
+	secret.StringData = make(map[string]string, len(secret.Data))
+	for k := range secret.Data {
+		secret.StringData[k] = maskedValue
+	}
+	secret.Data = nil
+}
+
+// writerRecorder writes events to a writer instead of the kube-apiserver.
+type writerRecorder struct {
+	out io.Writer
+}
+
+func (w *writerRecorder) Event(_ runtime.Object, eventtype, reason, message string) {
+	_, _ = fmt.Fprintf(w.out, "%s %s: %s\n", eventtype, reason, message)
+}
+
+func (w *writerRecorder) Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...any) {
+	w.Event(object, eventtype, reason, fmt.Sprintf(messageFmt, args...))
+}
+
+func (w *writerRecorder) AnnotatedEventf(object runtime.Object, _ map[string]string, eventtype, reason, messageFmt string, args ...any) {
+	w.Eventf(object, eventtype, reason, messageFmt, args...)
+}
+
+var _ record.EventRecorder = &writerRecorder{}
@@ -0,0 +1,114 @@
+/*
+Copyright © 2025 ESO Maintainer team
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+)
+
+func TestRenderTarget(t *testing.T) {
+	newSecret := func() *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "target", Namespace: "default"},
+			Data: map[string][]byte{
+				"config": []byte(`{"port": 8080, "hosts": ["a", "b"]}`),
+			},
+		}
+	}
+	manifest := &esv1.ExternalSecretTargetManifest{
+		APIVersion: "example.io/v1",
+		Kind:       "Widget",
+		Template: `spec:
+  {{- $config := .config | fromJson }}
+  port: {{ add $config.port 1 }}
+  hosts: {{ $config.hosts | toJson }}
+`,
+	}
+	tests := []struct {
+		name     string
+		manifest *esv1.ExternalSecretTargetManifest
+		mask     bool
+		want     map[string]any
+	}{
+		{
+			name:     "manifest",
+			manifest: manifest,
+			want: map[string]any{
+				"port":  int64(8081),
+				"hosts": []any{"a", "b"},
+			},
+		},
+		{
+			name:     "masked manifest",
+			manifest: manifest,
+			mask:     true,
+			want: map[string]any{
+				"port":  maskedValue,
+				"hosts": []any{maskedValue, maskedValue},
+			},
+		},
+		{
+			name:     "masked ConfigMap",
+			manifest: &esv1.ExternalSecretTargetManifest{APIVersion: "v1", Kind: "ConfigMap"},
+			mask:     true,
+			want: map[string]any{
+				"config": maskedValue,
+			},
+		},
+		{
+			name: "masked Secret",
+			mask: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := &esv1.ExternalSecret{
+				Spec: esv1.ExternalSecretSpec{
+					Target: esv1.ExternalSecretTarget{Manifest: tt.manifest},
+				},
+			}
+			got, err := renderTarget(es, newSecret(), tt.mask)
+			if err != nil {
+				t.Fatalf("renderTarget() returned an unexpected error: %v", err)
+			}
+			switch target := got.(type) {
+			case *unstructured.Unstructured:
+				field := "spec"
+				if target.GetKind() == "ConfigMap" {
+					field = "data"
+				}
+				if diff := cmp.Diff(tt.want, target.Object[field]); diff != "" {
+					t.Errorf("unexpected %s (-want, +got)\n%s", field, diff)
+				}
+				if target.GetName() != "target" {
+					t.Errorf("expected metadata not to be masked, got name %q", target.GetName())
+				}
+			case *corev1.Secret:
+				if target.Data != nil || target.StringData["config"] != maskedValue {
+					t.Errorf("expected secret to be masked, got %v %v", target.Data, target.StringData)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+/*
+Copyright © 2025 ESO Maintainer team
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+)
+
+var (
+	rollbackNamespace  string
+	rollbackKubeconfig string
+	rollbackContext    string
+	rollbackToVersion  int64
+	rollbackUnpin      bool
+)
+
+func init() {
+	rootCmd.AddCommand(rollbackCmd)
+	rollbackCmd.Flags().StringVarP(&rollbackNamespace, "namespace", "n", "", "Namespace of the ExternalSecret. Defaults to the namespace of the current context")
+	rollbackCmd.Flags().StringVar(&rollbackKubeconfig, "kubeconfig", "", "Path to the kubeconfig file. Defaults to the KUBECONFIG environment variable or ~/.kube/config")
+	rollbackCmd.Flags().StringVar(&rollbackContext, "context", "", "The kubeconfig context to use")
+	rollbackCmd.Flags().Int64Var(&rollbackToVersion, "to-version", 0, "Pins the target Secret to this version of its history")
+	rollbackCmd.Flags().BoolVar(&rollbackUnpin, "unpin", false, "Unpins the target Secret, so it is synced with the provider again")
+	rollbackCmd.MarkFlagsMutuallyExclusive("to-version", "unpin")
+}
+
+var rollbackCmd = &cobra.Command{
+	Use:   "rollback EXTERNALSECRET",
+	Short: "pins the target Secret of an ExternalSecret to a version of its history",
+	Long: `Pins the target Secret of an ExternalSecret to a version of its history, or unpins it.
+While pinned, the provider is not queried and the data of that version is applied instead.
+Without --to-version or --unpin, the versions of the history are listed.
+Requires spec.target.history to be set on the ExternalSecret.`,
+	Args: cobra.ExactArgs(1),
+	RunE: rollbackRun,
+}
+
+func rollbackRun(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	kubeClient, _, clientConfig, err := newKubeClient(rollbackKubeconfig, rollbackContext)
+	if err != nil {
+		return err
+	}
+	namespace := rollbackNamespace
+	if namespace == "" {
+		namespace, _, err = clientConfig.Namespace()
+		if err != nil {
+			return fmt.Errorf("could not determine namespace: %w", err)
+		}
+	}
+
+	es := &esv1.ExternalSecret{}
+	if err := kubeClient.Get(ctx, client.ObjectKey{Name: args[0], Namespace: namespace}, es); err != nil {
+		return fmt.Errorf("could not get ExternalSecret: %w", err)
+	}
+	if es.Spec.Target.History == nil {
+		return errors.New("the ExternalSecret has no history, set spec.target.history first")
+	}
+
+	switch {
+	case rollbackUnpin:
+		return setPinnedVersion(ctx, kubeClient, es, nil, cmd.OutOrStdout())
+	case rollbackToVersion != 0:
+		for _, v := range es.Status.History {
+			if v.Version == rollbackToVersion {
+				return setPinnedVersion(ctx, kubeClient, es, &rollbackToVersion, cmd.OutOrStdout())
+			}
+		}
+		return fmt.Errorf("version %d is not part of the history", rollbackToVersion)
+	default:
+		return printHistory(cmd.OutOrStdout(), es)
+	}
+}
+
+// setPinnedVersion sets or removes the annotation pinning the target, the controller picks up the change immediately.
+func setPinnedVersion(ctx context.Context, kubeClient client.Client, es *esv1.ExternalSecret, version *int64, out io.Writer) error {
+	patch := client.MergeFrom(es.DeepCopy())
+	if version == nil {
+		delete(es.Annotations, esv1.AnnotationPinnedVersion)
+	} else {
+		if es.Annotations == nil {
+			es.Annotations = make(map[string]string)
+		}
+		es.Annotations[esv1.AnnotationPinnedVersion] = strconv.FormatInt(*version, 10)
+	}
+	if err := kubeClient.Patch(ctx, es, patch); err != nil {
+		return fmt.Errorf("could not update ExternalSecret: %w", err)
+	}
+
+	if version == nil {
+		_, err := fmt.Fprintf(out, "externalsecret/%s unpinned\n", es.Name)
+		return err
+	}
+	_, err := fmt.Fprintf(out, "externalsecret/%s pinned to version %d\n", es.Name, *version)
+	return err
+}
+
+func printHistory(out io.Writer, es *esv1.ExternalSecret) error {
+	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+	_, _ = fmt.Fprintln(w, "VERSION\tSECRET\tCREATED\tPINNED")
+	for _, v := range es.Status.History {
+		pinned := ""
+		if v.Version == es.Status.PinnedVersion {
+			pinned = "*"
+		}
+		_, _ = fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", v.Version, v.SecretName, v.CreationTime.Format(time.RFC3339), pinned)
+	}
+	return w.Flush()
+}
@@ -248,6 +248,9 @@ Fail the install if a cluster scoped reconciler is enabled while its namespace s
 {{- if and (not .Values.processPushSecret) .Values.processClusterPushSecret -}}
   {{- fail "You have disabled processing of PushSecrets but not ClusterPushSecrets. This is an invalid configuration. ClusterPushSecret processing depends on processing of PushSecrets. Please either enable processing of PushSecrets, or disable processing of ClusterPushSecrets." }}
 {{- end -}}
+{{- if and .Values.notifications.enabled (not .Values.notifications.tokenSecret.name) (not .Values.notifications.allowUnauthenticated) -}}
+  {{- fail "Change notifications require a token. Please set notifications.tokenSecret.name, or set notifications.allowUnauthenticated to accept notifications without a token." }}
+{{- end -}}
 {{- end -}}
 
 {{/*
@@ -16,6 +16,7 @@ package cache
 
 import (
 	"fmt"
+	"time"
 
 	lru "github.com/hashicorp/golang-lru"
 )
@@ -25,6 +26,7 @@ import (
 // By design, this cache allows access to only a single version of a given key.
 // A version mismatch is considered a cache miss and the key gets evicted if it exists.
 // When a key is evicted a optional cleanup function is called.
+// Values added with a TTL are considered a cache miss once they expired.
 type Cache[T any] struct {
 	lru         *lru.Cache
 	size        int
@@ -36,11 +38,14 @@ type Key struct {
 	Name      string
 	Namespace string
 	Kind      string
+	// Subkey distinguishes multiple values of the same object.
+	Subkey string
 }
 
 type value[T any] struct {
 	Version string
 	Client  T
+	Expires time.Time
 }
 
 type cleanupFunc[T any] func(client T)
@@ -74,13 +79,14 @@ func Must[T any](size int, cleanup cleanupFunc[T]) *Cache[T] {
 }
 
 // Get retrieves the desired value using the key and
-// compares the version. If there is a mismatch
+// compares the version. If there is a mismatch or the value expired
 // it is considered a cache miss and the existing key is purged.
 func (c *Cache[T]) Get(version string, key Key) (T, bool) {
 	val, ok := c.lru.Get(key)
 	if ok {
 		cachedClient := val.(value[T])
-		if cachedClient.Version == version {
+		expired := !cachedClient.Expires.IsZero() && time.Now().After(cachedClient.Expires)
+		if cachedClient.Version == version && !expired {
 			return cachedClient.Client, true
 		}
 		c.lru.Remove(key)
@@ -93,7 +99,25 @@ func (c *Cache[T]) Add(version string, key Key, client T) {
 	c.lru.Add(key, value[T]{Version: version, Client: client})
 }
 
+// AddWithTTL adds a new value for the given key/version which expires after ttl.
+func (c *Cache[T]) AddWithTTL(version string, key Key, client T, ttl time.Duration) {
+	c.lru.Add(key, value[T]{Version: version, Client: client, Expires: time.Now().Add(ttl)})
+}
+
 // Contains returns true if a value with the given key exists.
 func (c *Cache[T]) Contains(key Key) bool {
 	return c.lru.Contains(key)
 }
+
+// RemoveFunc removes all values for which fn returns true.
+func (c *Cache[T]) RemoveFunc(fn func(key Key, value T) bool) int {
+	var removed int
+	for _, k := range c.lru.Keys() {
+		val, ok := c.lru.Peek(k)
+		if ok && fn(k.(Key), val.(value[T]).Client) {
+			c.lru.Remove(k)
+			removed++
+		}
+	}
+	return removed
+}
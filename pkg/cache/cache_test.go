@@ -16,6 +16,7 @@ package cache
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -98,3 +99,38 @@ func TestCacheEvict(t *testing.T) {
 	c.Add("", Key{Name: "bar"}, client{})
 	assert.True(t, cleanupCalled)
 }
+
+func TestCacheGetExpired(t *testing.T) {
+	c, err := New[client](2, nil)
+	if err != nil {
+		t.Fail()
+	}
+
+	c.AddWithTTL("", Key{Name: "foo", Subkey: "a"}, client{}, time.Hour)
+	c.AddWithTTL("", Key{Name: "foo", Subkey: "b"}, client{}, -time.Second)
+
+	_, ok := c.Get("", Key{Name: "foo", Subkey: "a"})
+	assert.True(t, ok)
+	_, ok = c.Get("", Key{Name: "foo", Subkey: "b"})
+	assert.False(t, ok)
+	assert.False(t, c.Contains(Key{Name: "foo", Subkey: "b"}))
+}
+
+func TestCacheRemoveFunc(t *testing.T) {
+	c, err := New[string](3, nil)
+	if err != nil {
+		t.Fail()
+	}
+
+	c.Add("", Key{Name: "foo", Subkey: "a"}, "a")
+	c.Add("", Key{Name: "foo", Subkey: "b"}, "b")
+	c.Add("", Key{Name: "bar", Subkey: "a"}, "a")
+
+	removed := c.RemoveFunc(func(key Key, value string) bool {
+		return key.Name == "foo" && value == "a"
+	})
+	assert.Equal(t, 1, removed)
+	assert.False(t, c.Contains(Key{Name: "foo", Subkey: "a"}))
+	assert.True(t, c.Contains(Key{Name: "foo", Subkey: "b"}))
+	assert.True(t, c.Contains(Key{Name: "bar", Subkey: "a"}))
+}
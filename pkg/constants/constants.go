@@ -29,6 +29,7 @@ const (
 
 	ProviderAWSPS                = "AWS/ParameterStore"
 	CallAWSPSGetParameter        = "GetParameter"
+	CallAWSPSGetParameters       = "GetParameters"
 	CallAWSPSPutParameter        = "PutParameter"
 	CallAWSPSDeleteParameter     = "DeleteParameter"
 	CallAWSPSDescribeParameter   = "DescribeParameter"
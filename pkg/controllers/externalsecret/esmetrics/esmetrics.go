@@ -15,6 +15,10 @@ limitations under the License.
 package esmetrics
 
 import (
+	"maps"
+	"slices"
+	"time"
+
 	"github.com/prometheus/client_golang/prometheus"
 	v1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/metrics"
@@ -29,6 +33,8 @@ const (
 	SyncCallsErrorKey                  = "sync_calls_error"
 	ExternalSecretStatusConditionKey   = "status_condition"
 	ExternalSecretReconcileDurationKey = "reconcile_duration"
+	DriftDetectedKey                   = "drift_detected_total"
+	KeyExpiryKey                       = "key_expiry_seconds"
 )
 
 var counterVecMetrics = map[string]*prometheus.CounterVec{}
@@ -51,6 +57,12 @@ func SetUpMetrics() {
 		Help:      "Total number of the External Secret sync errors",
 	}, ctrlmetrics.NonConditionMetricLabelNames)
 
+	driftDetected := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: ExternalSecretSubsystem,
+		Name:      DriftDetectedKey,
+		Help:      "Total number of times the target of an External Secret was changed since the last sync",
+	}, ctrlmetrics.NonConditionMetricLabelNames)
+
 	externalSecretCondition := prometheus.NewGaugeVec(prometheus.GaugeOpts{
 		Subsystem: ExternalSecretSubsystem,
 		Name:      ExternalSecretStatusConditionKey,
@@ -63,19 +75,40 @@ func SetUpMetrics() {
 		Help:      "The duration time to reconcile the External Secret",
 	}, ctrlmetrics.NonConditionMetricLabelNames)
 
-	metrics.Registry.MustRegister(syncCallsTotal, syncCallsError, externalSecretCondition, externalSecretReconcileDuration)
+	keyExpiry := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: ExternalSecretSubsystem,
+		Name:      KeyExpiryKey,
+		Help:      "The time an entry of a specific External Secret expires, in seconds since the epoch",
+	}, append(slices.Clone(ctrlmetrics.NonConditionMetricLabelNames), "path", "secret_key"))
+
+	metrics.Registry.MustRegister(syncCallsTotal, syncCallsError, driftDetected, externalSecretCondition, externalSecretReconcileDuration, keyExpiry)
 
 	counterVecMetrics = map[string]*prometheus.CounterVec{
 		SyncCallsKey:      syncCallsTotal,
 		SyncCallsErrorKey: syncCallsError,
+		DriftDetectedKey:  driftDetected,
 	}
 
 	gaugeVecMetrics = map[string]*prometheus.GaugeVec{
 		ExternalSecretStatusConditionKey:   externalSecretCondition,
 		ExternalSecretReconcileDurationKey: externalSecretReconcileDuration,
+		KeyExpiryKey:                       keyExpiry,
 	}
 }
 
+// SetKeyExpiry sets the expiry of an entry of an External Secret.
+func SetKeyExpiry(resourceLabels prometheus.Labels, path, secretKey string, expiry time.Time) {
+	labels := maps.Clone(resourceLabels)
+	labels["path"] = path
+	labels["secret_key"] = secretKey
+	GetGaugeVec(KeyExpiryKey).With(labels).Set(float64(expiry.Unix()))
+}
+
+// RemoveKeyExpiry removes the expiry of all entries of an External Secret.
+func RemoveKeyExpiry(name, namespace string) {
+	GetGaugeVec(KeyExpiryKey).DeletePartialMatch(prometheus.Labels{"name": name, "namespace": namespace})
+}
+
 func UpdateExternalSecretCondition(es *esv1.ExternalSecret, condition *esv1.ExternalSecretStatusCondition, value float64) {
 	esInfo := make(map[string]string)
 	esInfo["name"] = es.Name
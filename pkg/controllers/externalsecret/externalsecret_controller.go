@@ -46,12 +46,17 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
 	// Metrics.
 	"github.com/external-secrets/external-secrets/pkg/controllers/externalsecret/esmetrics"
 	ctrlmetrics "github.com/external-secrets/external-secrets/pkg/controllers/metrics"
+	"github.com/external-secrets/external-secrets/pkg/controllers/secretstore"
 	"github.com/external-secrets/external-secrets/pkg/controllers/util"
+	"github.com/external-secrets/external-secrets/pkg/metrics"
+	"github.com/external-secrets/external-secrets/pkg/notifications"
+	"github.com/external-secrets/external-secrets/pkg/sharding"
 	"github.com/external-secrets/external-secrets/pkg/utils"
 	"github.com/external-secrets/external-secrets/pkg/utils/resolvers"
 
@@ -84,6 +89,9 @@ const (
 	msgErrorBecomeOwner     = "failed to take ownership of target secret"
 	msgErrorIsOwned         = "target is owned by another ExternalSecret"
 
+	// condition messages for "SecretSyncThrottled" reason.
+	msgErrorThrottled = "rate limit of the store exceeded, sync will be retried"
+
 	// log messages.
 	logErrorGetES                = "unable to get ExternalSecret"
 	logErrorUpdateESStatus       = "unable to update ExternalSecret status"
@@ -108,12 +116,13 @@ const (
 	errSecretCachesNotSynced = "controller caches for secret %s are not in sync"
 
 	// event messages.
-	eventCreated                  = "secret created"
-	eventUpdated                  = "secret updated"
-	eventDeleted                  = "secret deleted due to DeletionPolicy=Delete"
-	eventDeletedOrphaned          = "secret deleted because it was orphaned"
-	eventMissingProviderSecret    = "secret does not exist at provider using spec.dataFrom[%d]"
-	eventMissingProviderSecretKey = "secret does not exist at provider using spec.dataFrom[%d] (key=%s)"
+	eventCreated                      = "secret created"
+	eventUpdated                      = "secret updated"
+	eventDeleted                      = "secret deleted due to DeletionPolicy=Delete"
+	eventDeletedOrphaned              = "secret deleted because it was orphaned"
+	eventMissingProviderSecret        = "secret does not exist at provider using spec.dataFrom[%d]"
+	eventMissingProviderSecretKey     = "secret does not exist at provider using spec.dataFrom[%d] (key=%s)"
+	eventMissingProviderSecretDataKey = "optional secret does not exist at provider using spec.data[%d] (key=%s)"
 )
 
 // these errors are explicitly defined so we can detect them with `errors.Is()`.
@@ -138,7 +147,24 @@ type Reconciler struct {
 	ClusterSecretStoreEnabled bool
 	EnableFloodGate           bool
 	EnableGeneratorState      bool
-	recorder                  record.EventRecorder
+	AllowGenericTargets       bool
+	EnableRolloutTriggers     bool
+	// Notifications requests refreshes of ExternalSecrets whose remote keys changed, nil if disabled.
+	// ExternalSecrets are indexed by their remote keys for it.
+	Notifications *notifications.Dispatcher
+	// ResponseCache caches provider responses across reconciles, nil if disabled.
+	ResponseCache *secretstore.ResponseCache
+	// ClientPool reuses provider clients across reconciles, nil if disabled.
+	ClientPool *secretstore.ClientPool
+	// Shards limits the reconciled ExternalSecrets to the shards held by this replica, nil if disabled.
+	Shards *sharding.Sharder
+	// Schedule spreads periodic refreshes over time, nil if disabled.
+	Schedule *util.Schedule
+	// ExpiryWarningWindow is the time before the expiry of an entry in which warning events are emitted, 0 if disabled.
+	ExpiryWarningWindow time.Duration
+	// HashKey keys the hashes of secret values written to the status, see utils.KeyedHash.
+	HashKey  []byte
+	recorder record.EventRecorder
 }
 
 // Reconcile implements the main reconciliation loop
@@ -172,6 +198,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ct
 					Namespace: req.Namespace,
 				},
 			}, *conditionSynced)
+			esmetrics.RemoveKeyExpiry(req.Name, req.Namespace)
 
 			return ctrl.Result{}, nil
 		}
@@ -181,6 +208,15 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ct
 		return ctrl.Result{}, err
 	}
 
+	// skip this ExternalSecret if it belongs to a shard held by another replica
+	if r.Shards != nil {
+		if !r.Shards.Owns(externalSecret) {
+			log.V(1).Info("skipping ExternalSecret, its shard is held by another replica")
+			return ctrl.Result{}, nil
+		}
+		metrics.ObserveShardReconcile(r.Shards.Shard(externalSecret))
+	}
+
 	// skip reconciliation if deletion timestamp is set on external secret
 	if !externalSecret.GetDeletionTimestamp().IsZero() {
 		log.V(1).Info("skipping ExternalSecret, it is marked for deletion")
@@ -214,6 +250,16 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ct
 		secretName = externalSecret.Name
 	}
 
+	// in dry-run mode the changes to the target are computed, but never applied
+	if externalSecret.Spec.DryRun {
+		return r.reconcileDryRun(ctx, log, externalSecret, secretName, start, syncCallsError.With(resourceLabels))
+	}
+
+	// targets other than Secrets (ConfigMaps, custom resources) are reconciled separately
+	if isGenericTarget(externalSecret) {
+		return r.reconcileGenericTarget(ctx, log, externalSecret, secretName, start, resourceLabels, syncCallsError.With(resourceLabels))
+	}
+
 	// fetch the existing secret (from the partial cache)
 	//  - please note that the ~partial cache~ is different from the ~full cache~
 	//    so there can be race conditions between the two caches
@@ -273,6 +319,18 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ct
 		return ctrl.Result{}, err
 	}
 
+	// NOTE: we dereference the DeepCopy of the status field because status fields are NOT pointers,
+	//       so otherwise the `equality.Semantic.DeepEqual` will always return false.
+	currentStatus := *externalSecret.Status.DeepCopy()
+
+	// detect changes of the target secret by others since the last sync.
+	// with DriftPolicy=Report, a drifted secret is left as it is until the next refresh,
+	// unless it lost the "managed" label or the "data-hash" annotation.
+	secretValid := isSecretValid(existingSecret, externalSecret)
+	if r.detectDrift(externalSecret, existingSecret, resourceLabels) {
+		secretValid = externalSecret.Spec.Target.DriftPolicy == esv1.DriftPolicyReport && hasManagedMetadata(existingSecret, externalSecret)
+	}
+
 	// refresh will be skipped if ALL the following conditions are met:
 	// 1. refresh interval is not 0
 	// 2. resource generation of the ExternalSecret has not changed
@@ -281,55 +339,58 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ct
 	//     - it exists
 	//     - it has the correct "managed" label
 	//     - it has the correct "data-hash" annotation
-	if !shouldRefresh(externalSecret) && isSecretValid(existingSecret, externalSecret) {
-		log.V(1).Info("skipping refresh")
-		return r.getRequeueResult(externalSecret), nil
+	//     - it did not drift since the last sync, unless DriftPolicy=Report and only its data or template metadata changed
+	// 5. no restart of the workloads in spec.rollout is pending
+	// a periodic refresh which is due may still be postponed by the startup stagger or the refresh budget of the store.
+	// NOTE: the status is only updated if a drift was reported.
+	if secretValid && !r.isRolloutPending(externalSecret, existingSecret) {
+		if !shouldRefresh(externalSecret) {
+			log.V(1).Info("skipping refresh")
+			return r.updateStatus(ctx, log, externalSecret, currentStatus, r.getRequeueResult(externalSecret), nil)
+		}
+		if delay := r.postponeRefresh(ctx, externalSecret); delay > 0 {
+			log.V(1).Info("postponing refresh", "delay", delay)
+			return r.updateStatus(ctx, log, externalSecret, currentStatus, ctrl.Result{RequeueAfter: delay}, nil)
+		}
 	}
 
 	// update status of the ExternalSecret when this function returns, if needed.
 	// NOTE: we use the ability of deferred functions to update named return values `result` and `err`
-	// NOTE: we dereference the DeepCopy of the status field because status fields are NOT pointers,
-	//       so otherwise the `equality.Semantic.DeepEqual` will always return false.
-	currentStatus := *externalSecret.Status.DeepCopy()
 	defer func() {
-		// if the status has not changed, we don't need to update it
-		if equality.Semantic.DeepEqual(currentStatus, externalSecret.Status) {
-			return
-		}
+		result, err = r.updateStatus(ctx, log, externalSecret, currentStatus, result, err)
+	}()
+	externalSecret.Status.DryRun = nil
 
-		// update the status of the ExternalSecret, storing any error in a new variable
-		// if there was no new error, we don't need to change the `result` or `err` values
-		updateErr := r.Status().Update(ctx, externalSecret)
-		if updateErr == nil {
-			return
+	// if the target is pinned to a version of its history, that version is applied instead of the provider data.
+	// NOTE: an invalid version cant be fixed by retrying so we don't return an error (which would requeue immediately)
+	pinned, err := r.getPinnedVersion(ctx, externalSecret)
+	if err != nil {
+		r.markAsFailed(msgErrorPinnedVersion, err, externalSecret, syncCallsError.With(resourceLabels))
+		if errors.Is(err, ErrInvalidPinnedVersion) {
+			return ctrl.Result{}, nil
 		}
+		return ctrl.Result{}, err
+	}
 
-		// if we got an update conflict, we should requeue immediately
-		if apierrors.IsConflict(updateErr) {
-			log.V(1).Info("conflict while updating status, will requeue")
-
-			// we only explicitly request a requeue if the main function did not return an `err`.
-			// otherwise, we get an annoying log saying that results are ignored when there is an error,
-			// as errors are always retried.
-			if err == nil {
-				result = ctrl.Result{Requeue: true}
-			}
-			return
+	// retrieve the provider secret data.
+	var dataMap map[string][]byte
+	if pinned != nil {
+		dataMap = pinned.Data
+	} else {
+		var keys *keyStatuses
+		dataMap, keys, err = r.getProviderSecretData(ctx, externalSecret)
+		r.reportExpiry(externalSecret, resourceLabels)
+		if err != nil {
+			r.markAsFailed(msgErrorGetSecretData, err, externalSecret, syncCallsError.With(resourceLabels))
+			return ctrl.Result{}, err
 		}
 
-		// for other errors, log and update the `err` variable if there is no error already
-		// so the reconciler will requeue the request
-		log.Error(updateErr, logErrorUpdateESStatus)
-		if err == nil {
-			err = updateErr
+		// the target is not rendered again if the provider returned the versions of the last sync for all entries.
+		if secretValid && !r.isRolloutPending(externalSecret, existingSecret) && versionsUnchanged(externalSecret, currentStatus, existingSecret, keys) {
+			log.V(1).Info("skipping update, the versions of the remote secrets did not change")
+			r.markAsDone(externalSecret, start, log, esv1.ConditionReasonSecretSynced, msgSynced)
+			return r.getRequeueResult(externalSecret), nil
 		}
-	}()
-
-	// retrieve the provider secret data.
-	dataMap, err := r.GetProviderSecretData(ctx, externalSecret)
-	if err != nil {
-		r.markAsFailed(msgErrorGetSecretData, err, externalSecret, syncCallsError.With(resourceLabels))
-		return ctrl.Result{}, err
 	}
 
 	// if no data was found we can delete the secret if needed.
@@ -369,96 +430,19 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ct
 	}
 
 	// mutationFunc is a function which can be applied to a secret to make it match the desired state.
+	// the rendered secret is kept, so it can be recorded in the history of the target and trigger rollouts.
+	var rendered *v1.Secret
 	mutationFunc := func(secret *v1.Secret) error {
-		// get information about the current owner of the secret
-		//  - we ignore the API version as it can change over time
-		//  - we ignore the UID for consistency with the SetControllerReference function
-		currentOwner := metav1.GetControllerOf(secret)
-		ownerIsESKind := false
-		ownerIsCurrentES := false
-		if currentOwner != nil {
-			currentOwnerGK := schema.FromAPIVersionAndKind(currentOwner.APIVersion, currentOwner.Kind).GroupKind()
-			ownerIsESKind = currentOwnerGK.String() == esv1.ExtSecretGroupKind
-			ownerIsCurrentES = ownerIsESKind && currentOwner.Name == externalSecret.Name
-		}
-
-		// if another ExternalSecret is the owner, we should return an error
-		// otherwise the controller will fight with itself to update the secret.
-		// note, this does not prevent other controllers from owning the secret.
-		if ownerIsESKind && !ownerIsCurrentES {
-			return fmt.Errorf("%w: %s", ErrSecretIsOwned, currentOwner.Name)
-		}
-
-		// if the CreationPolicy is Owner, we should set ourselves as the owner of the secret
-		if externalSecret.Spec.Target.CreationPolicy == esv1.CreatePolicyOwner {
-			err = controllerutil.SetControllerReference(externalSecret, secret, r.Scheme)
-			if err != nil {
-				return fmt.Errorf("%w: %w", ErrSecretSetCtrlRef, err)
-			}
-		}
-
-		// if the creation policy is not Owner, we should remove ourselves as the owner
-		// this could happen if the creation policy was changed after the secret was created
-		if externalSecret.Spec.Target.CreationPolicy != esv1.CreatePolicyOwner && ownerIsCurrentES {
-			err = controllerutil.RemoveControllerReference(externalSecret, secret, r.Scheme)
-			if err != nil {
-				return fmt.Errorf("%w: %w", ErrSecretRemoveCtrlRef, err)
-			}
-		}
-
-		// initialize maps within the secret so it's safe to set values
-		if secret.Annotations == nil {
-			secret.Annotations = make(map[string]string)
-		}
-		if secret.Labels == nil {
-			secret.Labels = make(map[string]string)
-		}
-		if secret.Data == nil {
-			secret.Data = make(map[string][]byte)
-		}
-
-		// set the immutable flag on the secret if requested by the ExternalSecret
-		if externalSecret.Spec.Target.Immutable {
-			secret.Immutable = ptr.To(true)
-		}
-
-		// only apply the template if the secret is mutable or if the secret is new (has no UID)
-		// otherwise we would mutate an object that is immutable and already exists
-		objectDoesNotExistOrCanBeMutated := secret.GetUID() == "" || !externalSecret.Spec.Target.Immutable
-
-		if objectDoesNotExistOrCanBeMutated {
-			// get the list of keys that are managed by this ExternalSecret
-			keys, err := getManagedDataKeys(secret, externalSecret.Name)
-			if err != nil {
-				return err
-			}
-			// remove any data keys that are managed by this ExternalSecret, so we can re-add them
-			// this ensures keys added by templates are not left behind when they are removed from the template
-			for _, key := range keys {
-				delete(secret.Data, key)
-			}
-
-			// WARNING: this will remove any labels or annotations managed by this ExternalSecret
-			//          so any updates to labels and annotations should be done AFTER this point
-			err = r.ApplyTemplate(ctx, externalSecret, secret, dataMap)
-			if err != nil {
-				return fmt.Errorf(errApplyTemplate, err)
-			}
-		}
-
-		// we also use a label to keep track of the owner of the secret
-		// this lets us remove secrets that are no longer needed if the target secret name changes
-		if externalSecret.Spec.Target.CreationPolicy == esv1.CreatePolicyOwner {
-			lblValue := utils.ObjectHash(fmt.Sprintf("%v/%v", externalSecret.Namespace, externalSecret.Name))
-			secret.Labels[esv1.LabelOwner] = lblValue
+		var err error
+		if pinned != nil {
+			err = r.mutateSecretFromHistory(externalSecret, secret, pinned)
 		} else {
-			// the label should not be set if the creation policy is not Owner
-			delete(secret.Labels, esv1.LabelOwner)
+			err = r.mutateSecret(ctx, externalSecret, secret, dataMap)
 		}
-
-		secret.Labels[esv1.LabelManaged] = esv1.LabelManagedValue
-		secret.Annotations[esv1.AnnotationDataHash] = utils.ObjectHash(secret.Data)
-
+		if err != nil {
+			return err
+		}
+		rendered = secret
 		return nil
 	}
 
@@ -533,8 +517,104 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ct
 		return ctrl.Result{}, err
 	}
 
-	r.markAsDone(externalSecret, start, log, esv1.ConditionReasonSecretSynced, msgSynced)
-	return r.getRequeueResult(externalSecret), nil
+	// keep the hash of the written secret to detect drift
+	recordTarget(externalSecret, rendered)
+
+	// record the rendered secret as a new version and remove the versions exceeding the limit
+	err = r.reconcileHistory(ctx, externalSecret, secretName, rendered)
+	if err != nil {
+		r.markAsFailed(msgErrorHistory, err, externalSecret, syncCallsError.With(resourceLabels))
+		return ctrl.Result{}, err
+	}
+
+	// restart the workloads in spec.rollout if the data changed
+	rolloutAfter, err := r.reconcileRollout(ctx, externalSecret, rendered)
+	if err != nil {
+		r.markAsFailed(msgErrorRollout, err, externalSecret, syncCallsError.With(resourceLabels))
+		return ctrl.Result{}, err
+	}
+
+	if pinned != nil {
+		r.markAsDone(externalSecret, start, log, esv1.ConditionReasonSecretPinned, fmt.Sprintf(msgPinned, externalSecret.Status.PinnedVersion))
+	} else {
+		r.markAsDone(externalSecret, start, log, esv1.ConditionReasonSecretSynced, msgSynced)
+	}
+	result = r.getRequeueResult(externalSecret)
+	if rolloutAfter > 0 && !result.Requeue && (result.RequeueAfter == 0 || rolloutAfter < result.RequeueAfter) {
+		result = ctrl.Result{RequeueAfter: rolloutAfter}
+	}
+	return result, nil
+}
+
+// mutateSecret applies the ExternalSecret to the given secret, so it matches the desired state.
+func (r *Reconciler) mutateSecret(ctx context.Context, externalSecret *esv1.ExternalSecret, secret *v1.Secret, dataMap map[string][]byte) error {
+	return r.mutateSecretWith(externalSecret, secret, func() error {
+		return r.ApplyTemplate(ctx, externalSecret, secret, dataMap)
+	})
+}
+
+// mutateSecretWith sets up the given secret to be managed by the ExternalSecret,
+// using applyData to set its data, labels and annotations.
+func (r *Reconciler) mutateSecretWith(externalSecret *esv1.ExternalSecret, secret *v1.Secret, applyData func() error) error {
+	// make sure we are the only ExternalSecret managing the secret and set the owner reference if needed
+	if err := r.reconcileOwnerReference(externalSecret, secret); err != nil {
+		return err
+	}
+
+	// initialize maps within the secret so it's safe to set values
+	if secret.Annotations == nil {
+		secret.Annotations = make(map[string]string)
+	}
+	if secret.Labels == nil {
+		secret.Labels = make(map[string]string)
+	}
+	if secret.Data == nil {
+		secret.Data = make(map[string][]byte)
+	}
+
+	// set the immutable flag on the secret if requested by the ExternalSecret
+	if externalSecret.Spec.Target.Immutable {
+		secret.Immutable = ptr.To(true)
+	}
+
+	// only apply the template if the secret is mutable or if the secret is new (has no UID)
+	// otherwise we would mutate an object that is immutable and already exists
+	objectDoesNotExistOrCanBeMutated := secret.GetUID() == "" || !externalSecret.Spec.Target.Immutable
+
+	if objectDoesNotExistOrCanBeMutated {
+		// get the list of keys that are managed by this ExternalSecret
+		keys, err := getManagedDataKeys(secret, externalSecret.Name)
+		if err != nil {
+			return err
+		}
+		// remove any data keys that are managed by this ExternalSecret, so we can re-add them
+		// this ensures keys added by templates are not left behind when they are removed from the template
+		for _, key := range keys {
+			delete(secret.Data, key)
+		}
+
+		// WARNING: this will remove any labels or annotations managed by this ExternalSecret
+		//          so any updates to labels and annotations should be done AFTER this point
+		err = applyData()
+		if err != nil {
+			return fmt.Errorf(errApplyTemplate, err)
+		}
+	}
+
+	// we also use a label to keep track of the owner of the secret
+	// this lets us remove secrets that are no longer needed if the target secret name changes
+	if externalSecret.Spec.Target.CreationPolicy == esv1.CreatePolicyOwner {
+		lblValue := utils.ObjectHash(fmt.Sprintf("%v/%v", externalSecret.Namespace, externalSecret.Name))
+		secret.Labels[esv1.LabelOwner] = lblValue
+	} else {
+		// the label should not be set if the creation policy is not Owner
+		delete(secret.Labels, esv1.LabelOwner)
+	}
+
+	secret.Labels[esv1.LabelManaged] = esv1.LabelManagedValue
+	secret.Annotations[esv1.AnnotationDataHash] = utils.ObjectHash(secret.Data)
+
+	return nil
 }
 
 // getRequeueResult create a result with requeueAfter based on the ExternalSecret refresh interval.
@@ -555,7 +635,7 @@ func (r *Reconciler) getRequeueResult(externalSecret *esv1.ExternalSecret) ctrl.
 	// note, this should not happen, as we only call this function on ExternalSecrets
 	// that have been reconciled at least once
 	if externalSecret.Status.RefreshTime.IsZero() {
-		return ctrl.Result{RequeueAfter: refreshInterval}
+		return ctrl.Result{RequeueAfter: refreshInterval + r.Schedule.Jitter(refreshInterval)}
 	}
 
 	timeSinceLastRefresh := time.Since(externalSecret.Status.RefreshTime.Time)
@@ -568,14 +648,52 @@ func (r *Reconciler) getRequeueResult(externalSecret *esv1.ExternalSecret) ctrl.
 	}
 
 	// if there is time remaining, requeue after the remaining time
+	// the jitter keeps ExternalSecrets refreshed at the same time from staying aligned
 	if timeSinceLastRefresh < refreshInterval {
-		return ctrl.Result{RequeueAfter: refreshInterval - timeSinceLastRefresh}
+		return ctrl.Result{RequeueAfter: refreshInterval - timeSinceLastRefresh + r.Schedule.Jitter(refreshInterval)}
 	}
 
 	// otherwise, requeue immediately
 	return ctrl.Result{Requeue: true}
 }
 
+// updateStatus persists the status of the ExternalSecret if it differs from currentStatus,
+// and returns the result and error the reconcile loop should return.
+func (r *Reconciler) updateStatus(ctx context.Context, log logr.Logger, externalSecret *esv1.ExternalSecret, currentStatus esv1.ExternalSecretStatus, result ctrl.Result, err error) (ctrl.Result, error) {
+	// if the status has not changed, we don't need to update it
+	if equality.Semantic.DeepEqual(currentStatus, externalSecret.Status) {
+		return result, err
+	}
+
+	// update the status of the ExternalSecret, storing any error in a new variable
+	// if there was no new error, we don't need to change the `result` or `err` values
+	updateErr := r.Status().Update(ctx, externalSecret)
+	if updateErr == nil {
+		return result, err
+	}
+
+	// if we got an update conflict, we should requeue immediately
+	if apierrors.IsConflict(updateErr) {
+		log.V(1).Info("conflict while updating status, will requeue")
+
+		// we only explicitly request a requeue if the main function did not return an `err`.
+		// otherwise, we get an annoying log saying that results are ignored when there is an error,
+		// as errors are always retried.
+		if err == nil {
+			result = ctrl.Result{Requeue: true}
+		}
+		return result, err
+	}
+
+	// for other errors, log and update the `err` variable if there is no error already
+	// so the reconciler will requeue the request
+	log.Error(updateErr, logErrorUpdateESStatus)
+	if err == nil {
+		err = updateErr
+	}
+	return result, err
+}
+
 func (r *Reconciler) markAsDone(externalSecret *esv1.ExternalSecret, start time.Time, log logr.Logger, reason, msg string) {
 	oldReadyCondition := GetExternalSecretCondition(externalSecret.Status, esv1.ExternalSecretReady)
 	newReadyCondition := NewExternalSecretCondition(esv1.ExternalSecretReady, v1.ConditionTrue, reason, msg)
@@ -597,8 +715,16 @@ func (r *Reconciler) markAsDone(externalSecret *esv1.ExternalSecret, start time.
 }
 
 func (r *Reconciler) markAsFailed(msg string, err error, externalSecret *esv1.ExternalSecret, counter prometheus.Counter) {
-	r.recorder.Event(externalSecret, v1.EventTypeWarning, esv1.ReasonUpdateFailed, err.Error())
-	conditionSynced := NewExternalSecretCondition(esv1.ExternalSecretReady, v1.ConditionFalse, esv1.ConditionReasonSecretSyncedError, msg)
+	reason, eventReason := esv1.ConditionReasonSecretSyncedError, esv1.ReasonUpdateFailed
+	// failed validation rules are not a sync error, they are fixed by correcting the data at the provider.
+	switch {
+	case errors.Is(err, ErrSecretValidation):
+		reason, eventReason, msg = esv1.ConditionReasonSecretValidationFailed, esv1.ReasonValidationFailed, msgErrorValidation
+	case errors.Is(err, secretstore.ErrThrottled):
+		reason, eventReason, msg = esv1.ConditionReasonSecretSyncThrottled, esv1.ReasonThrottled, msgErrorThrottled
+	}
+	r.recorder.Event(externalSecret, v1.EventTypeWarning, eventReason, err.Error())
+	conditionSynced := NewExternalSecretCondition(esv1.ExternalSecretReady, v1.ConditionFalse, reason, msg)
 	SetExternalSecretCondition(externalSecret, *conditionSynced)
 	counter.Inc()
 }
@@ -739,6 +865,47 @@ func (r *Reconciler) updateSecret(ctx context.Context, existingSecret *v1.Secret
 	return nil
 }
 
+// reconcileOwnerReference ensures that the target object is not managed by another ExternalSecret,
+// and sets or removes the controller reference depending on the CreationPolicy.
+func (r *Reconciler) reconcileOwnerReference(es *esv1.ExternalSecret, obj metav1.Object) error {
+	// get information about the current owner of the object
+	//  - we ignore the API version as it can change over time
+	//  - we ignore the UID for consistency with the SetControllerReference function
+	currentOwner := metav1.GetControllerOf(obj)
+	ownerIsESKind := false
+	ownerIsCurrentES := false
+	if currentOwner != nil {
+		currentOwnerGK := schema.FromAPIVersionAndKind(currentOwner.APIVersion, currentOwner.Kind).GroupKind()
+		ownerIsESKind = currentOwnerGK.String() == esv1.ExtSecretGroupKind
+		ownerIsCurrentES = ownerIsESKind && currentOwner.Name == es.Name
+	}
+
+	// if another ExternalSecret is the owner, we should return an error
+	// otherwise the controller will fight with itself to update the object.
+	// note, this does not prevent other controllers from owning the object.
+	if ownerIsESKind && !ownerIsCurrentES {
+		return fmt.Errorf("%w: %s", ErrSecretIsOwned, currentOwner.Name)
+	}
+
+	// if the CreationPolicy is Owner, we should set ourselves as the owner of the object
+	if es.Spec.Target.CreationPolicy == esv1.CreatePolicyOwner {
+		err := controllerutil.SetControllerReference(es, obj, r.Scheme)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrSecretSetCtrlRef, err)
+		}
+	}
+
+	// if the creation policy is not Owner, we should remove ourselves as the owner
+	// this could happen if the creation policy was changed after the object was created
+	if es.Spec.Target.CreationPolicy != esv1.CreatePolicyOwner && ownerIsCurrentES {
+		err := controllerutil.RemoveControllerReference(es, obj, r.Scheme)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrSecretRemoveCtrlRef, err)
+		}
+	}
+	return nil
+}
+
 // getManagedDataKeys returns the list of data keys in a secret which are managed by a specified owner.
 func getManagedDataKeys(secret *v1.Secret, fieldOwner string) ([]string, error) {
 	return getManagedFieldKeys(secret, fieldOwner, func(fields map[string]any) []string {
@@ -782,7 +949,18 @@ func getManagedFieldKeys(
 }
 
 func shouldSkipClusterSecretStore(r *Reconciler, es *esv1.ExternalSecret) bool {
-	return !r.ClusterSecretStoreEnabled && es.Spec.SecretStoreRef.Kind == esv1.ClusterSecretStoreKind
+	if r.ClusterSecretStoreEnabled {
+		return false
+	}
+	if es.Spec.SecretStoreRef.Kind == esv1.ClusterSecretStoreKind {
+		return true
+	}
+	for _, ref := range es.Spec.FallbackStoreRefs {
+		if ref.Kind == esv1.ClusterSecretStoreKind {
+			return true
+		}
+	}
+	return false
 }
 
 // shouldSkipUnmanagedStore iterates over all secretStore references in the externalSecret spec,
@@ -794,6 +972,7 @@ func shouldSkipUnmanagedStore(ctx context.Context, namespace string, r *Reconcil
 	if es.Spec.SecretStoreRef.Name != "" {
 		storeList = append(storeList, es.Spec.SecretStoreRef)
 	}
+	storeList = append(storeList, es.Spec.FallbackStoreRefs...)
 
 	for _, ref := range es.Spec.Data {
 		if ref.SourceRef != nil {
@@ -862,6 +1041,13 @@ func shouldSkipUnmanagedStore(ctx context.Context, namespace string, r *Reconcil
 	return false, nil
 }
 
+// isResyncRequested returns true if the ExternalSecret changed since the last refresh, e.g. by updating
+// the force-sync annotation, which change notifications do as well.
+// The data is then fetched from the providers rather than the response cache.
+func isResyncRequested(es *esv1.ExternalSecret) bool {
+	return es.Status.SyncedResourceVersion != "" && es.Status.SyncedResourceVersion != util.GetResourceVersion(es.ObjectMeta)
+}
+
 func shouldRefresh(es *esv1.ExternalSecret) bool {
 	switch es.Spec.RefreshPolicy {
 	case esv1.RefreshPolicyCreatedOnce:
@@ -935,6 +1121,12 @@ func isSecretValid(existingSecret *v1.Secret, es *esv1.ExternalSecret) bool {
 	return true
 }
 
+// SetEventRecorder sets the recorder used to emit events.
+// It is set up by SetupWithManager and only needs to be set if the Reconciler is used without a manager, e.g. by esoctl.
+func (r *Reconciler) SetEventRecorder(recorder record.EventRecorder) {
+	r.recorder = recorder
+}
+
 // SetupWithManager returns a new controller builder that will be started by the provided Manager.
 func (r *Reconciler) SetupWithManager(mgr ctrl.Manager, opts controller.Options) error {
 	r.recorder = mgr.GetEventRecorderFor("external-secrets")
@@ -953,13 +1145,21 @@ func (r *Reconciler) SetupWithManager(mgr ctrl.Manager, opts controller.Options)
 		return err
 	}
 
+	// index ExternalSecrets based on their remote keys,
+	// this lets us quickly find all ExternalSecrets which reference a key a provider notified a change of
+	if r.Notifications != nil {
+		if err := mgr.GetFieldIndexer().IndexField(context.Background(), &esv1.ExternalSecret{}, notifications.IndexRemoteKeyField, notifications.RemoteKeys); err != nil {
+			return err
+		}
+	}
+
 	// predicate function to ignore secret events unless they have the "managed" label
 	secretHasESLabel := predicate.NewPredicateFuncs(func(object client.Object) bool {
 		value, hasLabel := object.GetLabels()[esv1.LabelManaged]
 		return hasLabel && value == esv1.LabelManagedValue
 	})
 
-	return ctrl.NewControllerManagedBy(mgr).
+	b := ctrl.NewControllerManagedBy(mgr).
 		WithOptions(opts).
 		For(&esv1.ExternalSecret{}).
 		// we cant use Owns(), as we don't set ownerReferences when the creationPolicy is not Owner.
@@ -968,8 +1168,20 @@ func (r *Reconciler) SetupWithManager(mgr ctrl.Manager, opts controller.Options)
 			&v1.Secret{},
 			handler.EnqueueRequestsFromMapFunc(r.findObjectsForSecret),
 			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{}, secretHasESLabel),
-		).
-		Complete(r)
+		)
+	// ConfigMap targets are watched like Secrets. Custom resource targets are not watched,
+	// as their kinds are only known from the ExternalSecrets, so they are restored on the next refresh.
+	if r.AllowGenericTargets {
+		b = b.WatchesMetadata(
+			&v1.ConfigMap{},
+			handler.EnqueueRequestsFromMapFunc(r.findObjectsForSecret),
+			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{}, secretHasESLabel),
+		)
+	}
+	if r.Shards != nil {
+		b = b.WatchesRawSource(source.Channel(r.Shards.Events(), &handler.EnqueueRequestForObject{}))
+	}
+	return b.Complete(r)
 }
 
 func (r *Reconciler) findObjectsForSecret(ctx context.Context, secret client.Object) []reconcile.Request {
@@ -0,0 +1,89 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalsecret
+
+import (
+	"context"
+	"errors"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	"github.com/external-secrets/external-secrets/pkg/controllers/secretstore"
+)
+
+// prefetchedSecret is the result of GetSecret for an entry of spec.data, fetched in a batch.
+type prefetchedSecret struct {
+	value    []byte
+	metadata *esv1.SecretMetadata
+	err      error
+}
+
+// prefetchSecretData fetches the entries of spec.data in batches, if their store implements esv1.BatchSecretsClient.
+// Entries are grouped by the store of their sourceRef, entries without one use spec.secretStoreRef
+// and spec.fallbackStoreRefs. It returns the results by the index of the entry.
+//
+// Only values and esv1.NoSecretErr are returned, entries which failed otherwise or could not be
+// fetched in a batch are left to handleSecretData, which fetches them one by one and applies the fallback stores.
+func (r *Reconciler) prefetchSecretData(ctx context.Context, externalSecret *esv1.ExternalSecret, cmgr *secretstore.Manager) map[int]prefetchedSecret {
+	// the zero value is the group of the entries without a sourceRef
+	var order []esv1.SecretStoreRef
+	groups := make(map[esv1.SecretStoreRef][]int)
+	for i, secretRef := range externalSecret.Spec.Data {
+		var storeRef esv1.SecretStoreRef
+		if secretRef.SourceRef != nil {
+			storeRef = secretRef.SourceRef.SecretStoreRef
+		}
+		if _, ok := groups[storeRef]; !ok {
+			order = append(order, storeRef)
+		}
+		groups[storeRef] = append(groups[storeRef], i)
+	}
+
+	prefetched := make(map[int]prefetchedSecret)
+	for _, storeRef := range order {
+		indices := groups[storeRef]
+		// a single entry gains nothing from a batch
+		if len(indices) < 2 {
+			continue
+		}
+		var sourceRef *esv1.StoreGeneratorSourceRef
+		if storeRef != (esv1.SecretStoreRef{}) {
+			sourceRef = &esv1.StoreGeneratorSourceRef{SecretStoreRef: &storeRef}
+		}
+		refs := make([]esv1.ExternalSecretDataRemoteRef, len(indices))
+		for j, i := range indices {
+			refs[j] = externalSecret.Spec.Data[i].RemoteRef
+		}
+		err := r.withStoreClient(ctx, externalSecret, sourceRef, cmgr, func(client esv1.SecretsClient) error {
+			batchClient, ok := client.(esv1.BatchSecretsClient)
+			if !ok {
+				return nil
+			}
+			secrets, metadata, errs, err := esv1.GetSecretsWithMetadata(ctx, batchClient, refs)
+			if err != nil {
+				return err
+			}
+			for j, i := range indices {
+				if errs[j] == nil || errors.Is(errs[j], esv1.NoSecretErr) {
+					prefetched[i] = prefetchedSecret{value: secrets[j], metadata: metadata[j], err: errs[j]}
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			r.Log.V(1).Info("could not fetch secrets in a batch", "error", err.Error())
+		}
+	}
+	return prefetched
+}
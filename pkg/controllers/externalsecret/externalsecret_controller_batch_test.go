@@ -0,0 +1,124 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalsecret
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+)
+
+// batchClient fetches secrets in batches, the value of a secret is its key.
+type batchClient struct {
+	esv1.SecretsClient
+	batches  [][]string
+	getCalls []string
+}
+
+func (c *batchClient) GetSecrets(_ context.Context, refs []esv1.ExternalSecretDataRemoteRef) ([][]byte, []error, error) {
+	var keys []string
+	secrets := make([][]byte, len(refs))
+	errs := make([]error, len(refs))
+	for i, ref := range refs {
+		keys = append(keys, ref.Key)
+		switch ref.Key {
+		case "missing":
+			errs[i] = esv1.NoSecretErr
+		case "denied":
+			errs[i] = errors.New("access denied")
+		default:
+			secrets[i] = []byte(ref.Key)
+		}
+	}
+	c.batches = append(c.batches, keys)
+	return secrets, errs, nil
+}
+
+func (c *batchClient) GetSecret(_ context.Context, ref esv1.ExternalSecretDataRemoteRef) ([]byte, error) {
+	c.getCalls = append(c.getCalls, ref.Key)
+	if ref.Key == "denied" {
+		return []byte("retried"), nil
+	}
+	return []byte(ref.Key), nil
+}
+
+func TestGetProviderSecretDataBatch(t *testing.T) {
+	r := newManifestTestReconciler(t)
+	r.recorder = record.NewFakeRecorder(10)
+	newStore := func(name string) *esv1.SecretStore {
+		return &esv1.SecretStore{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec: esv1.SecretStoreSpec{
+				Provider: &esv1.SecretStoreProvider{
+					AWS: &esv1.AWSProvider{Service: esv1.AWSServiceSecretsManager},
+				},
+			},
+		}
+	}
+	r.Client = fake.NewClientBuilder().WithScheme(r.Scheme).WithObjects(newStore("primary"), newStore("other")).Build()
+
+	defer fakeProvider.Reset()
+	clients := map[string]*batchClient{}
+	fakeProvider.WithNew(func(_ context.Context, store esv1.GenericStore, _ client.Client, _ string) (esv1.SecretsClient, error) {
+		clients[store.GetName()] = &batchClient{SecretsClient: fakeProvider}
+		return clients[store.GetName()], nil
+	})
+
+	es := newManifestTestExternalSecret(nil)
+	es.Spec.SecretStoreRef = esv1.SecretStoreRef{Name: "primary"}
+	es.Spec.Data = []esv1.ExternalSecretData{
+		{SecretKey: "a", RemoteRef: esv1.ExternalSecretDataRemoteRef{Key: "a"}},
+		{SecretKey: "b", RemoteRef: esv1.ExternalSecretDataRemoteRef{Key: "Yg==", DecodingStrategy: esv1.ExternalSecretDecodeBase64}},
+		{SecretKey: "missing", RemoteRef: esv1.ExternalSecretDataRemoteRef{Key: "missing"}, Optional: true},
+		{SecretKey: "denied", RemoteRef: esv1.ExternalSecretDataRemoteRef{Key: "denied"}},
+		{SecretKey: "c", RemoteRef: esv1.ExternalSecretDataRemoteRef{Key: "c"}, SourceRef: &esv1.StoreSourceRef{SecretStoreRef: esv1.SecretStoreRef{Name: "other"}}},
+	}
+
+	data, err := r.GetProviderSecretData(context.Background(), es)
+	if err != nil {
+		t.Fatalf("GetProviderSecretData() returned an unexpected error: %v", err)
+	}
+	want := map[string][]byte{
+		"a":      []byte("a"),
+		"b":      []byte("b"),
+		"denied": []byte("retried"),
+		"c":      []byte("c"),
+	}
+	if diff := cmp.Diff(want, data); diff != "" {
+		t.Errorf("unexpected data (-want, +got)\n%s", diff)
+	}
+	// the entries of the default store are fetched in a batch, failed entries are fetched again one by one,
+	// and a store with a single entry is not batched
+	if diff := cmp.Diff([][]string{{"a", "Yg==", "missing", "denied"}}, clients["primary"].batches); diff != "" {
+		t.Errorf("unexpected batches (-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"denied"}, clients["primary"].getCalls); diff != "" {
+		t.Errorf("unexpected GetSecret calls (-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"c"}, clients["other"].getCalls); diff != "" {
+		t.Errorf("unexpected GetSecret calls (-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff([]esv1.ExternalSecretMissingKey{{Path: "spec.data[2]", RemoteKey: "missing"}}, es.Status.MissingKeys); diff != "" {
+		t.Errorf("unexpected missing keys (-want, +got)\n%s", diff)
+	}
+}
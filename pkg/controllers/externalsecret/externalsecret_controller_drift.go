@@ -0,0 +1,133 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalsecret
+
+import (
+	v1 "k8s.io/api/core/v1"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	"github.com/external-secrets/external-secrets/pkg/controllers/externalsecret/esmetrics"
+	"github.com/external-secrets/external-secrets/pkg/controllers/util"
+	"github.com/external-secrets/external-secrets/pkg/utils"
+)
+
+const (
+	// condition messages for "Drifted" condition.
+	msgDrifted       = "target secret was changed since the last sync"
+	msgDriftRepaired = "target secret matches the last sync"
+
+	// event messages.
+	eventDrifted = "target secret was changed since the last sync"
+)
+
+// isDriftDetected returns true if drift of the target Secret is detected for the ExternalSecret.
+func isDriftDetected(es *esv1.ExternalSecret) bool {
+	switch es.Spec.Target.CreationPolicy {
+	case esv1.CreatePolicyOwner, esv1.CreatePolicyOrphan, "":
+		return es.Spec.Target.Manifest == nil
+	default:
+		return false
+	}
+}
+
+// targetHash hashes the parts of the target Secret rendered by the ExternalSecret:
+// its type, its data, and the labels and annotations set by the template or the controller.
+// Labels and annotations added by others are ignored.
+func targetHash(es *esv1.ExternalSecret, secret *v1.Secret) string {
+	labelKeys := []string{esv1.LabelManaged, esv1.LabelOwner}
+	annotationKeys := []string{esv1.AnnotationDataHash}
+	if tpl := es.Spec.Target.Template; tpl != nil {
+		for key := range tpl.Metadata.Labels {
+			labelKeys = append(labelKeys, key)
+		}
+		for key := range tpl.Metadata.Annotations {
+			annotationKeys = append(annotationKeys, key)
+		}
+	}
+	// the API server defaults the type of Secrets created without one
+	secretType := secret.Type
+	if secretType == "" {
+		secretType = v1.SecretTypeOpaque
+	}
+	return utils.ObjectHash(struct {
+		Type        v1.SecretType
+		Data        map[string][]byte
+		Labels      map[string]string
+		Annotations map[string]string
+	}{
+		Type:        secretType,
+		Data:        secret.Data,
+		Labels:      pickKeys(secret.Labels, labelKeys),
+		Annotations: pickKeys(secret.Annotations, annotationKeys),
+	})
+}
+
+func pickKeys(m map[string]string, keys []string) map[string]string {
+	picked := make(map[string]string)
+	for _, key := range keys {
+		if v, ok := m[key]; ok {
+			picked[key] = v
+		}
+	}
+	return picked
+}
+
+// hasManagedMetadata returns true if the target Secret exists with the "managed" label and a "data-hash" annotation,
+// regardless of whether its data still matches the annotation.
+func hasManagedMetadata(existingSecret *v1.Secret, es *esv1.ExternalSecret) bool {
+	if es.Spec.Target.CreationPolicy == esv1.CreatePolicyOrphan {
+		return true
+	}
+	_, hasDataHash := existingSecret.Annotations[esv1.AnnotationDataHash]
+	return existingSecret.UID != "" && existingSecret.Labels[esv1.LabelManaged] == esv1.LabelManagedValue && hasDataHash
+}
+
+// detectDrift returns true if the target Secret was changed since the last sync.
+// The target is compared against the hash recorded by the last sync, it is not rendered again from the providers.
+// Changes of the ExternalSecret itself since the last sync are not drift, the target is refreshed for them anyway.
+// A newly detected drift sets the Drifted condition, emits an event and is counted.
+func (r *Reconciler) detectDrift(es *esv1.ExternalSecret, existingSecret *v1.Secret, resourceLabels map[string]string) bool {
+	if !isDriftDetected(es) || es.Status.TargetHash == "" || existingSecret.UID == "" {
+		return false
+	}
+	if es.Status.SyncedResourceVersion != util.GetResourceVersion(es.ObjectMeta) {
+		return false
+	}
+	if targetHash(es, existingSecret) == es.Status.TargetHash {
+		return false
+	}
+
+	cond := GetExternalSecretCondition(es.Status, esv1.ExternalSecretDrifted)
+	if cond == nil || cond.Status != v1.ConditionTrue {
+		r.recorder.Event(es, v1.EventTypeWarning, esv1.ReasonDrifted, eventDrifted)
+		esmetrics.GetCounterVec(esmetrics.DriftDetectedKey).With(resourceLabels).Inc()
+		SetExternalSecretCondition(es, *NewExternalSecretCondition(esv1.ExternalSecretDrifted, v1.ConditionTrue, esv1.ConditionReasonSecretDrifted, msgDrifted))
+	}
+	return true
+}
+
+// recordTarget keeps the hash of the target Secret written by a sync, so drift can be detected later.
+// A drift reported before is marked as repaired.
+func recordTarget(es *esv1.ExternalSecret, rendered *v1.Secret) {
+	if !isDriftDetected(es) || rendered == nil {
+		es.Status.TargetHash = ""
+		return
+	}
+	es.Status.TargetHash = targetHash(es, rendered)
+
+	if cond := GetExternalSecretCondition(es.Status, esv1.ExternalSecretDrifted); cond != nil && cond.Status == v1.ConditionTrue {
+		SetExternalSecretCondition(es, *NewExternalSecretCondition(esv1.ExternalSecretDrifted, v1.ConditionFalse, esv1.ConditionReasonDriftRepaired, msgDriftRepaired))
+	}
+}
@@ -0,0 +1,141 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalsecret
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	"github.com/external-secrets/external-secrets/pkg/controllers/util"
+)
+
+func TestDetectDrift(t *testing.T) {
+	r := newManifestTestReconciler(t)
+	recorder := record.NewFakeRecorder(10)
+	r.recorder = recorder
+	es := newManifestTestExternalSecret(nil)
+	es.Spec.Target.Template = &esv1.ExternalSecretTemplate{
+		Metadata: esv1.ExternalSecretTemplateMetadata{Labels: map[string]string{"team": "a"}},
+	}
+	es.Status.SyncedResourceVersion = util.GetResourceVersion(es.ObjectMeta)
+	labels := map[string]string{"name": es.Name, "namespace": es.Namespace}
+
+	rendered := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "es",
+			Namespace:   "default",
+			Labels:      map[string]string{esv1.LabelManaged: esv1.LabelManagedValue, "team": "a"},
+			Annotations: map[string]string{},
+		},
+		Data: map[string][]byte{"password": []byte("secret")},
+	}
+	recordTarget(es, rendered)
+	if es.Status.TargetHash == "" {
+		t.Fatalf("expected the hash of the target to be recorded")
+	}
+
+	// the written secret, and labels and annotations added by others are not drift
+	live := rendered.DeepCopy()
+	live.UID = "secret-uid"
+	live.Type = v1.SecretTypeOpaque
+	live.Labels["app"] = "other"
+	live.Annotations["reloader"] = "true"
+	if r.detectDrift(es, live, labels) {
+		t.Errorf("expected no drift for changes outside of the rendered secret")
+	}
+
+	// a changed label set by the template is drift, which is only reported once
+	live.Labels["team"] = "b"
+	if !r.detectDrift(es, live, labels) || !r.detectDrift(es, live, labels) {
+		t.Errorf("expected a changed label to be drift")
+	}
+	if len(recorder.Events) != 1 {
+		t.Errorf("expected a single event, got %d", len(recorder.Events))
+	}
+	cond := GetExternalSecretCondition(es.Status, esv1.ExternalSecretDrifted)
+	if cond == nil || cond.Status != v1.ConditionTrue || cond.Reason != esv1.ConditionReasonSecretDrifted {
+		t.Errorf("expected the Drifted condition to be set, got %+v", cond)
+	}
+
+	// changed data is drift, even if the data-hash annotation was updated
+	live = rendered.DeepCopy()
+	live.UID = "secret-uid"
+	live.Data["password"] = []byte("changed")
+	live.Annotations[esv1.AnnotationDataHash] = "updated"
+	if !r.detectDrift(es, live, labels) {
+		t.Errorf("expected changed data to be drift")
+	}
+
+	// a changed ExternalSecret is refreshed anyway
+	changed := es.DeepCopy()
+	changed.Generation = 2
+	if r.detectDrift(changed, live, labels) {
+		t.Errorf("expected no drift for a changed ExternalSecret")
+	}
+
+	// a sync repairs the drift
+	recordTarget(es, rendered)
+	cond = GetExternalSecretCondition(es.Status, esv1.ExternalSecretDrifted)
+	if cond == nil || cond.Status != v1.ConditionFalse || cond.Reason != esv1.ConditionReasonDriftRepaired {
+		t.Errorf("expected the drift to be repaired, got %+v", cond)
+	}
+
+	// targets merged into secrets of others are not checked
+	es.Spec.Target.CreationPolicy = esv1.CreatePolicyMerge
+	recordTarget(es, rendered)
+	if es.Status.TargetHash != "" || r.detectDrift(es, live, labels) {
+		t.Errorf("expected no drift detection for CreationPolicy=Merge")
+	}
+}
+
+func TestHasManagedMetadata(t *testing.T) {
+	managed := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			UID:         "secret-uid",
+			Labels:      map[string]string{esv1.LabelManaged: esv1.LabelManagedValue},
+			Annotations: map[string]string{esv1.AnnotationDataHash: "stale"},
+		},
+	}
+	withoutLabel := managed.DeepCopy()
+	delete(withoutLabel.Labels, esv1.LabelManaged)
+	withoutAnnotation := managed.DeepCopy()
+	delete(withoutAnnotation.Annotations, esv1.AnnotationDataHash)
+
+	tests := []struct {
+		name   string
+		secret *v1.Secret
+		policy esv1.ExternalSecretCreationPolicy
+		want   bool
+	}{
+		{name: "changed data", secret: managed, want: true},
+		{name: "missing managed label", secret: withoutLabel, want: false},
+		{name: "missing data-hash annotation", secret: withoutAnnotation, want: false},
+		{name: "missing secret", secret: &v1.Secret{}, want: false},
+		{name: "orphan", secret: withoutLabel, policy: esv1.CreatePolicyOrphan, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := newManifestTestExternalSecret(nil)
+			es.Spec.Target.CreationPolicy = tt.policy
+			if got := hasManagedMetadata(tt.secret, es); got != tt.want {
+				t.Errorf("hasManagedMetadata() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,309 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalsecret
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/json"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	"github.com/external-secrets/external-secrets/pkg/utils"
+)
+
+const (
+	// condition messages for "DryRun" reason.
+	msgDryRun = "dry-run completed, no changes were applied to the target"
+
+	// condition messages for "SecretSyncedError" reason.
+	msgErrorDryRun = "could not compute the changes of the dry-run"
+
+	// event messages.
+	eventDryRun = "dry-run: %s %s (added=%d, removed=%d, changed=%d)"
+)
+
+// dryRunTarget is a kind-agnostic view of a target, used to compute the changes of a dry-run.
+type dryRunTarget struct {
+	exists      bool
+	data        map[string][]byte
+	labels      map[string]string
+	annotations map[string]string
+}
+
+// reconcileDryRun runs the full fetch and template pipeline, but never writes the target.
+// Instead, the changes that would be applied are written to status.dryRun and emitted as an event.
+func (r *Reconciler) reconcileDryRun(ctx context.Context, log logr.Logger, externalSecret *esv1.ExternalSecret, targetName string, start time.Time, syncCallsError prometheus.Counter) (result ctrl.Result, err error) {
+	kind := "Secret"
+	if isGenericTarget(externalSecret) {
+		kind = targetGVK(externalSecret).Kind
+	}
+	log = log.WithValues("dryRun", true)
+
+	// update status of the ExternalSecret when this function returns, if needed.
+	currentStatus := *externalSecret.Status.DeepCopy()
+	defer func() {
+		result, err = r.updateStatus(ctx, log, externalSecret, currentStatus, result, err)
+	}()
+
+	if !shouldRefresh(externalSecret) {
+		log.V(1).Info("skipping refresh")
+		return r.getRequeueResult(externalSecret), nil
+	}
+
+	// reading arbitrary resources requires the same opt-in as writing them
+	// NOTE: this error cant be fixed by retrying so we don't return an error (which would requeue immediately)
+	if isGenericTarget(externalSecret) && !r.AllowGenericTargets {
+		r.markAsFailed(msgErrorGenericTargetsDisabled, fmt.Errorf(errGenericTargetsDisabled, kind), externalSecret, syncCallsError)
+		return ctrl.Result{}, nil
+	}
+
+	dataMap, err := r.GetProviderSecretData(ctx, externalSecret)
+	if err != nil {
+		r.markAsFailed(msgErrorGetSecretData, err, externalSecret, syncCallsError)
+		return ctrl.Result{}, err
+	}
+
+	var current, desired *dryRunTarget
+	if isGenericTarget(externalSecret) {
+		current, desired, err = r.planGenericTarget(ctx, externalSecret, targetName, dataMap)
+	} else {
+		current, desired, err = r.planSecret(ctx, externalSecret, targetName, dataMap)
+	}
+	if err != nil {
+		r.markAsFailed(msgErrorDryRun, err, externalSecret, syncCallsError)
+		return ctrl.Result{}, err
+	}
+
+	plan := newDryRunStatus(current, desired, func(value []byte) string {
+		return utils.KeyedHash(r.HashKey, string(externalSecret.UID), value)
+	})
+	externalSecret.Status.DryRun = plan
+	added, removed, changed := countDryRunKeys(plan.Keys)
+	r.recorder.Eventf(externalSecret, v1.EventTypeNormal, esv1.ReasonDryRun, eventDryRun, plan.Action, kind, added, removed, changed)
+	r.markAsDone(externalSecret, start, log, esv1.ConditionReasonDryRun, msgDryRun)
+	return r.getRequeueResult(externalSecret), nil
+}
+
+// planSecret returns the current and the desired state of a Secret target.
+// The desired state is nil if the target would be deleted, and equal to the current state if it would not be touched.
+func (r *Reconciler) planSecret(ctx context.Context, externalSecret *esv1.ExternalSecret, targetName string, dataMap map[string][]byte) (*dryRunTarget, *dryRunTarget, error) {
+	// NOTE: we can't use the managed secret client here, as we must not label unmanaged secrets in dry-run mode.
+	existing := &v1.Secret{}
+	err := r.Get(ctx, client.ObjectKey{Name: targetName, Namespace: externalSecret.Namespace}, existing)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, nil, err
+	}
+	current := &dryRunTarget{
+		exists:      existing.UID != "",
+		data:        existing.Data,
+		labels:      existing.Labels,
+		annotations: existing.Annotations,
+	}
+
+	mutate, remove := dryRunPolicy(externalSecret, current.exists, dataMap)
+	if remove {
+		return current, nil, nil
+	}
+	if !mutate {
+		return current, current, nil
+	}
+
+	secret := existing.DeepCopy()
+	secret.Name = targetName
+	secret.Namespace = externalSecret.Namespace
+	if err := r.mutateSecret(ctx, externalSecret, secret, dataMap); err != nil {
+		return nil, nil, err
+	}
+	return current, &dryRunTarget{
+		exists:      true,
+		data:        secret.Data,
+		labels:      secret.Labels,
+		annotations: secret.Annotations,
+	}, nil
+}
+
+// planGenericTarget returns the current and the desired state of a ConfigMap or custom resource target.
+func (r *Reconciler) planGenericTarget(ctx context.Context, externalSecret *esv1.ExternalSecret, targetName string, dataMap map[string][]byte) (*dryRunTarget, *dryRunTarget, error) {
+	gvk := targetGVK(externalSecret)
+	existing := &unstructured.Unstructured{}
+	existing.SetGroupVersionKind(gvk)
+	err := r.Get(ctx, client.ObjectKey{Name: targetName, Namespace: externalSecret.Namespace}, existing)
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return nil, nil, err
+		}
+		existing = &unstructured.Unstructured{}
+		existing.SetGroupVersionKind(gvk)
+	}
+	currentData, err := genericTargetData(externalSecret, existing)
+	if err != nil {
+		return nil, nil, err
+	}
+	current := &dryRunTarget{
+		exists:      existing.GetUID() != "",
+		data:        currentData,
+		labels:      existing.GetLabels(),
+		annotations: existing.GetAnnotations(),
+	}
+
+	mutate, remove := dryRunPolicy(externalSecret, current.exists, dataMap)
+	if remove {
+		return current, nil, nil
+	}
+	if !mutate {
+		return current, current, nil
+	}
+
+	obj := existing.DeepCopy()
+	obj.SetName(targetName)
+	obj.SetNamespace(externalSecret.Namespace)
+	if err := r.mutateGenericTarget(ctx, externalSecret, obj, dataMap); err != nil {
+		return nil, nil, err
+	}
+	desiredData, err := genericTargetData(externalSecret, obj)
+	if err != nil {
+		return nil, nil, err
+	}
+	return current, &dryRunTarget{
+		exists:      true,
+		data:        desiredData,
+		labels:      obj.GetLabels(),
+		annotations: obj.GetAnnotations(),
+	}, nil
+}
+
+// genericTargetData returns the data of a ConfigMap,
+// or the JSON encoded top-level fields of a custom resource, keyed by field name.
+func genericTargetData(es *esv1.ExternalSecret, obj *unstructured.Unstructured) (map[string][]byte, error) {
+	if isConfigMapTarget(es) {
+		return configMapData(obj)
+	}
+	out := make(map[string][]byte)
+	for k, v := range obj.Object {
+		if isReservedManifestField(k) {
+			continue
+		}
+		raw, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		out[k] = raw
+	}
+	return out, nil
+}
+
+// dryRunPolicy mirrors how Reconcile applies the creation and deletion policies.
+// It returns whether the target would be mutated, or whether it would be deleted.
+func dryRunPolicy(es *esv1.ExternalSecret, exists bool, dataMap map[string][]byte) (mutate, remove bool) {
+	if len(dataMap) == 0 {
+		switch es.Spec.Target.DeletionPolicy {
+		case esv1.DeletionPolicyDelete:
+			return false, exists && es.Spec.Target.CreationPolicy == esv1.CreatePolicyOwner
+		case esv1.DeletionPolicyRetain:
+			return false, false
+		case esv1.DeletionPolicyMerge:
+		}
+	}
+	switch es.Spec.Target.CreationPolicy {
+	case esv1.CreatePolicyNone:
+		return false, false
+	case esv1.CreatePolicyMerge:
+		return exists, false
+	case esv1.CreatePolicyOrphan, esv1.CreatePolicyOwner:
+	}
+	return true, false
+}
+
+// newDryRunStatus compares the current and desired state of the target.
+// Changed values are reported with the given hash.
+func newDryRunStatus(current, desired *dryRunTarget, hash func([]byte) string) *esv1.ExternalSecretDryRunStatus {
+	status := &esv1.ExternalSecretDryRunStatus{
+		PlanTime: metav1.Now(),
+		Action:   esv1.ExternalSecretDryRunActionNone,
+	}
+	switch {
+	case desired == nil:
+		status.Action = esv1.ExternalSecretDryRunActionDelete
+		status.Keys = diffDryRunKeys(current.data, nil, hash)
+	case desired == current:
+		status.Labels = current.labels
+		status.Annotations = current.annotations
+	case !current.exists:
+		status.Action = esv1.ExternalSecretDryRunActionCreate
+		status.Keys = diffDryRunKeys(nil, desired.data, hash)
+		status.Labels = desired.labels
+		status.Annotations = desired.annotations
+	default:
+		status.Keys = diffDryRunKeys(current.data, desired.data, hash)
+		status.Labels = desired.labels
+		status.Annotations = desired.annotations
+		if len(status.Keys) > 0 || !maps.Equal(current.labels, desired.labels) || !maps.Equal(current.annotations, desired.annotations) {
+			status.Action = esv1.ExternalSecretDryRunActionUpdate
+		}
+	}
+	return status
+}
+
+// diffDryRunKeys returns the keys that were added, removed or changed, sorted by key.
+// Values are never returned, only their keyed hashes.
+func diffDryRunKeys(current, desired map[string][]byte, hash func([]byte) string) []esv1.ExternalSecretDryRunKey {
+	var keys []esv1.ExternalSecretDryRunKey
+	for k, v := range desired {
+		old, ok := current[k]
+		switch {
+		case !ok:
+			keys = append(keys, esv1.ExternalSecretDryRunKey{Key: k, Change: esv1.ExternalSecretDryRunKeyAdded, Hash: hash(v)})
+		case !bytes.Equal(old, v):
+			keys = append(keys, esv1.ExternalSecretDryRunKey{Key: k, Change: esv1.ExternalSecretDryRunKeyChanged, Hash: hash(v)})
+		}
+	}
+	for k := range current {
+		if _, ok := desired[k]; !ok {
+			keys = append(keys, esv1.ExternalSecretDryRunKey{Key: k, Change: esv1.ExternalSecretDryRunKeyRemoved})
+		}
+	}
+	slices.SortFunc(keys, func(a, b esv1.ExternalSecretDryRunKey) int {
+		return cmp.Compare(a.Key, b.Key)
+	})
+	return keys
+}
+
+func countDryRunKeys(keys []esv1.ExternalSecretDryRunKey) (added, removed, changed int) {
+	for _, k := range keys {
+		switch k.Change {
+		case esv1.ExternalSecretDryRunKeyAdded:
+			added++
+		case esv1.ExternalSecretDryRunKeyRemoved:
+			removed++
+		case esv1.ExternalSecretDryRunKeyChanged:
+			changed++
+		}
+	}
+	return added, removed, changed
+}
@@ -0,0 +1,142 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalsecret
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	"github.com/external-secrets/external-secrets/pkg/utils"
+)
+
+func dryRunTestHash(value []byte) string {
+	return utils.KeyedHash([]byte("key"), "es-uid", value)
+}
+
+func TestDiffDryRunKeys(t *testing.T) {
+	current := map[string][]byte{
+		"unchanged": []byte("a"),
+		"changed":   []byte("b"),
+		"removed":   []byte("c"),
+	}
+	desired := map[string][]byte{
+		"unchanged": []byte("a"),
+		"changed":   []byte("B"),
+		"added":     []byte("d"),
+	}
+	expected := []esv1.ExternalSecretDryRunKey{
+		{Key: "added", Change: esv1.ExternalSecretDryRunKeyAdded, Hash: dryRunTestHash([]byte("d"))},
+		{Key: "changed", Change: esv1.ExternalSecretDryRunKeyChanged, Hash: dryRunTestHash([]byte("B"))},
+		{Key: "removed", Change: esv1.ExternalSecretDryRunKeyRemoved},
+	}
+	if diff := cmp.Diff(expected, diffDryRunKeys(current, desired, dryRunTestHash)); diff != "" {
+		t.Errorf("unexpected keys (-want, +got)\n%s", diff)
+	}
+}
+
+func TestPlanSecret(t *testing.T) {
+	r := newManifestTestReconciler(t)
+	if err := v1.AddToScheme(r.Scheme); err != nil {
+		t.Fatalf("could not build scheme: %v", err)
+	}
+	existing := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "target",
+			Namespace: "default",
+			UID:       types.UID("target-uid"),
+		},
+		Data: map[string][]byte{
+			"foo": []byte("old"),
+		},
+	}
+
+	tests := []struct {
+		name           string
+		objects        bool
+		creationPolicy esv1.ExternalSecretCreationPolicy
+		deletionPolicy esv1.ExternalSecretDeletionPolicy
+		dataMap        map[string][]byte
+		expectedAction esv1.ExternalSecretDryRunAction
+		expectedKeys   []esv1.ExternalSecretDryRunKey
+	}{
+		{
+			name:           "create",
+			creationPolicy: esv1.CreatePolicyOwner,
+			dataMap:        map[string][]byte{"foo": []byte("new")},
+			expectedAction: esv1.ExternalSecretDryRunActionCreate,
+			expectedKeys: []esv1.ExternalSecretDryRunKey{
+				{Key: "foo", Change: esv1.ExternalSecretDryRunKeyAdded, Hash: dryRunTestHash([]byte("new"))},
+			},
+		},
+		{
+			name:           "update",
+			objects:        true,
+			creationPolicy: esv1.CreatePolicyOwner,
+			dataMap:        map[string][]byte{"foo": []byte("new")},
+			expectedAction: esv1.ExternalSecretDryRunActionUpdate,
+			expectedKeys: []esv1.ExternalSecretDryRunKey{
+				{Key: "foo", Change: esv1.ExternalSecretDryRunKeyChanged, Hash: dryRunTestHash([]byte("new"))},
+			},
+		},
+		{
+			name:           "delete",
+			objects:        true,
+			creationPolicy: esv1.CreatePolicyOwner,
+			deletionPolicy: esv1.DeletionPolicyDelete,
+			dataMap:        map[string][]byte{},
+			expectedAction: esv1.ExternalSecretDryRunActionDelete,
+			expectedKeys: []esv1.ExternalSecretDryRunKey{
+				{Key: "foo", Change: esv1.ExternalSecretDryRunKeyRemoved},
+			},
+		},
+		{
+			name:           "merge without existing secret",
+			creationPolicy: esv1.CreatePolicyMerge,
+			dataMap:        map[string][]byte{"foo": []byte("new")},
+			expectedAction: esv1.ExternalSecretDryRunActionNone,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := fake.NewClientBuilder().WithScheme(r.Scheme)
+			if tt.objects {
+				builder = builder.WithObjects(existing.DeepCopy())
+			}
+			r.Client = builder.Build()
+			es := newManifestTestExternalSecret(nil)
+			es.Spec.Target.CreationPolicy = tt.creationPolicy
+			es.Spec.Target.DeletionPolicy = tt.deletionPolicy
+
+			current, desired, err := r.planSecret(context.Background(), es, "target", tt.dataMap)
+			if err != nil {
+				t.Fatalf("planSecret() returned an unexpected error: %v", err)
+			}
+			plan := newDryRunStatus(current, desired, dryRunTestHash)
+			if plan.Action != tt.expectedAction {
+				t.Errorf("unexpected action: got %s, want %s", plan.Action, tt.expectedAction)
+			}
+			if diff := cmp.Diff(tt.expectedKeys, plan.Keys); diff != "" {
+				t.Errorf("unexpected keys (-want, +got)\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalsecret
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	v1 "k8s.io/api/core/v1"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	genv1alpha1 "github.com/external-secrets/external-secrets/apis/generators/v1alpha1"
+	"github.com/external-secrets/external-secrets/pkg/controllers/externalsecret/esmetrics"
+)
+
+const (
+	// event messages.
+	eventExpiring = "%s (key: %s) expires at %s"
+)
+
+// withValueExpiry returns the metadata of an entry with the expiry of the X.509 certificates in its values,
+// if they expire before the expiry returned by the provider.
+func withValueExpiry(metadata *esv1.SecretMetadata, values ...[]byte) *esv1.SecretMetadata {
+	var expiry time.Time
+	for _, value := range values {
+		if notAfter, err := certNotAfter(value); err == nil {
+			expiry = earliest(expiry, notAfter)
+		}
+	}
+	if expiry.IsZero() {
+		return metadata
+	}
+	withExpiry := esv1.SecretMetadata{}
+	if metadata != nil {
+		withExpiry = *metadata
+	}
+	withExpiry.Expires = earliest(withExpiry.Expires, expiry)
+	return &withExpiry
+}
+
+// generatorMetadata returns the metadata of generated values with their expiry, if the generator reports it.
+func generatorMetadata(generator genv1alpha1.Generator, data map[string][]byte) *esv1.SecretMetadata {
+	expiring, ok := generator.(genv1alpha1.ExpiringGenerator)
+	if !ok {
+		return nil
+	}
+	expires := expiring.Expiry(data)
+	if expires.IsZero() {
+		return nil
+	}
+	return &esv1.SecretMetadata{Expires: expires}
+}
+
+// earliest returns the earlier of two times, ignoring zero times.
+func earliest(a, b time.Time) time.Time {
+	if a.IsZero() || (!b.IsZero() && b.Before(a)) {
+		return b
+	}
+	return a
+}
+
+// reportExpiry exports the expiry of the entries of the ExternalSecret,
+// and warns about entries which expire within the expiry warning window.
+func (r *Reconciler) reportExpiry(es *esv1.ExternalSecret, resourceLabels prometheus.Labels) {
+	esmetrics.RemoveKeyExpiry(es.Name, es.Namespace)
+	now := time.Now()
+	for _, key := range es.Status.Keys {
+		if key.ExpiresAt == nil {
+			continue
+		}
+		esmetrics.SetKeyExpiry(resourceLabels, key.Path, key.SecretKey, key.ExpiresAt.Time)
+		if r.ExpiryWarningWindow > 0 && key.ExpiresAt.Sub(now) < r.ExpiryWarningWindow {
+			r.recorder.Eventf(es, v1.EventTypeWarning, esv1.ReasonExpiring, eventExpiring, key.Path, key.RemoteKey, key.ExpiresAt.UTC().Format(time.RFC3339))
+		}
+	}
+}
@@ -0,0 +1,115 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalsecret
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	genv1alpha1 "github.com/external-secrets/external-secrets/apis/generators/v1alpha1"
+	"github.com/external-secrets/external-secrets/pkg/controllers/externalsecret/esmetrics"
+	ctrlmetrics "github.com/external-secrets/external-secrets/pkg/controllers/metrics"
+)
+
+func TestWithValueExpiry(t *testing.T) {
+	expires := time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)
+	cert := newTestCertificate(t, expires)
+	got := withValueExpiry(&esv1.SecretMetadata{Version: "1"}, []byte("password"), cert)
+	if got.Version != "1" || !got.Expires.Equal(expires) {
+		t.Errorf("expected the expiry of the certificate, got %+v", got)
+	}
+
+	// the expiry of the provider is kept if it is earlier
+	providerExpiry := expires.Add(-time.Hour)
+	got = withValueExpiry(&esv1.SecretMetadata{Version: "1", Expires: providerExpiry}, cert)
+	if got.Version != "1" || !got.Expires.Equal(providerExpiry) {
+		t.Errorf("expected the expiry of the provider, got %+v", got)
+	}
+	if got := withValueExpiry(nil, []byte("password")); got != nil {
+		t.Errorf("expected no metadata for values without certificates, got %+v", got)
+	}
+}
+
+// expiringGenerator reports an expiry for values with a token.
+type expiringGenerator struct {
+	genv1alpha1.Generator
+	expires time.Time
+}
+
+func (g expiringGenerator) Expiry(data map[string][]byte) time.Time {
+	if _, ok := data["token"]; !ok {
+		return time.Time{}
+	}
+	return g.expires
+}
+
+func TestGeneratorMetadata(t *testing.T) {
+	expires := time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)
+	generator := expiringGenerator{expires: expires}
+	if got := generatorMetadata(generator, map[string][]byte{"token": []byte("token")}); got == nil || !got.Expires.Equal(expires) {
+		t.Errorf("expected the expiry of the generator, got %+v", got)
+	}
+	if got := generatorMetadata(generator, map[string][]byte{"password": []byte("password")}); got != nil {
+		t.Errorf("expected no metadata without a known expiry, got %+v", got)
+	}
+	if got := generatorMetadata(expiringGenerator{}.Generator, nil); got != nil {
+		t.Errorf("expected no metadata for generators without expiry, got %+v", got)
+	}
+}
+
+func TestReportExpiry(t *testing.T) {
+	r := newManifestTestReconciler(t)
+	recorder := record.NewFakeRecorder(10)
+	r.recorder = recorder
+	r.ExpiryWarningWindow = 7 * 24 * time.Hour
+	es := newManifestTestExternalSecret(nil)
+	soon := metav1.NewTime(time.Now().Add(time.Hour).Truncate(time.Second))
+	later := metav1.NewTime(time.Now().Add(30 * 24 * time.Hour).Truncate(time.Second))
+	es.Status.Keys = []esv1.ExternalSecretKeyStatus{
+		{Path: "spec.data[0]", SecretKey: "tls.crt", RemoteKey: "certificates/api", ExpiresAt: &soon},
+		{Path: "spec.data[1]", SecretKey: "ca.crt", RemoteKey: "certificates/ca", ExpiresAt: &later},
+		{Path: "spec.data[2]", SecretKey: "password", RemoteKey: "password"},
+	}
+	resourceLabels := ctrlmetrics.RefineNonConditionMetricLabels(map[string]string{"name": es.Name, "namespace": es.Namespace})
+	expiry := func(path, secretKey string) float64 {
+		labels := prometheus.Labels{"path": path, "secret_key": secretKey}
+		for k, v := range resourceLabels {
+			labels[k] = v
+		}
+		return testutil.ToFloat64(esmetrics.GetGaugeVec(esmetrics.KeyExpiryKey).With(labels))
+	}
+
+	r.reportExpiry(es, resourceLabels)
+	if got := expiry("spec.data[0]", "tls.crt"); got != float64(soon.Unix()) {
+		t.Errorf("unexpected expiry %v, want %v", got, soon.Unix())
+	}
+	if len(recorder.Events) != 1 {
+		t.Errorf("expected a single event for the entry expiring within the window, got %d", len(recorder.Events))
+	}
+
+	// removed entries are dropped from the metric
+	es.Status.Keys = es.Status.Keys[1:]
+	r.reportExpiry(es, resourceLabels)
+	if got := testutil.CollectAndCount(esmetrics.GetGaugeVec(esmetrics.KeyExpiryKey)); got != 1 {
+		t.Errorf("expected a single expiry, got %d", got)
+	}
+	esmetrics.RemoveKeyExpiry(es.Name, es.Namespace)
+}
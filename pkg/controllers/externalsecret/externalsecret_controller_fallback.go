@@ -0,0 +1,60 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalsecret
+
+import (
+	"context"
+
+	v1 "k8s.io/api/core/v1"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	"github.com/external-secrets/external-secrets/pkg/controllers/secretstore"
+)
+
+const (
+	// event messages.
+	eventStoreFallback = "%s %q failed, the data is served by %s %q"
+)
+
+// withStoreClient calls fn with the client of the store of a remote ref.
+// A store of the sourceRef takes precedence and has no fallback,
+// otherwise secretStoreRef is used and spec.fallbackStoreRefs are tried in order if it fails.
+func (r *Reconciler) withStoreClient(ctx context.Context, externalSecret *esv1.ExternalSecret, sourceRef *esv1.StoreGeneratorSourceRef, cmgr *secretstore.Manager, fn func(esv1.SecretsClient) error) error {
+	if sourceRef != nil && sourceRef.SecretStoreRef != nil {
+		_, err := cmgr.GetWithFallback(ctx, []esv1.SecretStoreRef{*sourceRef.SecretStoreRef}, externalSecret.Namespace, fn)
+		return err
+	}
+
+	primary := externalSecret.Spec.SecretStoreRef
+	storeRefs := append([]esv1.SecretStoreRef{primary}, externalSecret.Spec.FallbackStoreRefs...)
+	served, err := cmgr.GetWithFallback(ctx, storeRefs, externalSecret.Namespace, fn)
+	if err != nil || len(externalSecret.Spec.FallbackStoreRefs) == 0 {
+		return err
+	}
+
+	active := externalSecret.Status.ActiveStoreRef
+	if served != primary && (active == nil || *active != served) {
+		r.recorder.Eventf(externalSecret, v1.EventTypeWarning, esv1.ReasonStoreFallback, eventStoreFallback, storeKind(primary), primary.Name, storeKind(served), served.Name)
+	}
+	externalSecret.Status.ActiveStoreRef = &served
+	return nil
+}
+
+func storeKind(ref esv1.SecretStoreRef) string {
+	if ref.Kind == "" {
+		return esv1.SecretStoreKind
+	}
+	return ref.Kind
+}
@@ -0,0 +1,91 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalsecret
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+)
+
+func TestGetProviderSecretDataFallback(t *testing.T) {
+	r := newManifestTestReconciler(t)
+	recorder := record.NewFakeRecorder(10)
+	r.recorder = recorder
+	newStore := func(name string) *esv1.SecretStore {
+		return &esv1.SecretStore{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec: esv1.SecretStoreSpec{
+				Provider: &esv1.SecretStoreProvider{
+					AWS: &esv1.AWSProvider{Service: esv1.AWSServiceSecretsManager},
+				},
+			},
+		}
+	}
+	r.Client = fake.NewClientBuilder().WithScheme(r.Scheme).WithObjects(newStore("primary"), newStore("secondary")).Build()
+
+	defer fakeProvider.Reset()
+	fakeProvider.WithGetSecret([]byte("bar"), nil)
+	fakeProvider.WithNew(func(_ context.Context, store esv1.GenericStore, _ client.Client, _ string) (esv1.SecretsClient, error) {
+		if store.GetName() == "primary" {
+			return nil, errors.New("connection refused")
+		}
+		return fakeProvider, nil
+	})
+
+	es := newManifestTestExternalSecret(nil)
+	es.Spec.SecretStoreRef = esv1.SecretStoreRef{Name: "primary"}
+	es.Spec.FallbackStoreRefs = []esv1.SecretStoreRef{{Name: "secondary"}}
+	es.Spec.Data = []esv1.ExternalSecretData{
+		{SecretKey: "foo", RemoteRef: esv1.ExternalSecretDataRemoteRef{Key: "foo"}},
+	}
+
+	data, err := r.GetProviderSecretData(context.Background(), es)
+	if err != nil {
+		t.Fatalf("GetProviderSecretData() returned an unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(map[string][]byte{"foo": []byte("bar")}, data); diff != "" {
+		t.Errorf("unexpected data (-want, +got)\n%s", diff)
+	}
+	if diff := cmp.Diff(&esv1.SecretStoreRef{Name: "secondary"}, es.Status.ActiveStoreRef); diff != "" {
+		t.Errorf("unexpected active store (-want, +got)\n%s", diff)
+	}
+	select {
+	case event := <-recorder.Events:
+		if !strings.Contains(event, esv1.ReasonStoreFallback) {
+			t.Errorf("unexpected event: %s", event)
+		}
+	default:
+		t.Errorf("expected a fallback event")
+	}
+
+	// without a fallback, the error of the primary store is returned
+	es.Spec.FallbackStoreRefs = nil
+	if _, err := r.GetProviderSecretData(context.Background(), es); err == nil || !strings.Contains(err.Error(), "connection refused") {
+		t.Errorf("expected the error of the primary store, got: %v", err)
+	}
+	if es.Status.ActiveStoreRef != nil {
+		t.Errorf("expected no active store, got: %v", es.Status.ActiveStoreRef)
+	}
+}
@@ -0,0 +1,210 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalsecret
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"strconv"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	"github.com/external-secrets/external-secrets/pkg/utils"
+)
+
+const (
+	historySecretNameFmt = "%s-history-%d"
+
+	// condition messages for "SecretPinned" reason.
+	msgPinned = "secret pinned to version %d of its history"
+
+	// condition messages for "SecretSyncedError" reason.
+	msgErrorPinnedVersion = "could not get pinned version of secret"
+	msgErrorHistory       = "could not update history of secret"
+
+	// error formats.
+	errPinnedVersionInvalid  = "%w: %q is not a valid version"
+	errPinnedVersionNotFound = "%w: version %d is not part of the history"
+	errHistoryConflict       = "secret %s already exists and is not part of the history of this ExternalSecret"
+
+	// event messages.
+	eventPinned = "secret pinned to version %d of its history"
+)
+
+// ErrInvalidPinnedVersion is returned if the pinned version is not part of the history.
+// It can't be fixed by retrying, until the annotation is changed.
+var ErrInvalidPinnedVersion = errors.New("invalid pinned version")
+
+// getPinnedVersion returns the history Secret the target is pinned to, or nil if it is not pinned.
+func (r *Reconciler) getPinnedVersion(ctx context.Context, externalSecret *esv1.ExternalSecret) (*v1.Secret, error) {
+	value, ok := externalSecret.Annotations[esv1.AnnotationPinnedVersion]
+	if !ok {
+		externalSecret.Status.PinnedVersion = 0
+		return nil, nil
+	}
+	version, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf(errPinnedVersionInvalid, ErrInvalidPinnedVersion, value)
+	}
+	for _, v := range externalSecret.Status.History {
+		if v.Version != version {
+			continue
+		}
+		secret := &v1.Secret{}
+		err := r.Get(ctx, client.ObjectKey{Name: v.SecretName, Namespace: externalSecret.Namespace}, secret)
+		if apierrors.IsNotFound(err) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if externalSecret.Status.PinnedVersion != version {
+			r.recorder.Eventf(externalSecret, v1.EventTypeNormal, esv1.ReasonPinned, eventPinned, version)
+		}
+		externalSecret.Status.PinnedVersion = version
+		return secret, nil
+	}
+	return nil, fmt.Errorf(errPinnedVersionNotFound, ErrInvalidPinnedVersion, version)
+}
+
+// mutateSecretFromHistory applies a version of the history to the given secret.
+// The data was rendered when the version was recorded, so the template is not applied again.
+func (r *Reconciler) mutateSecretFromHistory(externalSecret *esv1.ExternalSecret, secret, version *v1.Secret) error {
+	return r.mutateSecretWith(externalSecret, secret, func() error {
+		secret.Data = maps.Clone(version.Data)
+		if t, ok := version.Annotations[esv1.AnnotationHistoryType]; ok {
+			secret.Type = v1.SecretType(t)
+		}
+		return nil
+	})
+}
+
+// reconcileHistory records the rendered target as a new version if its data changed,
+// and removes the versions which exceed the limit.
+// No new version is recorded while the target is pinned.
+func (r *Reconciler) reconcileHistory(ctx context.Context, externalSecret *esv1.ExternalSecret, secretName string, rendered *v1.Secret) error {
+	limit := 0
+	if externalSecret.Spec.Target.History != nil {
+		limit = int(externalSecret.Spec.Target.History.Limit)
+	}
+
+	history := externalSecret.Status.History
+	if limit > 0 && rendered != nil && externalSecret.Status.PinnedVersion == 0 {
+		hash := utils.ObjectHash(rendered.Data)
+		if len(history) == 0 || history[len(history)-1].DataHash != hash {
+			version := int64(1)
+			if len(history) > 0 {
+				version = history[len(history)-1].Version + 1
+			}
+			entry, err := r.createHistoryVersion(ctx, externalSecret, secretName, rendered, version)
+			if err != nil {
+				return err
+			}
+			history = append(history, *entry)
+		}
+	}
+
+	// remove the oldest versions, but never the one the target is pinned to
+	kept := make([]esv1.ExternalSecretHistoryVersion, 0, len(history))
+	for i, v := range history {
+		if len(history)-i > limit && v.Version != externalSecret.Status.PinnedVersion {
+			secret := &v1.Secret{ObjectMeta: metav1.ObjectMeta{Name: v.SecretName, Namespace: externalSecret.Namespace}}
+			if err := r.Delete(ctx, secret); err != nil && !apierrors.IsNotFound(err) {
+				return err
+			}
+			continue
+		}
+		kept = append(kept, v)
+	}
+	if len(kept) == 0 {
+		kept = nil
+	}
+	externalSecret.Status.History = kept
+	return nil
+}
+
+// createHistoryVersion stores the data of the rendered target in a new immutable Secret.
+func (r *Reconciler) createHistoryVersion(ctx context.Context, externalSecret *esv1.ExternalSecret, secretName string, rendered *v1.Secret, version int64) (*esv1.ExternalSecretHistoryVersion, error) {
+	hash := utils.ObjectHash(rendered.Data)
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf(historySecretNameFmt, secretName, version),
+			Namespace: externalSecret.Namespace,
+			Labels: map[string]string{
+				esv1.LabelHistoryOf: utils.ObjectHash(fmt.Sprintf("%v/%v", externalSecret.Namespace, externalSecret.Name)),
+			},
+			Annotations: map[string]string{
+				esv1.AnnotationHistoryVersion: strconv.FormatInt(version, 10),
+				esv1.AnnotationHistoryType:    string(rendered.Type),
+				esv1.AnnotationDataHash:       hash,
+			},
+		},
+		Immutable: ptr.To(true),
+		Type:      v1.SecretTypeOpaque,
+		Data:      maps.Clone(rendered.Data),
+	}
+	// the history is garbage collected together with the ExternalSecret
+	if err := controllerutil.SetOwnerReference(externalSecret, secret, r.Scheme); err != nil {
+		return nil, err
+	}
+
+	err := r.Create(ctx, secret, client.FieldOwner(fqdnFor(externalSecret.Name)))
+	if apierrors.IsAlreadyExists(err) {
+		// a leftover of a history which is no longer part of the status, e.g. after a restore.
+		// Secrets which are not part of the history of this ExternalSecret are never replaced.
+		existing := &v1.Secret{}
+		if err := r.Get(ctx, client.ObjectKeyFromObject(secret), existing); err != nil {
+			return nil, err
+		}
+		if !isHistoryVersionOf(existing, externalSecret) {
+			return nil, fmt.Errorf(errHistoryConflict, secret.Name)
+		}
+		if err := r.Delete(ctx, existing); err != nil && !apierrors.IsNotFound(err) {
+			return nil, err
+		}
+		err = r.Create(ctx, secret, client.FieldOwner(fqdnFor(externalSecret.Name)))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &esv1.ExternalSecretHistoryVersion{
+		Version:      version,
+		SecretName:   secret.Name,
+		DataHash:     hash,
+		CreationTime: metav1.Now(),
+	}, nil
+}
+
+// isHistoryVersionOf returns true if the secret is labeled or owned as a history version of the ExternalSecret.
+func isHistoryVersionOf(secret *v1.Secret, externalSecret *esv1.ExternalSecret) bool {
+	if secret.Labels[esv1.LabelHistoryOf] == utils.ObjectHash(fmt.Sprintf("%v/%v", externalSecret.Namespace, externalSecret.Name)) {
+		return true
+	}
+	for _, ref := range secret.OwnerReferences {
+		if ref.UID == externalSecret.UID && ref.Kind == esv1.ExtSecretKind {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,215 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalsecret
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	"github.com/external-secrets/external-secrets/pkg/utils"
+)
+
+func TestReconcileHistory(t *testing.T) {
+	r := newManifestTestReconciler(t)
+	if err := v1.AddToScheme(r.Scheme); err != nil {
+		t.Fatalf("could not build scheme: %v", err)
+	}
+	r.Client = fake.NewClientBuilder().WithScheme(r.Scheme).Build()
+	ctx := context.Background()
+
+	es := newManifestTestExternalSecret(nil)
+	es.Spec.Target.History = &esv1.ExternalSecretTargetHistory{Limit: 2}
+
+	render := func(value string) *v1.Secret {
+		return &v1.Secret{
+			Type: v1.SecretTypeTLS,
+			Data: map[string][]byte{"foo": []byte(value)},
+		}
+	}
+	versions := func() []int64 {
+		var out []int64
+		for _, v := range es.Status.History {
+			out = append(out, v.Version)
+		}
+		return out
+	}
+
+	// unchanged data must not create a new version
+	for _, value := range []string{"a", "a", "b", "c"} {
+		if err := r.reconcileHistory(ctx, es, "target", render(value)); err != nil {
+			t.Fatalf("reconcileHistory() returned an unexpected error: %v", err)
+		}
+	}
+	if diff := cmp.Diff([]int64{2, 3}, versions()); diff != "" {
+		t.Errorf("unexpected versions (-want, +got)\n%s", diff)
+	}
+
+	// pruned versions are deleted
+	err := r.Get(ctx, client.ObjectKey{Name: "target-history-1", Namespace: "default"}, &v1.Secret{})
+	if !apierrors.IsNotFound(err) {
+		t.Errorf("expected pruned version to be deleted, got: %v", err)
+	}
+	version := &v1.Secret{}
+	if err := r.Get(ctx, client.ObjectKey{Name: "target-history-3", Namespace: "default"}, version); err != nil {
+		t.Fatalf("could not get version: %v", err)
+	}
+	if version.Type != v1.SecretTypeOpaque || version.Annotations[esv1.AnnotationHistoryType] != string(v1.SecretTypeTLS) {
+		t.Errorf("unexpected type of version: %s", version.Type)
+	}
+	if string(version.Data["foo"]) != "c" {
+		t.Errorf("unexpected data of version: %v", version.Data)
+	}
+
+	// the pinned version is kept, even if it exceeds the limit
+	es.Status.PinnedVersion = 2
+	es.Spec.Target.History.Limit = 1
+	if err := r.reconcileHistory(ctx, es, "target", nil); err != nil {
+		t.Fatalf("reconcileHistory() returned an unexpected error: %v", err)
+	}
+	if diff := cmp.Diff([]int64{2, 3}, versions()); diff != "" {
+		t.Errorf("unexpected versions (-want, +got)\n%s", diff)
+	}
+
+	// removing the history deletes all versions
+	es.Status.PinnedVersion = 0
+	es.Spec.Target.History = nil
+	if err := r.reconcileHistory(ctx, es, "target", render("d")); err != nil {
+		t.Fatalf("reconcileHistory() returned an unexpected error: %v", err)
+	}
+	if es.Status.History != nil {
+		t.Errorf("expected history to be empty, got: %v", es.Status.History)
+	}
+}
+
+func TestCreateHistoryVersionExisting(t *testing.T) {
+	r := newManifestTestReconciler(t)
+	if err := v1.AddToScheme(r.Scheme); err != nil {
+		t.Fatalf("could not build scheme: %v", err)
+	}
+	ctx := context.Background()
+	es := newManifestTestExternalSecret(nil)
+	es.Spec.Target.History = &esv1.ExternalSecretTargetHistory{Limit: 2}
+	rendered := &v1.Secret{Data: map[string][]byte{"foo": []byte("new")}}
+
+	// a leftover version of this ExternalSecret is replaced
+	leftover := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "target-history-1",
+			Namespace: "default",
+			Labels: map[string]string{
+				esv1.LabelHistoryOf: utils.ObjectHash("default/es"),
+			},
+		},
+		Data: map[string][]byte{"foo": []byte("old")},
+	}
+	r.Client = fake.NewClientBuilder().WithScheme(r.Scheme).WithObjects(leftover).Build()
+	if _, err := r.createHistoryVersion(ctx, es, "target", rendered, 1); err != nil {
+		t.Fatalf("createHistoryVersion() returned an unexpected error: %v", err)
+	}
+	version := &v1.Secret{}
+	if err := r.Get(ctx, client.ObjectKey{Name: "target-history-1", Namespace: "default"}, version); err != nil {
+		t.Fatalf("could not get version: %v", err)
+	}
+	if string(version.Data["foo"]) != "new" {
+		t.Errorf("expected leftover version to be replaced, got: %v", version.Data)
+	}
+
+	// a Secret which is not part of the history is never touched
+	foreign := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "target-history-1", Namespace: "default"},
+		Data:       map[string][]byte{"foo": []byte("foreign")},
+	}
+	r.Client = fake.NewClientBuilder().WithScheme(r.Scheme).WithObjects(foreign).Build()
+	if _, err := r.createHistoryVersion(ctx, es, "target", rendered, 1); err == nil {
+		t.Fatalf("expected an error for a foreign secret")
+	}
+	if err := r.Get(ctx, client.ObjectKey{Name: "target-history-1", Namespace: "default"}, version); err != nil {
+		t.Fatalf("could not get secret: %v", err)
+	}
+	if string(version.Data["foo"]) != "foreign" {
+		t.Errorf("expected foreign secret to be kept, got: %v", version.Data)
+	}
+}
+
+func TestGetPinnedVersion(t *testing.T) {
+	r := newManifestTestReconciler(t)
+	if err := v1.AddToScheme(r.Scheme); err != nil {
+		t.Fatalf("could not build scheme: %v", err)
+	}
+	r.Client = fake.NewClientBuilder().WithScheme(r.Scheme).Build()
+	r.recorder = record.NewFakeRecorder(10)
+	ctx := context.Background()
+
+	es := newManifestTestExternalSecret(nil)
+	es.Spec.Target.History = &esv1.ExternalSecretTargetHistory{Limit: 5}
+	if err := r.reconcileHistory(ctx, es, "target", &v1.Secret{Data: map[string][]byte{"foo": []byte("a")}}); err != nil {
+		t.Fatalf("reconcileHistory() returned an unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		annotation  *string
+		expectedErr error
+		expected    int64
+	}{
+		{
+			name: "not pinned",
+		},
+		{
+			name:       "pinned",
+			annotation: ptr.To("1"),
+			expected:   1,
+		},
+		{
+			name:        "unknown version",
+			annotation:  ptr.To("2"),
+			expectedErr: ErrInvalidPinnedVersion,
+		},
+		{
+			name:        "invalid version",
+			annotation:  ptr.To("latest"),
+			expectedErr: ErrInvalidPinnedVersion,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := es.DeepCopy()
+			if tt.annotation != nil {
+				es.Annotations = map[string]string{esv1.AnnotationPinnedVersion: *tt.annotation}
+			}
+			secret, err := r.getPinnedVersion(ctx, es)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("unexpected error: got %v, want %v", err, tt.expectedErr)
+			}
+			if es.Status.PinnedVersion != tt.expected {
+				t.Errorf("unexpected pinned version: got %d, want %d", es.Status.PinnedVersion, tt.expected)
+			}
+			if tt.expected != 0 && string(secret.Data["foo"]) != "a" {
+				t.Errorf("unexpected data of pinned version: %v", secret.Data)
+			}
+		})
+	}
+}
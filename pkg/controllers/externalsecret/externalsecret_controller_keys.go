@@ -0,0 +1,235 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalsecret
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	"github.com/external-secrets/external-secrets/pkg/controllers/util"
+)
+
+const (
+	// maxKeyStatuses bounds the size of status.keys.
+	maxKeyStatuses = 100
+	// maxKeyErrorLength bounds the size of the error of an entry of status.keys.
+	maxKeyErrorLength = 256
+)
+
+// keyStatuses collects the status of the entries of spec.dataFrom and spec.data during a sync.
+// All entries are fetched even if some of them fail. Entries which were not fetched, e.g. because no client
+// could be created for their store, keep their previous status.
+type keyStatuses struct {
+	now      metav1.Time
+	previous map[string]esv1.ExternalSecretKeyStatus
+	observed map[string]esv1.ExternalSecretKeyStatus
+	// versioned holds the entries whose version was returned by the provider.
+	versioned map[string]bool
+}
+
+func newKeyStatuses(es *esv1.ExternalSecret) *keyStatuses {
+	k := &keyStatuses{
+		now:       metav1.Now(),
+		previous:  make(map[string]esv1.ExternalSecretKeyStatus),
+		observed:  make(map[string]esv1.ExternalSecretKeyStatus),
+		versioned: make(map[string]bool),
+	}
+	for _, status := range es.Status.Keys {
+		k.previous[status.Path] = status
+	}
+	return k
+}
+
+// observe records the result of fetching an entry, along with the metadata of its version if the provider returned it.
+func (k *keyStatuses) observe(status esv1.ExternalSecretKeyStatus, metadata *esv1.SecretMetadata, err error) {
+	if err == nil {
+		now := k.now
+		status.LastSyncTime = &now
+		if metadata != nil && metadata.Version != "" {
+			status.Version = metadata.Version
+			k.versioned[status.Path] = true
+		}
+		if metadata != nil && !metadata.LastModified.IsZero() {
+			lastModified := metav1.NewTime(metadata.LastModified)
+			status.LastModified = &lastModified
+		}
+		if metadata != nil && !metadata.Expires.IsZero() {
+			expires := metav1.NewTime(metadata.Expires)
+			status.ExpiresAt = &expires
+		}
+	} else {
+		// the target still holds the values of the last successful sync
+		status.LastSyncTime = k.previous[status.Path].LastSyncTime
+		status.ExpiresAt = k.previous[status.Path].ExpiresAt
+		status.Error = err.Error()
+		if len(status.Error) > maxKeyErrorLength {
+			status.Error = status.Error[:maxKeyErrorLength]
+		}
+	}
+	k.observed[status.Path] = status
+}
+
+// unchanged returns true if the provider returned the version of every entry,
+// and they are all the versions of the last successful sync.
+func (k *keyStatuses) unchanged(es *esv1.ExternalSecret) bool {
+	paths := keyPaths(es)
+	if len(paths) == 0 {
+		return false
+	}
+	for _, path := range paths {
+		previous, ok := k.previous[path]
+		if !ok || !k.versioned[path] || previous.Error != "" || previous.Version != k.observed[path].Version {
+			return false
+		}
+	}
+	return true
+}
+
+// versionsUnchanged returns true if the target of the last sync can be kept: the ExternalSecret did not change
+// since the last successful sync, the target was not changed since it was written,
+// and the provider returned the same versions for all entries.
+// Templates reading from templateFrom are always rendered, as their sources may have changed.
+func versionsUnchanged(es *esv1.ExternalSecret, previous esv1.ExternalSecretStatus, existingSecret *v1.Secret, keys *keyStatuses) bool {
+	if previous.SyncedResourceVersion != util.GetResourceVersion(es.ObjectMeta) {
+		return false
+	}
+	// the next refresh restores a drifted target, even if the drift is only reported
+	if drifted := GetExternalSecretCondition(es.Status, esv1.ExternalSecretDrifted); drifted != nil && drifted.Status == v1.ConditionTrue {
+		return false
+	}
+	if es.Status.TargetHash != "" && targetHash(es, existingSecret) != es.Status.TargetHash {
+		return false
+	}
+	ready := GetExternalSecretCondition(previous, esv1.ExternalSecretReady)
+	if ready == nil || ready.Status != v1.ConditionTrue || ready.Reason != esv1.ConditionReasonSecretSynced {
+		return false
+	}
+	if es.Spec.Target.Template != nil && len(es.Spec.Target.Template.TemplateFrom) > 0 {
+		return false
+	}
+	return keys.unchanged(es)
+}
+
+// apply sets status.keys of the ExternalSecret.
+func (k *keyStatuses) apply(es *esv1.ExternalSecret) {
+	var keys []esv1.ExternalSecretKeyStatus
+	for _, path := range keyPaths(es) {
+		if status, ok := k.observed[path]; ok {
+			keys = append(keys, status)
+		} else if status, ok := k.previous[path]; ok {
+			keys = append(keys, status)
+		}
+	}
+	if len(keys) > maxKeyStatuses {
+		slices.SortStableFunc(keys, func(a, b esv1.ExternalSecretKeyStatus) int {
+			switch {
+			case a.Error != "" && b.Error == "":
+				return -1
+			case a.Error == "" && b.Error != "":
+				return 1
+			default:
+				return 0
+			}
+		})
+		keys = keys[:maxKeyStatuses]
+	}
+	es.Status.Keys = keys
+}
+
+// keyPaths returns the paths of the entries of spec.dataFrom and spec.data.
+func keyPaths(es *esv1.ExternalSecret) []string {
+	var paths []string
+	for i := range es.Spec.DataFrom {
+		paths = append(paths, fmt.Sprintf("spec.dataFrom[%d]", i))
+	}
+	for i := range es.Spec.Data {
+		paths = append(paths, fmt.Sprintf("spec.data[%d]", i))
+	}
+	return paths
+}
+
+// templateMetadata returns the metadata of the entries of spec.data synced successfully,
+// which templates read as .metadata.<secretKey>.version and .metadata.<secretKey>.lastModified.
+func templateMetadata(es *esv1.ExternalSecret) map[string]map[string]string {
+	metadata := make(map[string]map[string]string)
+	for _, status := range es.Status.Keys {
+		if status.SecretKey == "" || status.Error != "" {
+			continue
+		}
+		m := map[string]string{"version": status.Version, "lastModified": ""}
+		if status.LastModified != nil {
+			m["lastModified"] = status.LastModified.UTC().Format(time.RFC3339)
+		}
+		metadata[status.SecretKey] = m
+	}
+	return metadata
+}
+
+// dataKeyStatus describes an entry of spec.data.
+func dataKeyStatus(es *esv1.ExternalSecret, secretRef esv1.ExternalSecretData, i int) esv1.ExternalSecretKeyStatus {
+	status := esv1.ExternalSecretKeyStatus{
+		Path:      fmt.Sprintf("spec.data[%d]", i),
+		SecretKey: secretRef.SecretKey,
+		RemoteKey: secretRef.RemoteRef.Key,
+		Version:   secretRef.RemoteRef.Version,
+	}
+	var storeRef *esv1.SecretStoreRef
+	if secretRef.SourceRef != nil {
+		storeRef = &secretRef.SourceRef.SecretStoreRef
+	}
+	status.StoreRef = servingStoreRef(es, storeRef)
+	return status
+}
+
+// dataFromKeyStatus describes an entry of spec.dataFrom.
+func dataFromKeyStatus(es *esv1.ExternalSecret, remoteRef esv1.ExternalSecretDataFromRemoteRef, i int) esv1.ExternalSecretKeyStatus {
+	status := esv1.ExternalSecretKeyStatus{
+		Path:      fmt.Sprintf("spec.dataFrom[%d]", i),
+		RemoteKey: dataFromRemoteKey(remoteRef),
+	}
+	if remoteRef.Extract != nil {
+		status.Version = remoteRef.Extract.Version
+	}
+	if remoteRef.SourceRef != nil && remoteRef.SourceRef.GeneratorRef != nil {
+		status.GeneratorRef = remoteRef.SourceRef.GeneratorRef.DeepCopy()
+		return status
+	}
+	var storeRef *esv1.SecretStoreRef
+	if remoteRef.SourceRef != nil {
+		storeRef = remoteRef.SourceRef.SecretStoreRef
+	}
+	status.StoreRef = servingStoreRef(es, storeRef)
+	return status
+}
+
+// servingStoreRef returns the store an entry is fetched from: the store of its sourceRef,
+// or the store which served secretStoreRef, which differs from it if a fallback store was used.
+func servingStoreRef(es *esv1.ExternalSecret, sourceRef *esv1.SecretStoreRef) *esv1.SecretStoreRef {
+	switch {
+	case sourceRef != nil:
+		return sourceRef.DeepCopy()
+	case es.Status.ActiveStoreRef != nil:
+		return es.Status.ActiveStoreRef.DeepCopy()
+	case es.Spec.SecretStoreRef.Name != "":
+		return es.Spec.SecretStoreRef.DeepCopy()
+	default:
+		return nil
+	}
+}
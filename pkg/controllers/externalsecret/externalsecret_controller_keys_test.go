@@ -0,0 +1,279 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalsecret
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+)
+
+// failingClient fails to get the keys in failing, the value of other secrets is their key.
+type failingClient struct {
+	esv1.SecretsClient
+	failing map[string]bool
+}
+
+func (c *failingClient) GetSecret(_ context.Context, ref esv1.ExternalSecretDataRemoteRef) ([]byte, error) {
+	if c.failing[ref.Key] {
+		return nil, errors.New("access denied")
+	}
+	if ref.Key == "missing" {
+		return nil, esv1.NoSecretErr
+	}
+	return []byte(ref.Key), nil
+}
+
+// versionedClient returns the secrets with the version in versions.
+type versionedClient struct {
+	esv1.SecretsClient
+	versions map[string]string
+}
+
+func (c *versionedClient) GetSecret(ctx context.Context, ref esv1.ExternalSecretDataRemoteRef) ([]byte, error) {
+	secret, _, err := c.GetSecretWithMetadata(ctx, ref)
+	return secret, err
+}
+
+func (c *versionedClient) GetSecretWithMetadata(_ context.Context, ref esv1.ExternalSecretDataRemoteRef) ([]byte, *esv1.SecretMetadata, error) {
+	metadata := &esv1.SecretMetadata{
+		Version:      c.versions[ref.Key],
+		LastModified: time.Date(2026, 10, 17, 8, 0, 0, 0, time.UTC),
+	}
+	return []byte(ref.Key), metadata, nil
+}
+
+func TestGetProviderSecretDataKeys(t *testing.T) {
+	r := newManifestTestReconciler(t)
+	r.recorder = record.NewFakeRecorder(10)
+	store := &esv1.SecretStore{
+		ObjectMeta: metav1.ObjectMeta{Name: "primary", Namespace: "default"},
+		Spec: esv1.SecretStoreSpec{
+			Provider: &esv1.SecretStoreProvider{
+				AWS: &esv1.AWSProvider{Service: esv1.AWSServiceSecretsManager},
+			},
+		},
+	}
+	r.Client = fake.NewClientBuilder().WithScheme(r.Scheme).WithObjects(store).Build()
+
+	defer fakeProvider.Reset()
+	failing := map[string]bool{}
+	fakeProvider.WithNew(func(_ context.Context, _ esv1.GenericStore, _ client.Client, _ string) (esv1.SecretsClient, error) {
+		return &failingClient{SecretsClient: fakeProvider, failing: failing}, nil
+	})
+
+	es := newManifestTestExternalSecret(nil)
+	es.Spec.SecretStoreRef = esv1.SecretStoreRef{Name: "primary"}
+	es.Spec.Data = []esv1.ExternalSecretData{
+		{SecretKey: "a", RemoteRef: esv1.ExternalSecretDataRemoteRef{Key: "a", Version: "3"}},
+		{SecretKey: "b", RemoteRef: esv1.ExternalSecretDataRemoteRef{Key: "b"}},
+		{SecretKey: "c", RemoteRef: esv1.ExternalSecretDataRemoteRef{Key: "c"}},
+	}
+	ignoreTime := cmpopts.IgnoreFields(esv1.ExternalSecretKeyStatus{}, "LastSyncTime")
+	storeRef := &esv1.SecretStoreRef{Name: "primary"}
+
+	// every entry is recorded after a successful sync
+	if _, err := r.GetProviderSecretData(context.Background(), es); err != nil {
+		t.Fatalf("GetProviderSecretData() returned an unexpected error: %v", err)
+	}
+	want := []esv1.ExternalSecretKeyStatus{
+		{Path: "spec.data[0]", SecretKey: "a", StoreRef: storeRef, RemoteKey: "a", Version: "3"},
+		{Path: "spec.data[1]", SecretKey: "b", StoreRef: storeRef, RemoteKey: "b"},
+		{Path: "spec.data[2]", SecretKey: "c", StoreRef: storeRef, RemoteKey: "c"},
+	}
+	if diff := cmp.Diff(want, es.Status.Keys, ignoreTime); diff != "" {
+		t.Errorf("unexpected keys (-want, +got)\n%s", diff)
+	}
+	synced := es.Status.Keys[1].LastSyncTime
+	if synced == nil {
+		t.Fatalf("expected the last sync time to be set")
+	}
+
+	// a failed entry keeps its last sync time, the entries after it are still fetched
+	// and the errors of all entries are returned
+	failing["b"] = true
+	failing["c"] = true
+	_, err := r.GetProviderSecretData(context.Background(), es)
+	if err == nil || !strings.Contains(err.Error(), "spec.data[1]") || !strings.Contains(err.Error(), "spec.data[2]") {
+		t.Fatalf("expected GetProviderSecretData() to fail for spec.data[1] and spec.data[2], got: %v", err)
+	}
+	want[1].Error = "access denied"
+	want[2].Error = "access denied"
+	if diff := cmp.Diff(want, es.Status.Keys, ignoreTime); diff != "" {
+		t.Errorf("unexpected keys (-want, +got)\n%s", diff)
+	}
+	if !es.Status.Keys[1].LastSyncTime.Equal(synced) {
+		t.Errorf("expected the failed entry to keep its last sync time")
+	}
+
+	// a missing secret of an optional entry is not an error of the entry,
+	// and a missing secret does not hide the errors of other entries
+	es.Spec.Data = append(es.Spec.Data,
+		esv1.ExternalSecretData{SecretKey: "d", RemoteRef: esv1.ExternalSecretDataRemoteRef{Key: "missing"}, Optional: true},
+		esv1.ExternalSecretData{SecretKey: "e", RemoteRef: esv1.ExternalSecretDataRemoteRef{Key: "missing"}},
+	)
+	es.Spec.Target.DeletionPolicy = esv1.DeletionPolicyRetain
+	_, err = r.GetProviderSecretData(context.Background(), es)
+	if err == nil || errors.Is(err, esv1.NoSecretErr) {
+		t.Fatalf("expected GetProviderSecretData() to fail with an error other than a missing secret, got: %v", err)
+	}
+	if got := es.Status.Keys[3]; got.Path != "spec.data[3]" || got.Error != "" {
+		t.Errorf("expected no error for the missing optional entry, got %+v", got)
+	}
+	if got := es.Status.Keys[4]; got.Path != "spec.data[4]" || got.Error == "" {
+		t.Errorf("expected an error for the missing entry, got %+v", got)
+	}
+	failing["b"] = false
+	failing["c"] = false
+	if _, err = r.GetProviderSecretData(context.Background(), es); !errors.Is(err, esv1.NoSecretErr) {
+		t.Errorf("expected a missing secret error if only secrets are missing, got: %v", err)
+	}
+	es.Spec.Target.DeletionPolicy = ""
+	want[1].Error = ""
+	want[2].Error = ""
+
+	// removed entries are dropped
+	es.Spec.Data = es.Spec.Data[:1]
+	if _, err := r.GetProviderSecretData(context.Background(), es); err != nil {
+		t.Fatalf("GetProviderSecretData() returned an unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(want[:1], es.Status.Keys, ignoreTime); diff != "" {
+		t.Errorf("unexpected keys (-want, +got)\n%s", diff)
+	}
+}
+
+func TestKeyStatusesBounded(t *testing.T) {
+	es := newManifestTestExternalSecret(nil)
+	k := newKeyStatuses(es)
+	for i := range maxKeyStatuses + 10 {
+		es.Spec.Data = append(es.Spec.Data, esv1.ExternalSecretData{SecretKey: fmt.Sprint(i)})
+		var err error
+		if i == maxKeyStatuses+5 {
+			err = errors.New("access denied")
+		}
+		k.observe(dataKeyStatus(es, es.Spec.Data[i], i), nil, err)
+	}
+	k.apply(es)
+	if len(es.Status.Keys) != maxKeyStatuses {
+		t.Fatalf("expected %d keys, got %d", maxKeyStatuses, len(es.Status.Keys))
+	}
+	if es.Status.Keys[0].Error == "" || es.Status.Keys[1].Path != "spec.data[0]" {
+		t.Errorf("expected failed entries to be kept first, got %s and %s", es.Status.Keys[0].Path, es.Status.Keys[1].Path)
+	}
+}
+
+func TestVersionsUnchanged(t *testing.T) {
+	r := newManifestTestReconciler(t)
+	r.recorder = record.NewFakeRecorder(10)
+	store := &esv1.SecretStore{
+		ObjectMeta: metav1.ObjectMeta{Name: "primary", Namespace: "default"},
+		Spec: esv1.SecretStoreSpec{
+			Provider: &esv1.SecretStoreProvider{
+				AWS: &esv1.AWSProvider{Service: esv1.AWSServiceSecretsManager},
+			},
+		},
+	}
+	r.Client = fake.NewClientBuilder().WithScheme(r.Scheme).WithObjects(store).Build()
+
+	defer fakeProvider.Reset()
+	versions := map[string]string{"a": "v1", "b": "v1"}
+	fakeProvider.WithNew(func(_ context.Context, _ esv1.GenericStore, _ client.Client, _ string) (esv1.SecretsClient, error) {
+		return &versionedClient{SecretsClient: fakeProvider, versions: versions}, nil
+	})
+
+	es := newManifestTestExternalSecret(nil)
+	es.Spec.SecretStoreRef = esv1.SecretStoreRef{Name: "primary"}
+	es.Spec.Data = []esv1.ExternalSecretData{
+		{SecretKey: "a", RemoteRef: esv1.ExternalSecretDataRemoteRef{Key: "a"}},
+		{SecretKey: "b", RemoteRef: esv1.ExternalSecretDataRemoteRef{Key: "b"}},
+	}
+	target := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "target", Namespace: "default", UID: "uid"},
+		Data:       map[string][]byte{"a": []byte("a"), "b": []byte("b")},
+	}
+	sync := func() (esv1.ExternalSecretStatus, *keyStatuses) {
+		t.Helper()
+		previous := *es.Status.DeepCopy()
+		_, keys, err := r.getProviderSecretData(context.Background(), es)
+		if err != nil {
+			t.Fatalf("getProviderSecretData() returned an unexpected error: %v", err)
+		}
+		return previous, keys
+	}
+
+	// the versions returned by the provider are recorded
+	previous, keys := sync()
+	if versionsUnchanged(es, previous, target, keys) {
+		t.Errorf("expected the first sync to write the target")
+	}
+	if es.Status.Keys[0].Version != "v1" || es.Status.Keys[0].LastModified == nil {
+		t.Errorf("expected the version metadata to be recorded, got %+v", es.Status.Keys[0])
+	}
+	if got := templateMetadata(es)["a"]; got["version"] != "v1" || got["lastModified"] != "2026-10-17T08:00:00Z" {
+		t.Errorf("unexpected template metadata %v", got)
+	}
+	r.markAsDone(es, time.Now(), r.Log, esv1.ConditionReasonSecretSynced, msgSynced)
+	es.Status.TargetHash = targetHash(es, target)
+
+	// the same versions keep the target
+	previous, keys = sync()
+	if !versionsUnchanged(es, previous, target, keys) {
+		t.Errorf("expected unchanged versions to keep the target")
+	}
+
+	// a target changed since the last sync is restored
+	target.Data["a"] = []byte("changed")
+	previous, keys = sync()
+	if versionsUnchanged(es, previous, target, keys) {
+		t.Errorf("expected a changed target to be written")
+	}
+	target.Data["a"] = []byte("a")
+
+	// a reported drift is restored
+	SetExternalSecretCondition(es, *NewExternalSecretCondition(esv1.ExternalSecretDrifted, v1.ConditionTrue, esv1.ConditionReasonSecretDrifted, msgDrifted))
+	previous, keys = sync()
+	if versionsUnchanged(es, previous, target, keys) {
+		t.Errorf("expected a drifted target to be written")
+	}
+	SetExternalSecretCondition(es, *NewExternalSecretCondition(esv1.ExternalSecretDrifted, v1.ConditionFalse, esv1.ConditionReasonDriftRepaired, msgDriftRepaired))
+
+	// a new version of a single entry renders the target
+	versions["b"] = "v2"
+	previous, keys = sync()
+	if versionsUnchanged(es, previous, target, keys) {
+		t.Errorf("expected a new version to write the target")
+	}
+
+	// templates reading from templateFrom are always rendered
+	es.Spec.Target.Template = &esv1.ExternalSecretTemplate{TemplateFrom: []esv1.TemplateFrom{{Literal: ptr.To("{{ .a }}")}}}
+	previous, keys = sync()
+	if versionsUnchanged(es, previous, target, keys) {
+		t.Errorf("expected templateFrom to write the target")
+	}
+}
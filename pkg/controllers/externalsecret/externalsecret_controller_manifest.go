@@ -0,0 +1,559 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalsecret
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"maps"
+	"time"
+	"unicode/utf8"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/json"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	"github.com/external-secrets/external-secrets/pkg/template"
+	"github.com/external-secrets/external-secrets/pkg/utils"
+)
+
+const (
+	// condition messages for "SecretSyncedError" reason.
+	msgErrorGenericTargetsDisabled = "generic targets are disabled"
+	msgErrorGetTarget              = "could not get target"
+
+	// error formats.
+	errGenericTargetsDisabled = "target kind %s requires the controller to run with --unsafe-allow-generic-targets"
+	errRenderManifest         = "could not render manifest template: %w"
+	errParseManifest          = "could not parse rendered manifest: %w"
+
+	// event messages.
+	eventCreatedTarget = "%s created"
+	eventUpdatedTarget = "%s updated"
+	eventDeletedTarget = "%s deleted due to DeletionPolicy=Delete"
+
+	manifestTemplateKey = "manifest"
+)
+
+// isGenericTarget returns true if the ExternalSecret manages a resource other than a Secret.
+func isGenericTarget(es *esv1.ExternalSecret) bool {
+	manifest := es.Spec.Target.Manifest
+	if manifest == nil {
+		return false
+	}
+	return manifest.APIVersion != "v1" || manifest.Kind != "Secret"
+}
+
+// isConfigMapTarget returns true if the ExternalSecret manages a ConfigMap.
+func isConfigMapTarget(es *esv1.ExternalSecret) bool {
+	manifest := es.Spec.Target.Manifest
+	return manifest != nil && manifest.APIVersion == "v1" && manifest.Kind == "ConfigMap"
+}
+
+func targetGVK(es *esv1.ExternalSecret) schema.GroupVersionKind {
+	return schema.FromAPIVersionAndKind(es.Spec.Target.Manifest.APIVersion, es.Spec.Target.Manifest.Kind)
+}
+
+// reconcileGenericTarget reconciles an ExternalSecret with a non-Secret target (ConfigMap or any custom resource).
+// It follows the same creation/deletion policies and bookkeeping (labels, annotations, owner references) as Secret targets.
+func (r *Reconciler) reconcileGenericTarget(ctx context.Context, log logr.Logger, externalSecret *esv1.ExternalSecret, targetName string, start time.Time, resourceLabels prometheus.Labels, syncCallsError prometheus.Counter) (result ctrl.Result, err error) {
+	gvk := targetGVK(externalSecret)
+	log = log.WithValues("targetKind", gvk.Kind, "targetName", targetName)
+
+	// update status of the ExternalSecret when this function returns, if needed.
+	currentStatus := *externalSecret.Status.DeepCopy()
+	defer func() {
+		result, err = r.updateStatus(ctx, log, externalSecret, currentStatus, result, err)
+	}()
+	externalSecret.Status.DryRun = nil
+
+	// writing arbitrary resources is a privilege escalation risk, so it must be explicitly enabled
+	// NOTE: this error cant be fixed by retrying so we don't return an error (which would requeue immediately)
+	if !r.AllowGenericTargets {
+		r.markAsFailed(msgErrorGenericTargetsDisabled, fmt.Errorf(errGenericTargetsDisabled, gvk.Kind), externalSecret, syncCallsError)
+		return ctrl.Result{}, nil
+	}
+
+	// fetch the existing target
+	// NOTE: unstructured objects are not cached, so this is always a live read.
+	existing := &unstructured.Unstructured{}
+	existing.SetGroupVersionKind(gvk)
+	err = r.Get(ctx, client.ObjectKey{Name: targetName, Namespace: externalSecret.Namespace}, existing)
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			r.markAsFailed(msgErrorGetTarget, err, externalSecret, syncCallsError)
+			return ctrl.Result{}, err
+		}
+		existing = &unstructured.Unstructured{}
+		existing.SetGroupVersionKind(gvk)
+	}
+
+	if isGenericTargetValid(existing, externalSecret) {
+		if !shouldRefresh(externalSecret) {
+			log.V(1).Info("skipping refresh")
+			return r.getRequeueResult(externalSecret), nil
+		}
+		if delay := r.postponeRefresh(ctx, externalSecret); delay > 0 {
+			log.V(1).Info("postponing refresh", "delay", delay)
+			return ctrl.Result{RequeueAfter: delay}, nil
+		}
+	}
+
+	dataMap, err := r.GetProviderSecretData(ctx, externalSecret)
+	r.reportExpiry(externalSecret, resourceLabels)
+	if err != nil {
+		r.markAsFailed(msgErrorGetSecretData, err, externalSecret, syncCallsError)
+		return ctrl.Result{}, err
+	}
+
+	// if no data was found we can delete the target if needed.
+	if len(dataMap) == 0 {
+		switch externalSecret.Spec.Target.DeletionPolicy {
+		case esv1.DeletionPolicyDelete:
+			creationPolicy := externalSecret.Spec.Target.CreationPolicy
+			if creationPolicy != esv1.CreatePolicyOwner {
+				err = fmt.Errorf(errDeleteCreatePolicy, targetName, creationPolicy)
+				r.markAsFailed(msgErrorDeleteSecret, err, externalSecret, syncCallsError)
+				return ctrl.Result{}, nil
+			}
+			if existing.GetUID() != "" {
+				err = r.Delete(ctx, existing)
+				if err != nil && !apierrors.IsNotFound(err) {
+					r.markAsFailed(msgErrorDeleteSecret, err, externalSecret, syncCallsError)
+					return ctrl.Result{}, err
+				}
+				r.recorder.Eventf(externalSecret, v1.EventTypeNormal, esv1.ReasonDeleted, eventDeletedTarget, gvk.Kind)
+			}
+			r.markAsDone(externalSecret, start, log, esv1.ConditionReasonSecretDeleted, msgDeleted)
+			return r.getRequeueResult(externalSecret), nil
+		case esv1.DeletionPolicyRetain:
+			r.markAsDone(externalSecret, start, log, esv1.ConditionReasonSecretSynced, msgSyncedRetain)
+			return r.getRequeueResult(externalSecret), nil
+		case esv1.DeletionPolicyMerge:
+		}
+	}
+
+	mutationFunc := func(obj *unstructured.Unstructured) error {
+		return r.mutateGenericTarget(ctx, externalSecret, obj, dataMap)
+	}
+
+	switch externalSecret.Spec.Target.CreationPolicy {
+	case esv1.CreatePolicyNone:
+		log.V(1).Info("target creation skipped due to CreationPolicy=None")
+		err = nil
+	case esv1.CreatePolicyMerge:
+		if existing.GetUID() == "" {
+			r.markAsDone(externalSecret, start, log, esv1.ConditionReasonSecretMissing, msgMissing)
+			return r.getRequeueResult(externalSecret), nil
+		}
+		err = r.updateGenericTarget(ctx, existing, mutationFunc, externalSecret)
+	case esv1.CreatePolicyOrphan:
+		if existing.GetUID() == "" {
+			err = r.createGenericTarget(ctx, gvk, mutationFunc, externalSecret, targetName)
+		} else {
+			err = r.updateGenericTarget(ctx, existing, mutationFunc, externalSecret)
+		}
+	case esv1.CreatePolicyOwner:
+		err = r.deleteOrphanedTargets(ctx, externalSecret, gvk, targetName)
+		if err != nil {
+			r.markAsFailed(msgErrorDeleteOrphaned, err, externalSecret, syncCallsError)
+			return ctrl.Result{}, err
+		}
+		if existing.GetUID() == "" {
+			err = r.createGenericTarget(ctx, gvk, mutationFunc, externalSecret, targetName)
+		} else {
+			err = r.updateGenericTarget(ctx, existing, mutationFunc, externalSecret)
+		}
+	}
+	if err != nil {
+		if apierrors.IsConflict(err) {
+			log.V(1).Info("conflict while updating target, will requeue")
+			return ctrl.Result{Requeue: true}, nil
+		}
+		if errors.Is(err, ErrSecretSetCtrlRef) {
+			r.markAsFailed(msgErrorBecomeOwner, err, externalSecret, syncCallsError)
+			return ctrl.Result{}, nil
+		}
+		if errors.Is(err, ErrSecretIsOwned) {
+			r.markAsFailed(msgErrorIsOwned, err, externalSecret, syncCallsError)
+			return ctrl.Result{}, nil
+		}
+		if errors.Is(err, ErrSecretImmutable) {
+			r.markAsFailed(msgErrorUpdateImmutable, err, externalSecret, syncCallsError)
+			return ctrl.Result{}, nil
+		}
+		r.markAsFailed(msgErrorUpdateSecret, err, externalSecret, syncCallsError)
+		return ctrl.Result{}, err
+	}
+
+	r.markAsDone(externalSecret, start, log, esv1.ConditionReasonSecretSynced, msgSynced)
+	return r.getRequeueResult(externalSecret), nil
+}
+
+// mutateGenericTarget renders the ExternalSecret into the given object.
+// The template is applied to an in-memory Secret first, so templates behave exactly as they do for Secret targets,
+// the result is then copied into the ConfigMap data or rendered into the manifest template.
+func (r *Reconciler) mutateGenericTarget(ctx context.Context, es *esv1.ExternalSecret, obj *unstructured.Unstructured, dataMap map[string][]byte) error {
+	if err := r.reconcileOwnerReference(es, obj); err != nil {
+		return err
+	}
+
+	immutable, _, _ := unstructured.NestedBool(obj.Object, "immutable")
+	if obj.GetUID() != "" && immutable {
+		// metadata is still managed below, any change to the body will be rejected on update
+		setGenericTargetMetadata(es, obj, obj.GetLabels(), obj.GetAnnotations(), obj.GetAnnotations()[esv1.AnnotationDataHash])
+		return nil
+	}
+
+	// the in-memory Secret carries the metadata and managed fields of the target,
+	// so managed labels, annotations and data keys are cleaned up like for Secret targets.
+	rendered := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels:        obj.GetLabels(),
+			Annotations:   obj.GetAnnotations(),
+			ManagedFields: obj.GetManagedFields(),
+		},
+		Data: make(map[string][]byte),
+	}
+	if isConfigMapTarget(es) && es.Spec.Target.CreationPolicy == esv1.CreatePolicyMerge {
+		existingData, err := configMapData(obj)
+		if err != nil {
+			return err
+		}
+		rendered.Data = existingData
+		keys, err := getManagedDataKeys(rendered, es.Name)
+		if err != nil {
+			return err
+		}
+		for _, key := range keys {
+			delete(rendered.Data, key)
+		}
+	}
+	if err := r.ApplyTemplate(ctx, es, rendered, dataMap); err != nil {
+		return fmt.Errorf(errApplyTemplate, err)
+	}
+
+	var hash string
+	if isConfigMapTarget(es) {
+		setConfigMapData(obj, rendered.Data)
+		if es.Spec.Target.Immutable {
+			obj.Object["immutable"] = true
+		}
+		hash = genericTargetHash(obj)
+	} else {
+		body, err := renderManifestBody(es, rendered.Data)
+		if err != nil {
+			return err
+		}
+		// the hash covers the rendered body only, as the API server may default or mutate other fields.
+		// An object which still holds the rendered fields is left as it is, so these fields are not reverted.
+		hash = utils.ObjectHash(body)
+		unchanged := obj.GetAnnotations()[esv1.AnnotationDataHash] == hash && containsFields(obj.Object, body)
+		switch {
+		case unchanged:
+		case es.Spec.Target.CreationPolicy == esv1.CreatePolicyMerge:
+			mergeFields(obj.Object, body)
+		default:
+			for k := range obj.Object {
+				if !isReservedManifestField(k) {
+					delete(obj.Object, k)
+				}
+			}
+			maps.Copy(obj.Object, body)
+		}
+	}
+
+	setGenericTargetMetadata(es, obj, rendered.Labels, rendered.Annotations, hash)
+	return nil
+}
+
+// mergeFields merges the fields of src into dst recursively, fields of dst which are not set in src are kept.
+// Lists and values other than objects are replaced.
+func mergeFields(dst, src map[string]any) {
+	for k, v := range src {
+		srcMap, srcIsMap := v.(map[string]any)
+		dstMap, dstIsMap := dst[k].(map[string]any)
+		if srcIsMap && dstIsMap {
+			mergeFields(dstMap, srcMap)
+			continue
+		}
+		dst[k] = runtime.DeepCopyJSONValue(v)
+	}
+}
+
+// containsFields returns true if all fields of want are set to the same values in obj.
+func containsFields(obj, want map[string]any) bool {
+	for k, v := range want {
+		wantMap, wantIsMap := v.(map[string]any)
+		objMap, objIsMap := obj[k].(map[string]any)
+		if wantIsMap && objIsMap {
+			if !containsFields(objMap, wantMap) {
+				return false
+			}
+			continue
+		}
+		if !equality.Semantic.DeepEqual(obj[k], v) {
+			return false
+		}
+	}
+	return true
+}
+
+// RenderGenericTarget builds the generic target of the ExternalSecret from the rendered Secret,
+// without the bookkeeping metadata added by the controller. It returns nil if the target is a Secret.
+func RenderGenericTarget(es *esv1.ExternalSecret, rendered *v1.Secret) (*unstructured.Unstructured, error) {
+	if !isGenericTarget(es) {
+		return nil, nil
+	}
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(targetGVK(es))
+	obj.SetName(rendered.Name)
+	obj.SetNamespace(rendered.Namespace)
+	obj.SetLabels(rendered.Labels)
+	obj.SetAnnotations(rendered.Annotations)
+	if isConfigMapTarget(es) {
+		setConfigMapData(obj, rendered.Data)
+		if es.Spec.Target.Immutable {
+			obj.Object["immutable"] = true
+		}
+		return obj, nil
+	}
+	body, err := renderManifestBody(es, rendered.Data)
+	if err != nil {
+		return nil, err
+	}
+	maps.Copy(obj.Object, body)
+	return obj, nil
+}
+
+// setGenericTargetMetadata sets labels and annotations, including the ones used for bookkeeping.
+func setGenericTargetMetadata(es *esv1.ExternalSecret, obj *unstructured.Unstructured, lbls, annotations map[string]string, hash string) {
+	if lbls == nil {
+		lbls = make(map[string]string)
+	}
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	if es.Spec.Target.CreationPolicy == esv1.CreatePolicyOwner {
+		lbls[esv1.LabelOwner] = utils.ObjectHash(fmt.Sprintf("%v/%v", es.Namespace, es.Name))
+	} else {
+		delete(lbls, esv1.LabelOwner)
+	}
+	lbls[esv1.LabelManaged] = esv1.LabelManagedValue
+	annotations[esv1.AnnotationDataHash] = hash
+	obj.SetLabels(lbls)
+	obj.SetAnnotations(annotations)
+}
+
+// renderManifestBody executes the manifest template with the rendered secret data
+// and returns all fields except apiVersion, kind, metadata and status.
+func renderManifestBody(es *esv1.ExternalSecret, data map[string][]byte) (map[string]any, error) {
+	engineVersion := esv1.TemplateEngineV2
+	if es.Spec.Target.Template != nil {
+		engineVersion = es.Spec.Target.Template.EngineVersion
+	}
+	execute, err := template.EngineWithMetadata(engineVersion, templateMetadata(es))
+	if err != nil {
+		return nil, err
+	}
+	out := &v1.Secret{}
+	tpl := map[string][]byte{
+		manifestTemplateKey: []byte(es.Spec.Target.Manifest.Template),
+	}
+	if err := execute(tpl, data, esv1.TemplateScopeValues, esv1.TemplateTargetData, out); err != nil {
+		return nil, fmt.Errorf(errRenderManifest, err)
+	}
+	// round-trip through JSON so that numbers are decoded the same way as objects returned by the kube-apiserver
+	// otherwise the data hash would not be stable
+	raw, err := yaml.YAMLToJSON(out.Data[manifestTemplateKey])
+	if err != nil {
+		return nil, fmt.Errorf(errParseManifest, err)
+	}
+	body := make(map[string]any)
+	if err := json.Unmarshal(raw, &body); err != nil {
+		return nil, fmt.Errorf(errParseManifest, err)
+	}
+	for k := range body {
+		if isReservedManifestField(k) {
+			delete(body, k)
+		}
+	}
+	return body, nil
+}
+
+func isReservedManifestField(field string) bool {
+	switch field {
+	case "apiVersion", "kind", "metadata", "status":
+		return true
+	}
+	return false
+}
+
+// configMapData returns the data and binaryData of a ConfigMap as a single map.
+func configMapData(obj *unstructured.Unstructured) (map[string][]byte, error) {
+	out := make(map[string][]byte)
+	data, _, err := unstructured.NestedStringMap(obj.Object, "data")
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range data {
+		out[k] = []byte(v)
+	}
+	binaryData, _, err := unstructured.NestedStringMap(obj.Object, "binaryData")
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range binaryData {
+		decoded, err := base64.StdEncoding.DecodeString(v)
+		if err != nil {
+			return nil, err
+		}
+		out[k] = decoded
+	}
+	return out, nil
+}
+
+// setConfigMapData writes valid UTF-8 values to .data and everything else to .binaryData.
+func setConfigMapData(obj *unstructured.Unstructured, in map[string][]byte) {
+	data := make(map[string]any)
+	binaryData := make(map[string]any)
+	for k, v := range in {
+		if utf8.Valid(v) {
+			data[k] = string(v)
+		} else {
+			binaryData[k] = base64.StdEncoding.EncodeToString(v)
+		}
+	}
+	delete(obj.Object, "data")
+	delete(obj.Object, "binaryData")
+	if len(data) > 0 {
+		obj.Object["data"] = data
+	}
+	if len(binaryData) > 0 {
+		obj.Object["binaryData"] = binaryData
+	}
+}
+
+// genericTargetHash returns the hash of the body of the object, i.e. everything except apiVersion, kind, metadata and status.
+func genericTargetHash(obj *unstructured.Unstructured) string {
+	body := make(map[string]any, len(obj.Object))
+	for k, v := range obj.Object {
+		if !isReservedManifestField(k) {
+			body[k] = v
+		}
+	}
+	return utils.ObjectHash(body)
+}
+
+// isGenericTargetValid checks if the target exists, and it's body is consistent with the calculated hash.
+// The body of custom resources may be defaulted or mutated by the API server, so it is not hashed again here,
+// it is compared against the rendered body on every refresh instead.
+func isGenericTargetValid(existing *unstructured.Unstructured, es *esv1.ExternalSecret) bool {
+	if es.Spec.Target.CreationPolicy == esv1.CreatePolicyOrphan {
+		return true
+	}
+	if existing.GetUID() == "" {
+		return false
+	}
+	if existing.GetLabels()[esv1.LabelManaged] != esv1.LabelManagedValue {
+		return false
+	}
+	hash, ok := existing.GetAnnotations()[esv1.AnnotationDataHash]
+	if !isConfigMapTarget(es) {
+		return ok
+	}
+	return hash == genericTargetHash(existing)
+}
+
+func (r *Reconciler) createGenericTarget(ctx context.Context, gvk schema.GroupVersionKind, mutationFunc func(obj *unstructured.Unstructured) error, es *esv1.ExternalSecret, targetName string) error {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(gvk)
+	obj.SetName(targetName)
+	obj.SetNamespace(es.Namespace)
+	if err := mutationFunc(obj); err != nil {
+		return err
+	}
+	if err := r.Create(ctx, obj, client.FieldOwner(fqdnFor(es.Name))); err != nil {
+		return err
+	}
+	r.recorder.Eventf(es, v1.EventTypeNormal, esv1.ReasonCreated, eventCreatedTarget, gvk.Kind)
+	return nil
+}
+
+func (r *Reconciler) updateGenericTarget(ctx context.Context, existing *unstructured.Unstructured, mutationFunc func(obj *unstructured.Unstructured) error, es *esv1.ExternalSecret) error {
+	updated := existing.DeepCopy()
+	if err := mutationFunc(updated); err != nil {
+		return fmt.Errorf(errMutate, updated.GetName(), err)
+	}
+	if equality.Semantic.DeepEqual(existing, updated) {
+		return nil
+	}
+
+	// the body of an immutable object can not be changed
+	immutable, _, _ := unstructured.NestedBool(existing.Object, "immutable")
+	if immutable && genericTargetHash(existing) != genericTargetHash(updated) {
+		return fmt.Errorf(errUpdate, existing.GetName(), ErrSecretImmutable)
+	}
+
+	if err := r.Update(ctx, updated, client.FieldOwner(fqdnFor(es.Name))); err != nil {
+		if apierrors.IsConflict(err) {
+			return err
+		}
+		return fmt.Errorf(errUpdate, updated.GetName(), err)
+	}
+	r.recorder.Eventf(es, v1.EventTypeNormal, esv1.ReasonUpdated, eventUpdatedTarget, existing.GetKind())
+	return nil
+}
+
+// deleteOrphanedTargets deletes objects of the given kind which are owned by the ExternalSecret but are not the current target,
+// e.g. after the target name was changed.
+func (r *Reconciler) deleteOrphanedTargets(ctx context.Context, es *esv1.ExternalSecret, gvk schema.GroupVersionKind, targetName string) error {
+	ownerLabel := utils.ObjectHash(fmt.Sprintf("%v/%v", es.Namespace, es.Name))
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+	listOpts := &client.ListOptions{
+		LabelSelector: labels.SelectorFromSet(map[string]string{
+			esv1.LabelOwner: ownerLabel,
+		}),
+		Namespace: es.Namespace,
+	}
+	if err := r.List(ctx, list, listOpts); err != nil {
+		return err
+	}
+	for i := range list.Items {
+		if list.Items[i].GetName() == targetName {
+			continue
+		}
+		if err := r.Delete(ctx, &list.Items[i]); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+		r.recorder.Event(es, v1.EventTypeNormal, esv1.ReasonDeleted, eventDeletedOrphaned)
+	}
+	return nil
+}
@@ -0,0 +1,297 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalsecret
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+)
+
+func newManifestTestReconciler(t *testing.T) *Reconciler {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := esv1.AddToScheme(scheme); err != nil {
+		t.Fatalf("could not build scheme: %v", err)
+	}
+	return &Reconciler{Scheme: scheme}
+}
+
+func newManifestTestExternalSecret(manifest *esv1.ExternalSecretTargetManifest) *esv1.ExternalSecret {
+	return &esv1.ExternalSecret{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: esv1.SchemeGroupVersion.String(),
+			Kind:       esv1.ExtSecretKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "es",
+			Namespace: "default",
+			UID:       types.UID("es-uid"),
+		},
+		Spec: esv1.ExternalSecretSpec{
+			Target: esv1.ExternalSecretTarget{
+				CreationPolicy: esv1.CreatePolicyOwner,
+				Manifest:       manifest,
+			},
+		},
+	}
+}
+
+func TestIsGenericTarget(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest *esv1.ExternalSecretTargetManifest
+		expected bool
+	}{
+		{
+			name:     "no manifest",
+			manifest: nil,
+			expected: false,
+		},
+		{
+			name:     "secret manifest",
+			manifest: &esv1.ExternalSecretTargetManifest{APIVersion: "v1", Kind: "Secret"},
+			expected: false,
+		},
+		{
+			name:     "configmap manifest",
+			manifest: &esv1.ExternalSecretTargetManifest{APIVersion: "v1", Kind: "ConfigMap"},
+			expected: true,
+		},
+		{
+			name:     "custom resource manifest",
+			manifest: &esv1.ExternalSecretTargetManifest{APIVersion: "argoproj.io/v1alpha1", Kind: "Application"},
+			expected: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isGenericTarget(newManifestTestExternalSecret(tt.manifest)); got != tt.expected {
+				t.Errorf("isGenericTarget() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMutateGenericTargetConfigMap(t *testing.T) {
+	r := newManifestTestReconciler(t)
+	es := newManifestTestExternalSecret(&esv1.ExternalSecretTargetManifest{APIVersion: "v1", Kind: "ConfigMap"})
+
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(targetGVK(es))
+	obj.SetName("target")
+	obj.SetNamespace("default")
+
+	err := r.mutateGenericTarget(context.Background(), es, obj, map[string][]byte{
+		"endpoint": []byte("https://example.com"),
+		"binary":   {0xff, 0xfe},
+	})
+	if err != nil {
+		t.Fatalf("mutateGenericTarget() returned an unexpected error: %v", err)
+	}
+
+	expectedData := map[string]any{"endpoint": "https://example.com"}
+	if diff := cmp.Diff(expectedData, obj.Object["data"]); diff != "" {
+		t.Errorf("unexpected data (-want, +got)\n%s", diff)
+	}
+	expectedBinaryData := map[string]any{"binary": "//4="}
+	if diff := cmp.Diff(expectedBinaryData, obj.Object["binaryData"]); diff != "" {
+		t.Errorf("unexpected binaryData (-want, +got)\n%s", diff)
+	}
+	if obj.GetLabels()[esv1.LabelManaged] != esv1.LabelManagedValue {
+		t.Errorf("expected managed label to be set")
+	}
+	if obj.GetLabels()[esv1.LabelOwner] == "" {
+		t.Errorf("expected owner label to be set")
+	}
+	if len(obj.GetOwnerReferences()) != 1 || obj.GetOwnerReferences()[0].Name != es.Name {
+		t.Errorf("expected owner reference to ExternalSecret, got %v", obj.GetOwnerReferences())
+	}
+
+	obj.SetUID(types.UID("target-uid"))
+	if !isGenericTargetValid(obj, es) {
+		t.Errorf("expected target to be valid after mutation")
+	}
+	obj.Object["data"] = map[string]any{"endpoint": "https://tampered.example.com"}
+	if isGenericTargetValid(obj, es) {
+		t.Errorf("expected target to be invalid after its data changed")
+	}
+}
+
+func TestMutateGenericTargetManifest(t *testing.T) {
+	r := newManifestTestReconciler(t)
+	es := newManifestTestExternalSecret(&esv1.ExternalSecretTargetManifest{
+		APIVersion: "example.io/v1",
+		Kind:       "Widget",
+		Template: `kind: Ignored
+metadata:
+  name: ignored
+spec:
+  url: "{{ .endpoint }}"
+  replicas: 3
+`,
+	})
+
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(targetGVK(es))
+	obj.SetName("target")
+	obj.SetNamespace("default")
+	obj.Object["stale"] = "value"
+
+	err := r.mutateGenericTarget(context.Background(), es, obj, map[string][]byte{
+		"endpoint": []byte("https://example.com"),
+	})
+	if err != nil {
+		t.Fatalf("mutateGenericTarget() returned an unexpected error: %v", err)
+	}
+
+	expectedSpec := map[string]any{
+		"url":      "https://example.com",
+		"replicas": int64(3),
+	}
+	if diff := cmp.Diff(expectedSpec, obj.Object["spec"]); diff != "" {
+		t.Errorf("unexpected spec (-want, +got)\n%s", diff)
+	}
+	if _, ok := obj.Object["stale"]; ok {
+		t.Errorf("expected stale fields to be removed")
+	}
+	if obj.GetKind() != "Widget" || obj.GetName() != "target" {
+		t.Errorf("expected kind and metadata to be preserved, got kind=%s name=%s", obj.GetKind(), obj.GetName())
+	}
+
+	// fields defaulted by the API server are kept as long as the rendered fields are unchanged
+	obj.SetUID(types.UID("target-uid"))
+	if err := unstructured.SetNestedField(obj.Object, "Always", "spec", "policy"); err != nil {
+		t.Fatalf("could not set field: %v", err)
+	}
+	if !isGenericTargetValid(obj, es) {
+		t.Errorf("expected target with defaulted fields to be valid")
+	}
+	defaulted := obj.DeepCopy()
+	err = r.mutateGenericTarget(context.Background(), es, obj, map[string][]byte{
+		"endpoint": []byte("https://example.com"),
+	})
+	if err != nil {
+		t.Fatalf("mutateGenericTarget() returned an unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(defaulted.Object, obj.Object); diff != "" {
+		t.Errorf("expected unchanged target to be left as it is (-want, +got)\n%s", diff)
+	}
+
+	// a changed rendered field replaces the body
+	err = r.mutateGenericTarget(context.Background(), es, obj, map[string][]byte{
+		"endpoint": []byte("https://changed.example.com"),
+	})
+	if err != nil {
+		t.Fatalf("mutateGenericTarget() returned an unexpected error: %v", err)
+	}
+	expectedSpec["url"] = "https://changed.example.com"
+	if diff := cmp.Diff(expectedSpec, obj.Object["spec"]); diff != "" {
+		t.Errorf("unexpected spec (-want, +got)\n%s", diff)
+	}
+}
+
+func TestMutateGenericTargetManifestMerge(t *testing.T) {
+	r := newManifestTestReconciler(t)
+	es := newManifestTestExternalSecret(&esv1.ExternalSecretTargetManifest{
+		APIVersion: "example.io/v1",
+		Kind:       "Widget",
+		Template: `spec:
+  auth:
+    token: "{{ .token }}"
+  replicas: 3
+`,
+	})
+	es.Spec.Target.CreationPolicy = esv1.CreatePolicyMerge
+
+	obj := &unstructured.Unstructured{Object: map[string]any{
+		"spec": map[string]any{
+			"auth": map[string]any{
+				"token": "old",
+				"user":  "admin",
+			},
+			"url": "https://example.com",
+		},
+	}}
+	obj.SetGroupVersionKind(targetGVK(es))
+	obj.SetName("target")
+	obj.SetNamespace("default")
+	obj.SetUID(types.UID("target-uid"))
+
+	err := r.mutateGenericTarget(context.Background(), es, obj, map[string][]byte{
+		"token": []byte("new"),
+	})
+	if err != nil {
+		t.Fatalf("mutateGenericTarget() returned an unexpected error: %v", err)
+	}
+
+	expectedSpec := map[string]any{
+		"auth": map[string]any{
+			"token": "new",
+			"user":  "admin",
+		},
+		"url":      "https://example.com",
+		"replicas": int64(3),
+	}
+	if diff := cmp.Diff(expectedSpec, obj.Object["spec"]); diff != "" {
+		t.Errorf("unexpected spec (-want, +got)\n%s", diff)
+	}
+}
+
+func TestRenderGenericTarget(t *testing.T) {
+	rendered := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "target", Namespace: "default"},
+		Data:       map[string][]byte{"endpoint": []byte("https://example.com")},
+	}
+
+	secretTarget := newManifestTestExternalSecret(nil)
+	obj, err := RenderGenericTarget(secretTarget, rendered)
+	if err != nil || obj != nil {
+		t.Fatalf("RenderGenericTarget() of a Secret target = %v, %v, want nil", obj, err)
+	}
+
+	configMapTarget := newManifestTestExternalSecret(&esv1.ExternalSecretTargetManifest{APIVersion: "v1", Kind: "ConfigMap"})
+	obj, err = RenderGenericTarget(configMapTarget, rendered)
+	if err != nil {
+		t.Fatalf("RenderGenericTarget() returned an unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(map[string]any{"endpoint": "https://example.com"}, obj.Object["data"]); diff != "" {
+		t.Errorf("unexpected ConfigMap data (-want, +got)\n%s", diff)
+	}
+
+	manifestTarget := newManifestTestExternalSecret(&esv1.ExternalSecretTargetManifest{
+		APIVersion: "example.io/v1",
+		Kind:       "Widget",
+		Template:   "spec:\n  url: \"{{ .endpoint }}\"\n",
+	})
+	obj, err = RenderGenericTarget(manifestTarget, rendered)
+	if err != nil {
+		t.Fatalf("RenderGenericTarget() returned an unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(map[string]any{"url": "https://example.com"}, obj.Object["spec"]); diff != "" {
+		t.Errorf("unexpected spec (-want, +got)\n%s", diff)
+	}
+	if obj.GetKind() != "Widget" || obj.GetName() != "target" {
+		t.Errorf("unexpected kind or name, got kind=%s name=%s", obj.GetKind(), obj.GetName())
+	}
+}
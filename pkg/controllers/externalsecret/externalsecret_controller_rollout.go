@@ -0,0 +1,159 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalsecret
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+)
+
+const (
+	// condition messages for "SecretSyncedError" reason.
+	msgErrorRollout = "could not restart workloads"
+
+	// event messages.
+	eventRolloutTriggered = "restarted %d workloads because the secret data changed"
+	eventRolloutDisabled  = "workloads were not restarted, rollout triggers are disabled on the controller"
+)
+
+// rolloutKinds are the kinds of workloads which can be restarted.
+var rolloutKinds = []string{"Deployment", "StatefulSet", "DaemonSet"}
+
+// isRolloutPending returns true if the data of the target changed since the workloads were last restarted.
+// It forces a refresh, so delayed rollouts are picked up once the minimum interval has elapsed.
+func (r *Reconciler) isRolloutPending(externalSecret *esv1.ExternalSecret, existingSecret *v1.Secret) bool {
+	if !r.EnableRolloutTriggers || externalSecret.Spec.Rollout == nil || externalSecret.Status.Rollout == nil {
+		return false
+	}
+	return existingSecret.UID != "" && externalSecret.Status.Rollout.DataHash != existingSecret.Annotations[esv1.AnnotationDataHash]
+}
+
+// reconcileRollout restarts the workloads of spec.rollout if the data of the target changed,
+// and returns the time after which a delayed rollout is due.
+// The first sync only records the data hash, as the workloads are started with the current data anyway.
+func (r *Reconciler) reconcileRollout(ctx context.Context, externalSecret *esv1.ExternalSecret, target *v1.Secret) (time.Duration, error) {
+	rollout := externalSecret.Spec.Rollout
+	if rollout == nil {
+		externalSecret.Status.Rollout = nil
+		return 0, nil
+	}
+	if target == nil {
+		return 0, nil
+	}
+
+	dataHash := target.Annotations[esv1.AnnotationDataHash]
+	status := externalSecret.Status.Rollout
+	if status == nil {
+		externalSecret.Status.Rollout = &esv1.ExternalSecretRolloutStatus{DataHash: dataHash}
+		return 0, nil
+	}
+	if status.DataHash == dataHash {
+		return 0, nil
+	}
+
+	if !r.EnableRolloutTriggers {
+		r.recorder.Event(externalSecret, v1.EventTypeWarning, esv1.ReasonRolloutFailed, eventRolloutDisabled)
+		status.DataHash = dataHash
+		return 0, nil
+	}
+
+	// wait for the minimum interval, the change is rolled out with the next reconcile after it has elapsed
+	if rollout.MinInterval != nil && status.LastRolloutTime != nil {
+		if remaining := rollout.MinInterval.Duration - time.Since(status.LastRolloutTime.Time); remaining > 0 {
+			return remaining, nil
+		}
+	}
+
+	now := metav1.Now()
+	restarted, err := r.restartWorkloads(ctx, externalSecret, now)
+	if err != nil {
+		r.recorder.Event(externalSecret, v1.EventTypeWarning, esv1.ReasonRolloutFailed, err.Error())
+		return 0, err
+	}
+	status.DataHash = dataHash
+	status.LastRolloutTime = &now
+	r.recorder.Eventf(externalSecret, v1.EventTypeNormal, esv1.ReasonRolloutTriggered, eventRolloutTriggered, restarted)
+	return 0, nil
+}
+
+// restartWorkloads sets the restart annotation on the pod template of all listed and selected workloads.
+func (r *Reconciler) restartWorkloads(ctx context.Context, externalSecret *esv1.ExternalSecret, now metav1.Time) (int, error) {
+	rollout := externalSecret.Spec.Rollout
+	workloads := make(map[esv1.ExternalSecretRolloutWorkload]struct{})
+	for _, w := range rollout.Workloads {
+		workloads[w] = struct{}{}
+	}
+	if rollout.Selector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(rollout.Selector)
+		if err != nil {
+			return 0, fmt.Errorf("invalid rollout selector: %w", err)
+		}
+		for _, kind := range rolloutKinds {
+			list := &unstructured.UnstructuredList{}
+			list.SetGroupVersionKind(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: kind + "List"})
+			if err := r.List(ctx, list, client.InNamespace(externalSecret.Namespace), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+				return 0, fmt.Errorf("could not list %s: %w", kind, err)
+			}
+			for _, item := range list.Items {
+				workloads[esv1.ExternalSecretRolloutWorkload{Kind: kind, Name: item.GetName()}] = struct{}{}
+			}
+		}
+	}
+
+	patch, err := json.Marshal(map[string]any{
+		"spec": map[string]any{
+			"template": map[string]any{
+				"metadata": map[string]any{
+					"annotations": map[string]string{
+						esv1.AnnotationRestartedAt: now.UTC().Format(time.RFC3339),
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	restarted := 0
+	for w := range workloads {
+		obj := &unstructured.Unstructured{}
+		obj.SetGroupVersionKind(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: w.Kind})
+		obj.SetName(w.Name)
+		obj.SetNamespace(externalSecret.Namespace)
+		err := r.Patch(ctx, obj, client.RawPatch(types.MergePatchType, patch), client.FieldOwner(fqdnFor(externalSecret.Name)))
+		// workloads may be created after the ExternalSecret, so missing ones are skipped
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return restarted, fmt.Errorf("could not restart %s %s: %w", w.Kind, w.Name, err)
+		}
+		restarted++
+	}
+	return restarted, nil
+}
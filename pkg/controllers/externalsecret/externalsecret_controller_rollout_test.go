@@ -0,0 +1,110 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalsecret
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+)
+
+func TestReconcileRollout(t *testing.T) {
+	r := newManifestTestReconciler(t)
+	if err := appsv1.AddToScheme(r.Scheme); err != nil {
+		t.Fatalf("could not build scheme: %v", err)
+	}
+	r.recorder = record.NewFakeRecorder(10)
+	r.EnableRolloutTriggers = true
+	ctx := context.Background()
+
+	listed := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "listed", Namespace: "default"}}
+	selected := &appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: "selected", Namespace: "default", Labels: map[string]string{"app": "foo"}}}
+	other := &appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "default", Labels: map[string]string{"app": "bar"}}}
+	r.Client = fake.NewClientBuilder().WithScheme(r.Scheme).WithObjects(listed, selected, other).Build()
+
+	es := newManifestTestExternalSecret(nil)
+	es.Spec.Rollout = &esv1.ExternalSecretRollout{
+		Workloads: []esv1.ExternalSecretRolloutWorkload{
+			{Kind: "Deployment", Name: "listed"},
+			{Kind: "Deployment", Name: "missing"},
+		},
+		Selector:    &metav1.LabelSelector{MatchLabels: map[string]string{"app": "foo"}},
+		MinInterval: &metav1.Duration{Duration: time.Hour},
+	}
+	target := func(hash string) *v1.Secret {
+		return &v1.Secret{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{esv1.AnnotationDataHash: hash}}}
+	}
+	restartedAt := func(obj client.Object) string {
+		t.Helper()
+		if err := r.Get(ctx, client.ObjectKeyFromObject(obj), obj); err != nil {
+			t.Fatalf("could not get workload: %v", err)
+		}
+		switch o := obj.(type) {
+		case *appsv1.Deployment:
+			return o.Spec.Template.Annotations[esv1.AnnotationRestartedAt]
+		case *appsv1.StatefulSet:
+			return o.Spec.Template.Annotations[esv1.AnnotationRestartedAt]
+		case *appsv1.DaemonSet:
+			return o.Spec.Template.Annotations[esv1.AnnotationRestartedAt]
+		}
+		return ""
+	}
+
+	// the first sync only records the hash
+	if _, err := r.reconcileRollout(ctx, es, target("a")); err != nil {
+		t.Fatalf("reconcileRollout() returned an unexpected error: %v", err)
+	}
+	if restartedAt(listed.DeepCopy()) != "" {
+		t.Errorf("expected no restart on the first sync")
+	}
+
+	// a change restarts the listed and selected workloads
+	if _, err := r.reconcileRollout(ctx, es, target("b")); err != nil {
+		t.Fatalf("reconcileRollout() returned an unexpected error: %v", err)
+	}
+	if restartedAt(listed.DeepCopy()) == "" || restartedAt(selected.DeepCopy()) == "" {
+		t.Errorf("expected listed and selected workloads to be restarted")
+	}
+	if restartedAt(other.DeepCopy()) != "" {
+		t.Errorf("expected other workloads not to be restarted")
+	}
+	if es.Status.Rollout.DataHash != "b" || es.Status.Rollout.LastRolloutTime == nil {
+		t.Errorf("unexpected rollout status: %+v", es.Status.Rollout)
+	}
+
+	// another change within the minimum interval is delayed
+	after, err := r.reconcileRollout(ctx, es, target("c"))
+	if err != nil {
+		t.Fatalf("reconcileRollout() returned an unexpected error: %v", err)
+	}
+	if after <= 0 || after > time.Hour {
+		t.Errorf("expected rollout to be delayed, got: %v", after)
+	}
+	if es.Status.Rollout.DataHash != "b" {
+		t.Errorf("expected delayed rollout not to update the hash, got: %s", es.Status.Rollout.DataHash)
+	}
+	if !r.isRolloutPending(es, &v1.Secret{ObjectMeta: metav1.ObjectMeta{UID: "uid", Annotations: map[string]string{esv1.AnnotationDataHash: "c"}}}) {
+		t.Errorf("expected delayed rollout to be pending")
+	}
+}
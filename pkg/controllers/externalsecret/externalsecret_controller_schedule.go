@@ -0,0 +1,55 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalsecret
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	"github.com/external-secrets/external-secrets/pkg/controllers/secretstore"
+	"github.com/external-secrets/external-secrets/pkg/controllers/util"
+)
+
+// postponeRefresh returns how long a periodic refresh of the ExternalSecret is postponed
+// by the startup stagger of the controller and the refresh budget of its store.
+// Refreshes of new or changed ExternalSecrets are never postponed.
+func (r *Reconciler) postponeRefresh(ctx context.Context, es *esv1.ExternalSecret) time.Duration {
+	if es.Status.RefreshTime.IsZero() || es.Status.SyncedResourceVersion != util.GetResourceVersion(es.ObjectMeta) {
+		return 0
+	}
+	key := types.NamespacedName{Namespace: es.Namespace, Name: es.Name}.String()
+	if delay := r.Schedule.Stagger(key); delay > 0 {
+		return delay
+	}
+
+	storeRef := es.Spec.SecretStoreRef
+	if storeRef.Name == "" {
+		return 0
+	}
+	var store esv1.GenericStore = &esv1.SecretStore{}
+	namespace := es.Namespace
+	if storeRef.Kind == esv1.ClusterSecretStoreKind {
+		store = &esv1.ClusterSecretStore{}
+		namespace = ""
+	}
+	// a store which can't be read fails the refresh right after, so it is not postponed
+	if err := r.Get(ctx, types.NamespacedName{Name: storeRef.Name, Namespace: namespace}, store); err != nil {
+		return 0
+	}
+	return secretstore.ReserveRefresh(store, key)
+}
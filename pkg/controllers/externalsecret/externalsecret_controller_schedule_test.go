@@ -0,0 +1,92 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalsecret
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	"github.com/external-secrets/external-secrets/pkg/controllers/util"
+)
+
+func TestPostponeRefresh(t *testing.T) {
+	r := newManifestTestReconciler(t)
+	store := &esv1.SecretStore{
+		ObjectMeta: metav1.ObjectMeta{Name: "budgeted", Namespace: "default"},
+		Spec: esv1.SecretStoreSpec{
+			Provider:      &esv1.SecretStoreProvider{AWS: &esv1.AWSProvider{}},
+			RefreshBudget: &esv1.SecretStoreRefreshBudget{RefreshesPerMinute: 1},
+		},
+	}
+	r.Client = fake.NewClientBuilder().WithScheme(r.Scheme).WithObjects(store).Build()
+	newES := func(name string) *esv1.ExternalSecret {
+		es := newManifestTestExternalSecret(nil)
+		es.Name = name
+		es.Spec.SecretStoreRef = esv1.SecretStoreRef{Name: "budgeted"}
+		es.Status.RefreshTime = metav1.NewTime(time.Now().Add(-2 * time.Hour))
+		es.Status.SyncedResourceVersion = util.GetResourceVersion(es.ObjectMeta)
+		return es
+	}
+	ctx := context.Background()
+
+	// the first periodic refresh takes the budget, the next one has to wait for the next slot
+	if delay := r.postponeRefresh(ctx, newES("first")); delay != 0 {
+		t.Errorf("expected the first refresh not to be postponed, got %s", delay)
+	}
+	if delay := r.postponeRefresh(ctx, newES("second")); delay <= 50*time.Second || delay > time.Minute {
+		t.Errorf("expected the second refresh to be postponed by about a minute, got %s", delay)
+	}
+
+	// changed and new ExternalSecrets are refreshed right away
+	changed := newES("changed")
+	changed.Generation = 2
+	if delay := r.postponeRefresh(ctx, changed); delay != 0 {
+		t.Errorf("expected a changed ExternalSecret not to be postponed, got %s", delay)
+	}
+	created := newES("created")
+	created.Status = esv1.ExternalSecretStatus{}
+	if delay := r.postponeRefresh(ctx, created); delay != 0 {
+		t.Errorf("expected a new ExternalSecret not to be postponed, got %s", delay)
+	}
+
+	// ExternalSecrets of stores without a refresh budget are not postponed
+	unbudgeted := newES("unbudgeted")
+	unbudgeted.Spec.SecretStoreRef = esv1.SecretStoreRef{Name: "missing"}
+	if delay := r.postponeRefresh(ctx, unbudgeted); delay != 0 {
+		t.Errorf("expected a refresh without a store not to be postponed, got %s", delay)
+	}
+}
+
+func TestGetRequeueResultJitter(t *testing.T) {
+	schedule, err := util.NewSchedule(50, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := &Reconciler{Schedule: schedule}
+	es := newManifestTestExternalSecret(nil)
+	es.Spec.RefreshInterval = &metav1.Duration{Duration: time.Hour}
+	es.Status.RefreshTime = metav1.Now()
+	for range 20 {
+		requeueAfter := r.getRequeueResult(es).RequeueAfter
+		if requeueAfter < 59*time.Minute || requeueAfter >= 90*time.Minute {
+			t.Errorf("expected the requeue to be jittered by up to 50%% of the refresh interval, got %s", requeueAfter)
+		}
+	}
+}
@@ -19,6 +19,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
+	"slices"
 	"strconv"
 
 	v1 "k8s.io/api/core/v1"
@@ -36,13 +38,22 @@ import (
 	_ "github.com/external-secrets/external-secrets/pkg/provider/register"
 )
 
-// getProviderSecretData returns the provider's secret data with the provided ExternalSecret.
-func (r *Reconciler) GetProviderSecretData(ctx context.Context, externalSecret *esv1.ExternalSecret) (providerData map[string][]byte, err error) {
+// GetProviderSecretData returns the provider's secret data with the provided ExternalSecret.
+func (r *Reconciler) GetProviderSecretData(ctx context.Context, externalSecret *esv1.ExternalSecret) (map[string][]byte, error) {
+	providerData, _, err := r.getProviderSecretData(ctx, externalSecret)
+	return providerData, err
+}
+
+// getProviderSecretData returns the provider's secret data, and the status of its entries.
+func (r *Reconciler) getProviderSecretData(ctx context.Context, externalSecret *esv1.ExternalSecret) (providerData map[string][]byte, keys *keyStatuses, err error) {
 	// We MUST NOT create multiple instances of a provider client (mostly due to limitations with GCP)
 	// Clientmanager keeps track of the client instances
 	// that are created during the fetching process and closes clients
 	// if needed.
-	mgr := secretstore.NewManager(r.Client, r.ControllerClass, r.EnableFloodGate)
+	mgr := secretstore.NewManager(r.Client, r.ControllerClass, r.EnableFloodGate).
+		WithResponseCache(r.ResponseCache).
+		WithRefreshedResponses(isResyncRequested(externalSecret)).
+		WithClientPool(r.ClientPool)
 	defer func() {
 		_ = mgr.Close(ctx)
 	}()
@@ -58,7 +69,8 @@ func (r *Reconciler) GetProviderSecretData(ctx context.Context, externalSecret *
 			// A generator is expected to always generate a secret.
 			// If it doesn't, it should return an error.
 			// If the error is NoSecretErr, we should commit the generator state.
-			if err != nil && !errors.Is(err, esv1.NoSecretErr) {
+			// In dry-run mode, nothing must be persisted, so the generator state is always rolled back.
+			if (err != nil && !errors.Is(err, esv1.NoSecretErr)) || externalSecret.Spec.DryRun {
 				if rollBackErr := genState.Rollback(); rollBackErr != nil {
 					r.Log.Error(rollBackErr, "error rolling back generator state")
 				}
@@ -72,9 +84,21 @@ func (r *Reconciler) GetProviderSecretData(ctx context.Context, externalSecret *
 			}
 		}()
 	}
+	if len(externalSecret.Spec.FallbackStoreRefs) == 0 {
+		externalSecret.Status.ActiveStoreRef = nil
+	}
+	// the status of every entry is recorded, including the entries which failed.
+	keys = newKeyStatuses(externalSecret)
+	defer keys.apply(externalSecret)
+
 	providerData = make(map[string][]byte)
+	var missingKeys []esv1.ExternalSecretMissingKey
+	// all entries are fetched even if one fails, so the status of every entry is up to date.
+	var entryErrs []error
 	for i, remoteRef := range externalSecret.Spec.DataFrom {
 		var secretMap map[string][]byte
+		// the metadata of generated values, dataFrom entries of stores have no metadata
+		var metadata *esv1.SecretMetadata
 
 		if remoteRef.Find != nil {
 			secretMap, err = r.handleFindAllSecrets(ctx, externalSecret, remoteRef, mgr, genState, i)
@@ -87,57 +111,156 @@ func (r *Reconciler) GetProviderSecretData(ctx context.Context, externalSecret *
 				err = fmt.Errorf("error processing spec.dataFrom[%d].extract, err: %w", i, err)
 			}
 		} else if remoteRef.SourceRef != nil && remoteRef.SourceRef.GeneratorRef != nil {
-			secretMap, err = r.handleGenerateSecrets(ctx, externalSecret.Namespace, remoteRef, i, genState)
+			secretMap, metadata, err = r.handleGenerateSecrets(ctx, externalSecret.Namespace, remoteRef, i, genState)
 			if err != nil {
 				err = fmt.Errorf("error processing spec.dataFrom[%d].sourceRef.generatorRef, err: %w", i, err)
 			}
 		}
-
+		// optional entries are skipped (or defaulted) if the remote secret does not exist
+		optionalMissing := errors.Is(err, esv1.NoSecretErr) && remoteRef.Optional
+		keys.observe(dataFromKeyStatus(externalSecret, remoteRef, i), withValueExpiry(metadata, slices.Collect(maps.Values(secretMap))...), observedErr(err, optionalMissing))
+		if optionalMissing {
+			r.recorder.Eventf(externalSecret, v1.EventTypeNormal, esv1.ReasonMissingProviderSecret, eventMissingProviderSecret, i)
+			missingKeys = append(missingKeys, esv1.ExternalSecretMissingKey{
+				Path:      fmt.Sprintf("spec.dataFrom[%d]", i),
+				RemoteKey: dataFromRemoteKey(remoteRef),
+				Defaulted: remoteRef.Default != nil,
+			})
+			for k, v := range remoteRef.Default {
+				providerData[k] = []byte(v)
+			}
+			err = nil
+			continue
+		}
 		if errors.Is(err, esv1.NoSecretErr) && externalSecret.Spec.Target.DeletionPolicy != esv1.DeletionPolicyRetain {
 			r.recorder.Eventf(externalSecret, v1.EventTypeNormal, esv1.ReasonMissingProviderSecret, eventMissingProviderSecret, i)
 			continue
 		}
 		if err != nil {
-			return nil, err
+			entryErrs = append(entryErrs, err)
+			continue
 		}
 
 		providerData = utils.MergeByteMap(providerData, secretMap)
 	}
 
+	prefetched := r.prefetchSecretData(ctx, externalSecret, mgr)
 	for i, secretRef := range externalSecret.Spec.Data {
-		err := r.handleSecretData(ctx, externalSecret, secretRef, providerData, mgr)
+		var metadata *esv1.SecretMetadata
+		var err error
+		if secret, ok := prefetched[i]; ok {
+			metadata, err = secret.metadata, secret.err
+			if err == nil {
+				err = setSecretData(secretRef, providerData, secret.value)
+			}
+		} else {
+			metadata, err = r.handleSecretData(ctx, externalSecret, secretRef, providerData, mgr)
+		}
+		if err == nil {
+			metadata = withValueExpiry(metadata, providerData[secretRef.SecretKey])
+		}
+		// optional entries are skipped (or defaulted) if the remote secret does not exist
+		optionalMissing := errors.Is(err, esv1.NoSecretErr) && secretRef.Optional
+		keys.observe(dataKeyStatus(externalSecret, secretRef, i), metadata, observedErr(err, optionalMissing))
+		if optionalMissing {
+			r.recorder.Eventf(externalSecret, v1.EventTypeNormal, esv1.ReasonMissingProviderSecret, eventMissingProviderSecretDataKey, i, secretRef.RemoteRef.Key)
+			missingKeys = append(missingKeys, esv1.ExternalSecretMissingKey{
+				Path:      fmt.Sprintf("spec.data[%d]", i),
+				RemoteKey: secretRef.RemoteRef.Key,
+				Defaulted: secretRef.Default != nil,
+			})
+			if secretRef.Default != nil {
+				providerData[secretRef.SecretKey] = []byte(*secretRef.Default)
+			}
+			continue
+		}
 		if errors.Is(err, esv1.NoSecretErr) && externalSecret.Spec.Target.DeletionPolicy != esv1.DeletionPolicyRetain {
 			r.recorder.Eventf(externalSecret, v1.EventTypeNormal, esv1.ReasonMissingProviderSecret, eventMissingProviderSecretKey, i, secretRef.RemoteRef.Key)
 			continue
 		}
 		if err != nil {
-			return nil, fmt.Errorf("error processing spec.data[%d] (key: %s), err: %w", i, secretRef.RemoteRef.Key, err)
+			entryErrs = append(entryErrs, fmt.Errorf("error processing spec.data[%d] (key: %s), err: %w", i, secretRef.RemoteRef.Key, err))
 		}
 	}
+	if len(entryErrs) > 0 {
+		return nil, keys, joinEntryErrors(entryErrs)
+	}
+
+	externalSecret.Status.MissingKeys = missingKeys
 
-	return providerData, nil
+	// the data is validated before the template is applied, so invalid values never reach the target.
+	if err := validateSecretData(externalSecret, providerData); err != nil {
+		return nil, keys, err
+	}
+	return providerData, keys, nil
 }
 
-func (r *Reconciler) handleSecretData(ctx context.Context, externalSecret *esv1.ExternalSecret, secretRef esv1.ExternalSecretData, providerData map[string][]byte, cmgr *secretstore.Manager) error {
-	client, err := cmgr.Get(ctx, externalSecret.Spec.SecretStoreRef, externalSecret.Namespace, toStoreGenSourceRef(secretRef.SourceRef))
-	if err != nil {
-		return err
+// observedErr returns the error recorded in the status of an entry.
+// A missing remote secret of an optional entry is not an error, it is reported in status.missingKeys instead.
+func observedErr(err error, optionalMissing bool) error {
+	if optionalMissing {
+		return nil
+	}
+	return err
+}
+
+// joinEntryErrors aggregates the errors of all entries.
+// The result only matches esv1.NoSecretErr if all entries failed with it, so other errors
+// are not mistaken for missing secrets, e.g. when committing the generator state.
+func joinEntryErrors(errs []error) error {
+	onlyMissing := !slices.ContainsFunc(errs, func(err error) bool {
+		return !errors.Is(err, esv1.NoSecretErr)
+	})
+	if onlyMissing {
+		return errors.Join(errs...)
+	}
+	joined := make([]error, 0, len(errs))
+	for _, err := range errs {
+		if errors.Is(err, esv1.NoSecretErr) {
+			err = errors.New(err.Error())
+		}
+		joined = append(joined, err)
+	}
+	return errors.Join(joined...)
+}
+
+// dataFromRemoteKey returns a human-readable reference to the remote secret of a dataFrom entry.
+func dataFromRemoteKey(remoteRef esv1.ExternalSecretDataFromRemoteRef) string {
+	switch {
+	case remoteRef.Extract != nil:
+		return remoteRef.Extract.Key
+	case remoteRef.Find != nil && remoteRef.Find.Name != nil:
+		return remoteRef.Find.Name.RegExp
+	default:
+		return ""
 	}
+}
 
+// handleSecretData fetches a single entry of spec.data, and returns the metadata of its version if the provider returns it.
+func (r *Reconciler) handleSecretData(ctx context.Context, externalSecret *esv1.ExternalSecret, secretRef esv1.ExternalSecretData, providerData map[string][]byte, cmgr *secretstore.Manager) (*esv1.SecretMetadata, error) {
 	// get a single secret from the store
-	secretData, err := client.GetSecret(ctx, secretRef.RemoteRef)
-	if err != nil {
+	var secretData []byte
+	var metadata *esv1.SecretMetadata
+	err := r.withStoreClient(ctx, externalSecret, toStoreGenSourceRef(secretRef.SourceRef), cmgr, func(client esv1.SecretsClient) (err error) {
+		secretData, metadata, err = esv1.GetSecretWithMetadata(ctx, client, secretRef.RemoteRef)
 		return err
+	})
+	if err != nil {
+		return nil, err
 	}
+	return metadata, setSecretData(secretRef, providerData, secretData)
+}
 
+// setSecretData decodes the value of a spec.data entry and adds it to providerData.
+func setSecretData(secretRef esv1.ExternalSecretData, providerData map[string][]byte, secretData []byte) error {
 	// decode the secret if needed
-	secretData, err = utils.Decode(secretRef.RemoteRef.DecodingStrategy, secretData)
+	decoded, err := utils.Decode(secretRef.RemoteRef.DecodingStrategy, secretData)
 	if err != nil {
 		return fmt.Errorf(errDecode, secretRef.RemoteRef.DecodingStrategy, err)
 	}
 
 	// store the secret data
-	providerData[secretRef.SecretKey] = secretData
+	providerData[secretRef.SecretKey] = decoded
 
 	return nil
 }
@@ -151,22 +274,24 @@ func toStoreGenSourceRef(ref *esv1.StoreSourceRef) *esv1.StoreGeneratorSourceRef
 	}
 }
 
-func (r *Reconciler) handleGenerateSecrets(ctx context.Context, namespace string, remoteRef esv1.ExternalSecretDataFromRemoteRef, i int, generatorState *statemanager.Manager) (map[string][]byte, error) {
+func (r *Reconciler) handleGenerateSecrets(ctx context.Context, namespace string, remoteRef esv1.ExternalSecretDataFromRemoteRef, i int, generatorState *statemanager.Manager) (map[string][]byte, *esv1.SecretMetadata, error) {
 	impl, generatorResource, err := resolvers.GeneratorRef(ctx, r.Client, r.Scheme, namespace, remoteRef.SourceRef.GeneratorRef)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	var latestState *genv1alpha1.GeneratorState
 	if generatorState != nil {
 		latestState, err = generatorState.GetLatestState(generatorStateKey(i))
 		if err != nil {
-			return nil, fmt.Errorf("unable to get latest state: %w", err)
+			return nil, nil, fmt.Errorf("unable to get latest state: %w", err)
 		}
 	}
 	secretMap, newState, err := impl.Generate(ctx, generatorResource, r.Client, namespace)
 	if err != nil {
-		return nil, fmt.Errorf(errGenerate, err)
+		return nil, nil, fmt.Errorf(errGenerate, err)
 	}
+	// the expiry is read before the keys are rewritten
+	metadata := generatorMetadata(impl, secretMap)
 	if latestState != nil {
 		if generatorState != nil {
 			generatorState.EnqueueMoveStateToGC(generatorStateKey(i))
@@ -178,16 +303,16 @@ func (r *Reconciler) handleGenerateSecrets(ctx context.Context, namespace string
 	// rewrite the keys if needed
 	secretMap, err = utils.RewriteMap(remoteRef.Rewrite, secretMap)
 	if err != nil {
-		return nil, fmt.Errorf(errRewrite, err)
+		return nil, nil, fmt.Errorf(errRewrite, err)
 	}
 
 	// validate the keys
 	err = utils.ValidateKeys(r.Log, secretMap)
 	if err != nil {
-		return nil, fmt.Errorf(errInvalidKeys, err)
+		return nil, nil, fmt.Errorf(errInvalidKeys, err)
 	}
 
-	return secretMap, err
+	return secretMap, metadata, err
 }
 
 // We're using the index of the generator as the key for the generator state
@@ -198,13 +323,12 @@ func generatorStateKey(i int) string {
 }
 
 func (r *Reconciler) handleExtractSecrets(ctx context.Context, externalSecret *esv1.ExternalSecret, remoteRef esv1.ExternalSecretDataFromRemoteRef, cmgr *secretstore.Manager, genState *statemanager.Manager, i int) (map[string][]byte, error) {
-	client, err := cmgr.Get(ctx, externalSecret.Spec.SecretStoreRef, externalSecret.Namespace, remoteRef.SourceRef)
-	if err != nil {
-		return nil, err
-	}
-
 	// get multiple secrets from the store
-	secretMap, err := client.GetSecretMap(ctx, *remoteRef.Extract)
+	var secretMap map[string][]byte
+	err := r.withStoreClient(ctx, externalSecret, remoteRef.SourceRef, cmgr, func(client esv1.SecretsClient) (err error) {
+		secretMap, err = client.GetSecretMap(ctx, *remoteRef.Extract)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -239,13 +363,12 @@ func (r *Reconciler) handleExtractSecrets(ctx context.Context, externalSecret *e
 }
 
 func (r *Reconciler) handleFindAllSecrets(ctx context.Context, externalSecret *esv1.ExternalSecret, remoteRef esv1.ExternalSecretDataFromRemoteRef, cmgr *secretstore.Manager, genState *statemanager.Manager, i int) (map[string][]byte, error) {
-	client, err := cmgr.Get(ctx, externalSecret.Spec.SecretStoreRef, externalSecret.Namespace, remoteRef.SourceRef)
-	if err != nil {
-		return nil, err
-	}
-
 	// get all secrets from the store that match the selector
-	secretMap, err := client.GetAllSecrets(ctx, *remoteRef.Find)
+	var secretMap map[string][]byte
+	err := r.withStoreClient(ctx, externalSecret, remoteRef.SourceRef, cmgr, func(client esv1.SecretsClient) (err error) {
+		secretMap, err = client.GetAllSecrets(ctx, *remoteRef.Find)
+		return err
+	})
 	if err != nil {
 		return nil, fmt.Errorf("error getting all secrets: %w", err)
 	}
@@ -62,7 +62,7 @@ func (r *Reconciler) ApplyTemplate(ctx context.Context, es *esv1.ExternalSecret,
 		maps.Insert(secret.Data, maps.All(dataMap))
 	}
 
-	execute, err := template.EngineForVersion(es.Spec.Target.Template.EngineVersion)
+	execute, err := template.EngineWithMetadata(es.Spec.Target.Template.EngineVersion, templateMetadata(es))
 	if err != nil {
 		return err
 	}
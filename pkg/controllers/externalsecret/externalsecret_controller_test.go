@@ -34,6 +34,7 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
@@ -1502,6 +1503,26 @@ var _ = Describe("ExternalSecret controller", Serial, func() {
 		}
 	}
 
+	// optional data entries should fall back to their default value
+	// if the provider secret does not exist
+	optionalDataWithDefault := func(tc *testCase) {
+		const defaultVal = "default-value"
+		fakeProvider.WithGetSecret(nil, esv1.NoSecretErr)
+		tc.externalSecret.Spec.Target.DeletionPolicy = esv1.DeletionPolicyRetain
+		tc.externalSecret.Spec.Data[0].Optional = true
+		tc.externalSecret.Spec.Data[0].Default = ptr.To(defaultVal)
+		tc.checkSecret = func(es *esv1.ExternalSecret, secret *v1.Secret) {
+			Expect(string(secret.Data[targetProp])).To(Equal(defaultVal))
+			Expect(es.Status.MissingKeys).To(Equal([]esv1.ExternalSecretMissingKey{
+				{
+					Path:      "spec.data[0]",
+					RemoteKey: remoteKey,
+					Defaulted: true,
+				},
+			}))
+		}
+	}
+
 	// merge with existing secret using creationPolicy=Merge
 	// if provider secret gets deleted only the managed field should get deleted
 	deletionPolicyMerge := func(tc *testCase) {
@@ -2393,6 +2414,7 @@ var _ = Describe("ExternalSecret controller", Serial, func() {
 		Entry("should not delete target secret with deletionPolicy=Retain", deletionPolicyRetain),
 		Entry("should update the status properly even if the deletionPolicy is Retain and the data is empty", deletionPolicyRetainEmptyData),
 		Entry("should not delete pre-existing secret with deletionPolicy=Merge", deletionPolicyMerge),
+		Entry("should use the default value of an optional data entry if the provider secret does not exist", optionalDataWithDefault),
 		Entry("secret is created when there are no conditions for the cluster secret store", useClusterSecretStore, noConditionsSecretCreated),
 		Entry("secret is not created when the condition for the cluster secret store states a different namespace single string condition", useClusterSecretStore, noSecretCreatedWhenNamespaceDoesntMatchStringCondition),
 		Entry("secret is not created when the condition for the cluster secret store states a different namespace single string condition with multiple names", useClusterSecretStore, noSecretCreatedWhenNamespaceDoesntMatchStringConditionWithMultipleNames),
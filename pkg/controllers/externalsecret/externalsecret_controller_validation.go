@@ -0,0 +1,225 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalsecret
+
+import (
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/google/cel-go/ext"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+)
+
+const (
+	// condition messages for "SecretValidationFailed" reason.
+	msgErrorValidation = "secret data did not pass the validation rules"
+
+	// error formats.
+	errValidationRule       = "rule %d: %s"
+	errValidationKeyMissing = "rule %d: key %q does not exist"
+	errValidationRuleKey    = "rule %d, key %q: %s"
+
+	// celCostLimit bounds the evaluation of a single expression.
+	celCostLimit = 1000000
+)
+
+// ErrSecretValidation is returned if the fetched data does not pass the validation rules.
+// The target keeps its last valid data until the data at the provider is fixed.
+var ErrSecretValidation = errors.New("secret data failed validation")
+
+// celEnv declares the variables and functions available to validation expressions.
+var celEnv = sync.OnceValues(func() (*cel.Env, error) {
+	return cel.NewEnv(
+		cel.Variable("key", cel.StringType),
+		cel.Variable("value", cel.StringType),
+		cel.Variable("data", cel.MapType(cel.StringType, cel.StringType)),
+		cel.Variable("now", cel.TimestampType),
+		ext.Strings(),
+		ext.Encoders(),
+		cel.Function("isPEM",
+			cel.Overload("isPEM_string", []*cel.Type{cel.StringType}, cel.BoolType,
+				cel.UnaryBinding(func(v ref.Val) ref.Val {
+					block, _ := pem.Decode([]byte(v.(types.String)))
+					return types.Bool(block != nil)
+				}))),
+		cel.Function("isJSON",
+			cel.Overload("isJSON_string", []*cel.Type{cel.StringType}, cel.BoolType,
+				cel.UnaryBinding(func(v ref.Val) ref.Val {
+					return types.Bool(json.Valid([]byte(v.(types.String))))
+				}))),
+		cel.Function("certNotAfter",
+			cel.Overload("certNotAfter_string", []*cel.Type{cel.StringType}, cel.TimestampType,
+				cel.UnaryBinding(func(v ref.Val) ref.Val {
+					notAfter, err := certNotAfter([]byte(v.(types.String)))
+					if err != nil {
+						return types.NewErr("certNotAfter: %v", err)
+					}
+					return types.Timestamp{Time: notAfter}
+				}))),
+	)
+})
+
+// certNotAfter returns the earliest expiry of the certificates of a PEM encoded value,
+// or the expiry of the value if it is a DER encoded certificate.
+func certNotAfter(data []byte) (time.Time, error) {
+	var notAfter time.Time
+	var found bool
+	for rest := data; ; {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		found = true
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return time.Time{}, err
+		}
+		notAfter = earliest(notAfter, cert.NotAfter)
+	}
+	if !notAfter.IsZero() {
+		return notAfter, nil
+	}
+	if !found {
+		if cert, err := x509.ParseCertificate(data); err == nil {
+			return cert.NotAfter, nil
+		}
+	}
+	return time.Time{}, errors.New("no certificate found")
+}
+
+// validateSecretData evaluates the validation rules of the target against the fetched data.
+// Values are never part of the returned error, as it ends up in events and the status.
+func validateSecretData(externalSecret *esv1.ExternalSecret, data map[string][]byte) error {
+	validation := externalSecret.Spec.Target.Validation
+	if validation == nil {
+		return nil
+	}
+
+	stringData := make(map[string]string, len(data))
+	for k, v := range data {
+		stringData[k] = string(v)
+	}
+	now := time.Now()
+
+	var errs error
+	for i, rule := range validation.Rules {
+		keys := []string{rule.Key}
+		if rule.Key == "" && rule.Regex != "" {
+			keys = make([]string, 0, len(data))
+			for k := range data {
+				keys = append(keys, k)
+			}
+		}
+		if rule.Key != "" {
+			if _, ok := data[rule.Key]; !ok {
+				errs = errors.Join(errs, fmt.Errorf(errValidationKeyMissing, i, rule.Key))
+				continue
+			}
+		}
+
+		var check func(key string) (bool, error)
+		switch {
+		case rule.Regex != "":
+			re, err := regexp.Compile(rule.Regex)
+			if err != nil {
+				errs = errors.Join(errs, fmt.Errorf(errValidationRule, i, err))
+				continue
+			}
+			check = func(key string) (bool, error) {
+				return re.Match(data[key]), nil
+			}
+		case rule.Expression != "":
+			prg, err := compileExpression(rule.Expression)
+			if err != nil {
+				errs = errors.Join(errs, fmt.Errorf(errValidationRule, i, err))
+				continue
+			}
+			check = func(key string) (bool, error) {
+				out, _, err := prg.Eval(map[string]any{
+					"key":   key,
+					"value": stringData[key],
+					"data":  stringData,
+					"now":   now,
+				})
+				if err != nil {
+					return false, err
+				}
+				return out == types.True, nil
+			}
+		default:
+			continue
+		}
+
+		for _, key := range keys {
+			ok, err := check(key)
+			if err == nil && !ok {
+				err = errors.New(ruleMessage(rule))
+			}
+			if err == nil {
+				continue
+			}
+			if key == "" {
+				errs = errors.Join(errs, fmt.Errorf(errValidationRule, i, err))
+			} else {
+				errs = errors.Join(errs, fmt.Errorf(errValidationRuleKey, i, key, err))
+			}
+		}
+	}
+
+	if errs != nil {
+		return fmt.Errorf("%w: %w", ErrSecretValidation, errs)
+	}
+	return nil
+}
+
+func compileExpression(expression string) (cel.Program, error) {
+	env, err := celEnv()
+	if err != nil {
+		return nil, err
+	}
+	ast, iss := env.Compile(expression)
+	if iss.Err() != nil {
+		return nil, iss.Err()
+	}
+	if !ast.OutputType().IsExactType(cel.BoolType) {
+		return nil, fmt.Errorf("expression must evaluate to a bool, got %s", ast.OutputType())
+	}
+	return env.Program(ast, cel.CostLimit(celCostLimit))
+}
+
+func ruleMessage(rule esv1.ExternalSecretValidationRule) string {
+	switch {
+	case rule.Message != "":
+		return rule.Message
+	case rule.Regex != "":
+		return fmt.Sprintf("value does not match %q", rule.Regex)
+	default:
+		return fmt.Sprintf("expression %q evaluated to false", rule.Expression)
+	}
+}
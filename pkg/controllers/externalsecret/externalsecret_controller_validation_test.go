@@ -0,0 +1,179 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalsecret
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+)
+
+// newTestCertificate returns a PEM encoded self-signed certificate which expires at notAfter.
+func newTestCertificate(t *testing.T, notAfter time.Time) []byte {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("could not generate key: %v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "test"},
+		NotBefore:    notAfter.Add(-24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("could not create certificate: %v", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func TestCertNotAfter(t *testing.T) {
+	first := time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)
+	second := first.Add(24 * time.Hour)
+	firstPEM, secondPEM := newTestCertificate(t, first), newTestCertificate(t, second)
+	secondDER, _ := pem.Decode(secondPEM)
+	chain := append(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: []byte("key")}), secondPEM...)
+	chain = append(chain, firstPEM...)
+
+	tests := []struct {
+		name    string
+		value   []byte
+		want    time.Time
+		wantErr bool
+	}{
+		{name: "PEM chain expires with its earliest certificate", value: chain, want: first},
+		{name: "DER certificate", value: secondDER.Bytes, want: second},
+		{name: "PEM without certificates", value: pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: []byte("key")}), wantErr: true},
+		{name: "not a certificate", value: []byte("password"), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := certNotAfter(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("certNotAfter() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("certNotAfter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateSecretData(t *testing.T) {
+	valid := newTestCertificate(t, time.Now().Add(30*24*time.Hour))
+	expiring := newTestCertificate(t, time.Now().Add(24*time.Hour))
+	notExpiring := "certNotAfter(value) - now > duration('168h')"
+
+	tests := []struct {
+		name        string
+		rules       []esv1.ExternalSecretValidationRule
+		data        map[string][]byte
+		expectedErr string
+	}{
+		{
+			name:  "no rules",
+			data:  map[string][]byte{"foo": []byte("bar")},
+			rules: nil,
+		},
+		{
+			name: "valid certificate",
+			rules: []esv1.ExternalSecretValidationRule{
+				{Key: "tls.crt", Expression: "isPEM(value)"},
+				{Key: "tls.crt", Expression: notExpiring},
+			},
+			data: map[string][]byte{"tls.crt": valid},
+		},
+		{
+			name: "expiring certificate",
+			rules: []esv1.ExternalSecretValidationRule{
+				{Key: "tls.crt", Expression: notExpiring, Message: "certificate expires within 7 days"},
+			},
+			data:        map[string][]byte{"tls.crt": expiring},
+			expectedErr: `rule 0, key "tls.crt": certificate expires within 7 days`,
+		},
+		{
+			name: "not a certificate",
+			rules: []esv1.ExternalSecretValidationRule{
+				{Key: "tls.crt", Expression: notExpiring},
+			},
+			data:        map[string][]byte{"tls.crt": []byte("secret-value")},
+			expectedErr: "no certificate found",
+		},
+		{
+			name: "regex on all keys",
+			rules: []esv1.ExternalSecretValidationRule{
+				{Regex: "^[a-z]+$"},
+			},
+			data:        map[string][]byte{"foo": []byte("bar"), "baz": []byte("secret-value")},
+			expectedErr: `rule 0, key "baz": value does not match "^[a-z]+$"`,
+		},
+		{
+			name: "expression on all data",
+			rules: []esv1.ExternalSecretValidationRule{
+				{Expression: "'username' in data && 'password' in data"},
+			},
+			data:        map[string][]byte{"username": []byte("foo")},
+			expectedErr: "rule 0: expression",
+		},
+		{
+			name: "missing key",
+			rules: []esv1.ExternalSecretValidationRule{
+				{Key: "config", Expression: "isJSON(value)"},
+			},
+			data:        map[string][]byte{"foo": []byte("bar")},
+			expectedErr: `rule 0: key "config" does not exist`,
+		},
+		{
+			name: "expression not returning a bool",
+			rules: []esv1.ExternalSecretValidationRule{
+				{Key: "foo", Expression: "value"},
+			},
+			data:        map[string][]byte{"foo": []byte("bar")},
+			expectedErr: "expression must evaluate to a bool",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := newManifestTestExternalSecret(nil)
+			if tt.rules != nil {
+				es.Spec.Target.Validation = &esv1.ExternalSecretValidation{Rules: tt.rules}
+			}
+			err := validateSecretData(es, tt.data)
+			if tt.expectedErr == "" {
+				if err != nil {
+					t.Fatalf("validateSecretData() returned an unexpected error: %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrSecretValidation) || !strings.Contains(err.Error(), tt.expectedErr) {
+				t.Fatalf("unexpected error: got %v, want %q", err, tt.expectedErr)
+			}
+			if strings.Contains(err.Error(), "secret-value") {
+				t.Errorf("error must not contain values: %v", err)
+			}
+		})
+	}
+}
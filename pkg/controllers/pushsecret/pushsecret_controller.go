@@ -15,6 +15,7 @@ limitations under the License.
 package pushsecret
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -70,6 +71,17 @@ type Reconciler struct {
 	RestConfig      *rest.Config
 	RequeueInterval time.Duration
 	ControllerClass string
+	// ClientPool reuses provider clients across reconciles, nil if disabled.
+	ClientPool *secretstore.ClientPool
+	// AllowResourceSelector enables reading fields of other resources with spec.selector.resource.
+	AllowResourceSelector bool
+	// HashKey keys the hashes of secret values written to the status, see utils.KeyedHash.
+	HashKey []byte
+}
+
+// valueHash returns the hash of a secret value which is written to the status of the PushSecret.
+func (r *Reconciler) valueHash(ps *esapi.PushSecret, value any) string {
+	return utils.KeyedHash(r.HashKey, string(ps.UID), value)
 }
 
 func (r *Reconciler) SetupWithManager(mgr ctrl.Manager, opts controller.Options) error {
@@ -91,7 +103,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	defer func() { pushSecretReconcileDuration.With(resourceLabels).Set(float64(time.Since(start))) }()
 
 	var ps esapi.PushSecret
-	mgr := secretstore.NewManager(r.Client, r.ControllerClass, false)
+	mgr := secretstore.NewManager(r.Client, r.ControllerClass, false).WithClientPool(r.ClientPool)
 	defer func() {
 		_ = mgr.Close(ctx)
 	}()
@@ -187,14 +199,31 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		return ctrl.Result{}, nil
 	}
 
+	// in dry-run mode the changes to the providers are computed, but never applied
+	if ps.Spec.DryRun {
+		plan, err := r.planPushSecret(ctx, &ps, secrets, secretStores, mgr)
+		if err != nil {
+			r.markAsFailed(fmt.Sprintf(errDryRun, err), &ps, nil)
+			return ctrl.Result{}, err
+		}
+		r.markAsDryRun(&ps, plan, start)
+		return ctrl.Result{RequeueAfter: refreshInt}, nil
+	}
+	ps.Status.DryRun = nil
+
 	allSyncedSecrets := make(esapi.SyncedPushSecretsMap)
+	conflicts := newConflictTracker(&ps, func(value any) string {
+		return r.valueHash(&ps, value)
+	})
 	for _, secret := range secrets {
 		if err := r.applyTemplate(ctx, &ps, &secret); err != nil {
 			return ctrl.Result{}, err
 		}
 
-		syncedSecrets, err := r.PushSecretToProviders(ctx, secretStores, ps, &secret, mgr)
+		syncedSecrets, err := r.PushSecretToProviders(ctx, secretStores, ps, &secret, mgr, conflicts)
 		if err != nil {
+			// keep the hashes of the values that were written before the sync failed
+			ps.Status.SyncedPushSecretHashes = conflicts.mergedHashes()
 			if errors.Is(err, locks.ErrConflict) {
 				log.Info("retry to acquire lock to update the secret later", "error", err)
 				return ctrl.Result{Requeue: true}, nil
@@ -218,9 +247,21 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		default:
 		}
 
+		if err := r.adoptRemoteValues(ctx, &secret, conflicts); err != nil {
+			ps.Status.SyncedPushSecretHashes = conflicts.mergedHashes()
+			r.markAsFailed(fmt.Sprintf(errAdoptRemoteValues, err), &ps, nil)
+			return ctrl.Result{}, err
+		}
+
 		allSyncedSecrets = mergeSecretState(allSyncedSecrets, syncedSecrets)
 	}
 
+	ps.Status.SyncedPushSecretHashes = conflicts.hashes
+	if len(conflicts.conflicts) > 0 {
+		r.markAsConflicted(&ps, allSyncedSecrets, conflicts.conflicts, start)
+		return ctrl.Result{RequeueAfter: refreshInt}, nil
+	}
+	clearConflict(&ps)
 	r.markAsDone(&ps, allSyncedSecrets, start)
 
 	return ctrl.Result{RequeueAfter: refreshInt}, nil
@@ -329,10 +370,10 @@ func (r *Reconciler) DeleteSecretFromStore(ctx context.Context, client esv1.Secr
 	return client.DeleteSecret(ctx, data.Match.RemoteRef)
 }
 
-func (r *Reconciler) PushSecretToProviders(ctx context.Context, stores map[esapi.PushSecretStoreRef]esv1.GenericStore, ps esapi.PushSecret, secret *v1.Secret, mgr *secretstore.Manager) (esapi.SyncedPushSecretsMap, error) {
+func (r *Reconciler) PushSecretToProviders(ctx context.Context, stores map[esapi.PushSecretStoreRef]esv1.GenericStore, ps esapi.PushSecret, secret *v1.Secret, mgr *secretstore.Manager, conflicts *conflictTracker) (esapi.SyncedPushSecretsMap, error) {
 	out := make(esapi.SyncedPushSecretsMap)
 	for ref, store := range stores {
-		out, err := r.handlePushSecretDataForStore(ctx, ps, secret, out, mgr, store.GetName(), ref.Kind)
+		out, err := r.handlePushSecretDataForStore(ctx, ps, secret, out, mgr, store, ref.Kind, conflicts)
 		if err != nil {
 			return out, err
 		}
@@ -340,7 +381,8 @@ func (r *Reconciler) PushSecretToProviders(ctx context.Context, stores map[esapi
 	return out, nil
 }
 
-func (r *Reconciler) handlePushSecretDataForStore(ctx context.Context, ps esapi.PushSecret, secret *v1.Secret, out esapi.SyncedPushSecretsMap, mgr *secretstore.Manager, storeName, refKind string) (esapi.SyncedPushSecretsMap, error) {
+func (r *Reconciler) handlePushSecretDataForStore(ctx context.Context, ps esapi.PushSecret, secret *v1.Secret, out esapi.SyncedPushSecretsMap, mgr *secretstore.Manager, store esv1.GenericStore, refKind string, conflicts *conflictTracker) (esapi.SyncedPushSecretsMap, error) {
+	storeName := store.GetName()
 	storeKey := fmt.Sprintf("%v/%v", refKind, storeName)
 	out[storeKey] = make(map[string]esapi.PushSecretData)
 	storeRef := esv1.SecretStoreRef{
@@ -352,7 +394,11 @@ func (r *Reconciler) handlePushSecretDataForStore(ctx context.Context, ps esapi.
 	if err != nil {
 		return out, fmt.Errorf("could not get secrets client for store %v: %w", storeName, err)
 	}
-	for _, data := range ps.Spec.Data {
+	pushData, bundles, err := pushSecretData(&ps, secret)
+	if err != nil {
+		return out, err
+	}
+	for _, data := range pushData {
 		secretData, err := utils.ReverseKeys(data.ConversionStrategy, originalSecretData)
 		if err != nil {
 			return nil, fmt.Errorf(errConvert, err)
@@ -362,12 +408,39 @@ func (r *Reconciler) handlePushSecretDataForStore(ctx context.Context, ps esapi.
 		if !secretKeyExists(key, secret) {
 			return out, fmt.Errorf("secret key %v does not exist", key)
 		}
+		// only single keys are checked, whole secrets may share the remote secret with other keys
+		if key != "" && conflicts.enabled(&ps) {
+			remote, diverged, err := conflicts.check(ctx, secretClient, storeKey, data)
+			if err != nil {
+				return out, fmt.Errorf(errCheckConflict, err)
+			} else if diverged {
+				var values map[string][]byte
+				if source, ok := sourceKey(data.ConversionStrategy, originalSecretData, key); ok {
+					values = map[string][]byte{source: remote}
+				}
+				conflicts.diverged(storeKey, data, remote, values)
+				out[storeKey][statusRef(data)] = data
+				continue
+			}
+		}
 		switch ps.Spec.UpdatePolicy {
 		case esapi.PushSecretUpdatePolicyIfNotExists:
 			exists, err := secretClient.SecretExists(ctx, data.Match.RemoteRef)
 			if err != nil {
 				return out, fmt.Errorf("could not verify if secret exists in store: %w", err)
 			} else if exists {
+				conflicts.keep(storeKey, data)
+				out[storeKey][statusRef(data)] = data
+				continue
+			}
+		case esapi.PushSecretUpdatePolicyIfChanged:
+			changed, err := remoteValueChanged(ctx, secretClient, secret, data)
+			if err != nil {
+				return out, fmt.Errorf("could not verify if secret changed in store: %w", err)
+			} else if !changed {
+				if key != "" {
+					conflicts.record(storeKey, data, secret.Data[key])
+				}
 				out[storeKey][statusRef(data)] = data
 				continue
 			}
@@ -377,8 +450,44 @@ func (r *Reconciler) handlePushSecretDataForStore(ctx context.Context, ps esapi.
 		if err := secretClient.PushSecret(ctx, secret, data); err != nil {
 			return out, fmt.Errorf(errSetSecretFailed, key, storeName, err)
 		}
+		if key != "" {
+			conflicts.record(storeKey, data, secret.Data[key])
+		}
 		out[storeKey][statusRef(data)] = data
 	}
+	for _, bundle := range bundles {
+		if ps.Spec.UpdatePolicy == esapi.PushSecretUpdatePolicyIfNotExists {
+			exists, err := secretClient.SecretExists(ctx, bundle.data.Match.RemoteRef)
+			if err != nil {
+				return out, fmt.Errorf("could not verify if secret exists in store: %w", err)
+			} else if exists {
+				conflicts.keep(storeKey, bundle.data)
+				out[storeKey][statusRef(bundle.data)] = bundle.data
+				continue
+			}
+		}
+		// merged documents are shared with other writers, so only replaced documents are checked
+		if bundle.mergePolicy != esapi.PushSecretBundleMergePolicyMerge && conflicts.enabled(&ps) {
+			remote, diverged, err := conflicts.check(ctx, secretClient, storeKey, bundle.data)
+			if err != nil {
+				return out, fmt.Errorf(errCheckConflict, err)
+			} else if diverged {
+				conflicts.diverged(storeKey, bundle.data, remote, bundle.adopt(remote, originalSecretData))
+				out[storeKey][statusRef(bundle.data)] = bundle.data
+				continue
+			}
+		}
+		ifChanged := ps.Spec.UpdatePolicy == esapi.PushSecretUpdatePolicyIfChanged
+		doc, err := pushBundle(ctx, secretClient, store, secret, bundle, ifChanged)
+		if err != nil {
+			if errors.Is(err, locks.ErrConflict) {
+				return out, err
+			}
+			return out, fmt.Errorf(errSetSecretFailed, statusRef(bundle.data), storeName, err)
+		}
+		conflicts.record(storeKey, bundle.data, doc)
+		out[storeKey][statusRef(bundle.data)] = bundle.data
+	}
 	return out, nil
 }
 
@@ -387,13 +496,49 @@ func secretKeyExists(key string, secret *v1.Secret) bool {
 	return key == "" || ok
 }
 
+// remoteValueChanged reports whether the value that would be pushed differs from the value in the provider.
+// A secret that does not exist, or whose value cannot be read back, counts as changed.
+func remoteValueChanged(ctx context.Context, secretClient esv1.SecretsClient, secret *v1.Secret, data esapi.PushSecretData) (bool, error) {
+	exists, err := secretClient.SecretExists(ctx, data.Match.RemoteRef)
+	if err != nil {
+		return false, err
+	}
+	if !exists {
+		return true, nil
+	}
+	ref := esv1.ExternalSecretDataRemoteRef{
+		Key:      data.GetRemoteKey(),
+		Property: data.GetProperty(),
+	}
+	if data.GetSecretKey() != "" {
+		remote, err := secretClient.GetSecret(ctx, ref)
+		if err != nil {
+			return true, nil
+		}
+		return !bytes.Equal(remote, secret.Data[data.GetSecretKey()]), nil
+	}
+	// the whole secret is pushed: providers either merge its keys into the remote secret
+	// or marshal them into the property, both of which read back as a map
+	remote, err := secretClient.GetSecretMap(ctx, ref)
+	if err != nil {
+		return true, nil
+	}
+	for k, v := range secret.Data {
+		if !bytes.Equal(remote[k], v) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 const defaultGeneratorStateKey = "__pushsecret"
 
 func (r *Reconciler) resolveSecrets(ctx context.Context, ps *esapi.PushSecret) ([]v1.Secret, error) {
 	var err error
 	generatorState := statemanager.New(ctx, r.Client, r.Scheme, ps.Namespace, ps)
 	defer func() {
-		if err != nil {
+		// in dry-run mode, nothing must be persisted, so the generator state is always rolled back
+		if err != nil || ps.Spec.DryRun {
 			if err := generatorState.Rollback(); err != nil {
 				r.Log.Error(err, "error rolling back generator state")
 			}
@@ -435,6 +580,15 @@ func (r *Reconciler) resolveSecrets(ctx context.Context, ps *esapi.PushSecret) (
 		}
 
 		return secretList.Items, err
+	case ps.Spec.Selector.ConfigMap != nil:
+		return r.resolveSecretsFromConfigMaps(ctx, ps.Namespace, ps.Spec.Selector.ConfigMap)
+	case ps.Spec.Selector.Resource != nil:
+		secret, err := r.resolveSecretFromResource(ctx, ps.Namespace, ps.Spec.Selector.Resource)
+		if err != nil {
+			return nil, fmt.Errorf("could not resolve secret from %s %v: %w", ps.Spec.Selector.Resource.Kind, ps.Spec.Selector.Resource.Name, err)
+		}
+
+		return []v1.Secret{*secret}, nil
 	}
 
 	return nil, errors.New("no secret selector provided")
@@ -0,0 +1,283 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pushsecret
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	v1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/yaml"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	esapi "github.com/external-secrets/external-secrets/apis/externalsecrets/v1alpha1"
+	"github.com/external-secrets/external-secrets/pkg/provider/util/locks"
+	"github.com/external-secrets/external-secrets/pkg/utils"
+)
+
+// bundleSecretKey is the key the document is handed to the provider with.
+const bundleSecretKey = "bundle"
+
+// bundleLockPrefix keeps the locks of bundles apart from the locks the providers take while writing.
+const bundleLockPrefix = "bundle/"
+
+// secretBundle is a document built from a set of keys of the Secret.
+type secretBundle struct {
+	// data is the entry recorded in the synced status.
+	data        esapi.PushSecretData
+	format      esapi.PushSecretBundleFormat
+	mergePolicy esapi.PushSecretBundleMergePolicy
+	values      map[string]any
+	// keys maps the properties to the secret keys they are bundled from.
+	keys map[string]string
+}
+
+// newSecretBundle collects the keys selected by a dataFrom entry, using the rewritten keys as properties.
+func newSecretBundle(from esapi.PushSecretDataFrom, secret *v1.Secret) (*secretBundle, error) {
+	entries, err := expandDataFrom(from, secret)
+	if err != nil {
+		return nil, err
+	}
+	secretData, err := utils.ReverseKeys(from.ConversionStrategy, secret.Data)
+	if err != nil {
+		return nil, fmt.Errorf(errConvert, err)
+	}
+	values := make(map[string]any, len(entries))
+	keys := make(map[string]string, len(entries))
+	for _, e := range entries {
+		if _, ok := values[e.GetRemoteKey()]; ok {
+			return nil, fmt.Errorf("property %q is bundled from more than one secret key", e.GetRemoteKey())
+		}
+		value := secretData[e.GetSecretKey()]
+		if !utf8.Valid(value) {
+			// documents hold strings, converting the value would replace its invalid bytes
+			return nil, fmt.Errorf("value of secret key %q is not valid UTF-8 and cannot be bundled, push it as an individual key instead", e.GetSecretKey())
+		}
+		values[e.GetRemoteKey()] = string(value)
+		keys[e.GetRemoteKey()] = e.GetSecretKey()
+	}
+	return &secretBundle{
+		data: esapi.PushSecretData{
+			Match: esapi.PushSecretMatch{
+				RemoteRef: from.Bundle.RemoteRef,
+			},
+			Metadata:           from.Metadata,
+			ConversionStrategy: from.ConversionStrategy,
+		},
+		format:      from.Bundle.Format,
+		mergePolicy: from.Bundle.MergePolicy,
+		values:      values,
+		keys:        keys,
+	}, nil
+}
+
+// document encodes the bundle. With the Merge policy the properties of the existing document
+// that are not part of the bundle are kept.
+func (b *secretBundle) document(existing []byte) ([]byte, error) {
+	doc := make(map[string]any, len(b.values))
+	if b.mergePolicy == esapi.PushSecretBundleMergePolicyMerge && len(existing) > 0 {
+		var err error
+		doc, err = decodeBundle(b.format, existing)
+		if err != nil {
+			return nil, fmt.Errorf("could not decode remote document: %w", err)
+		}
+	}
+	maps.Copy(doc, b.values)
+	return encodeBundle(b.format, doc)
+}
+
+// render returns the document to push and whether it differs from the remote document.
+// The remote document is read for the Merge policy and, with ifChanged, to compare it.
+func (b *secretBundle) render(ctx context.Context, secretClient esv1.SecretsClient, ifChanged bool) ([]byte, bool, error) {
+	merge := b.mergePolicy == esapi.PushSecretBundleMergePolicyMerge
+	var existing []byte
+	if merge || ifChanged {
+		var err error
+		existing, err = secretClient.GetSecret(ctx, esv1.ExternalSecretDataRemoteRef{
+			Key:      b.data.GetRemoteKey(),
+			Property: b.data.GetProperty(),
+		})
+		if err != nil && !errors.Is(err, esv1.NoSecretErr) {
+			if merge {
+				return nil, false, fmt.Errorf("could not read remote document: %w", err)
+			}
+			// a document that cannot be read back counts as changed
+			existing = nil
+		}
+	}
+	doc, err := b.document(existing)
+	if err != nil {
+		return nil, false, err
+	}
+	return doc, !ifChanged || existing == nil || !bytes.Equal(existing, doc), nil
+}
+
+// adopt maps the properties of a remote document back to the keys of the source Secret.
+// It returns nil if the document cannot be decoded or a key cannot be mapped back.
+func (b *secretBundle) adopt(remote []byte, original map[string][]byte) map[string][]byte {
+	doc, err := decodeBundle(b.format, remote)
+	if err != nil {
+		return nil
+	}
+	values := make(map[string][]byte, len(b.keys))
+	for property, key := range b.keys {
+		value, ok := doc[property]
+		if !ok {
+			continue
+		}
+		source, ok := sourceKey(b.data.ConversionStrategy, original, key)
+		if !ok {
+			return nil
+		}
+		str, ok := value.(string)
+		if !ok {
+			raw, err := json.Marshal(value)
+			if err != nil {
+				return nil
+			}
+			str = string(raw)
+		}
+		values[source] = []byte(str)
+	}
+	return values
+}
+
+// lockBundle locks the remote document of a bundle. Stores of the same provider in the same namespace share the lock,
+// as they may point at the same backend, and ClusterSecretStores share it across namespaces.
+// NOTE: the lock is held in memory, it only serializes the writes of a single controller process.
+func lockBundle(store esv1.GenericStore, b *secretBundle) (func(), error) {
+	providerName, err := esv1.GetProviderName(store)
+	if err != nil {
+		return nil, err
+	}
+	return locks.TryLock(bundleLockPrefix+providerName, fmt.Sprintf("%s/%s", store.GetNamespace(), b.data.GetRemoteKey()))
+}
+
+// pushBundle writes the bundle to the provider, unless ifChanged is set and the remote document is up to date,
+// and returns the document the provider holds afterwards.
+// The remote document is locked while it is read, merged and written,
+// so bundles sharing a remote document do not overwrite each other's properties.
+func pushBundle(ctx context.Context, secretClient esv1.SecretsClient, store esv1.GenericStore, secret *v1.Secret, b *secretBundle, ifChanged bool) ([]byte, error) {
+	unlock, err := lockBundle(store, b)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	doc, changed, err := b.render(ctx, secretClient, ifChanged)
+	if err != nil || !changed {
+		return doc, err
+	}
+	bundled := secret.DeepCopy()
+	bundled.Data = map[string][]byte{bundleSecretKey: doc}
+	data := b.data
+	data.Match.SecretKey = bundleSecretKey
+	data.ConversionStrategy = esapi.PushSecretConversionNone
+	return doc, secretClient.PushSecret(ctx, bundled, data)
+}
+
+func encodeBundle(format esapi.PushSecretBundleFormat, doc map[string]any) ([]byte, error) {
+	switch format {
+	case esapi.PushSecretBundleFormatYAML:
+		return yaml.Marshal(doc)
+	case esapi.PushSecretBundleFormatDotenv:
+		return encodeDotenv(doc)
+	default:
+		var buf bytes.Buffer
+		enc := json.NewEncoder(&buf)
+		enc.SetEscapeHTML(false)
+		if err := enc.Encode(doc); err != nil {
+			return nil, err
+		}
+		return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+	}
+}
+
+func decodeBundle(format esapi.PushSecretBundleFormat, in []byte) (map[string]any, error) {
+	doc := make(map[string]any)
+	var err error
+	switch format {
+	case esapi.PushSecretBundleFormatYAML:
+		err = yaml.Unmarshal(in, &doc)
+	case esapi.PushSecretBundleFormatDotenv:
+		doc, err = decodeDotenv(in)
+	default:
+		err = json.Unmarshal(in, &doc)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if doc == nil {
+		doc = make(map[string]any)
+	}
+	return doc, nil
+}
+
+// encodeDotenv writes one KEY="value" line per property, sorted by key.
+func encodeDotenv(doc map[string]any) ([]byte, error) {
+	var buf bytes.Buffer
+	for _, k := range slices.Sorted(maps.Keys(doc)) {
+		value, ok := doc[k].(string)
+		if !ok {
+			raw, err := json.Marshal(doc[k])
+			if err != nil {
+				return nil, err
+			}
+			value = string(raw)
+		}
+		fmt.Fprintf(&buf, "%s=%s\n", k, strconv.Quote(value))
+	}
+	return buf.Bytes(), nil
+}
+
+// decodeDotenv reads KEY=value lines. Blank lines, comments and an export prefix are ignored,
+// double quoted values are unescaped and single quoted values are taken literally.
+func decodeDotenv(in []byte) (map[string]any, error) {
+	doc := make(map[string]any)
+	scanner := bufio.NewScanner(bytes.NewReader(in))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid line %q", line)
+		}
+		value = strings.TrimSpace(value)
+		switch {
+		case len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"':
+			unquoted, err := strconv.Unquote(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value of %q: %w", key, err)
+			}
+			value = unquoted
+		case len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'':
+			value = value[1 : len(value)-1]
+		}
+		doc[strings.TrimSpace(key)] = value
+	}
+	return doc, scanner.Err()
+}
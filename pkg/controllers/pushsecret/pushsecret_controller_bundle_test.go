@@ -0,0 +1,227 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pushsecret
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	esapi "github.com/external-secrets/external-secrets/apis/externalsecrets/v1alpha1"
+	"github.com/external-secrets/external-secrets/pkg/provider/util/locks"
+)
+
+func TestSecretBundleDocument(t *testing.T) {
+	tests := []struct {
+		name        string
+		format      esapi.PushSecretBundleFormat
+		mergePolicy esapi.PushSecretBundleMergePolicy
+		existing    string
+		want        string
+		wantErr     bool
+	}{
+		{
+			name:   "json",
+			format: esapi.PushSecretBundleFormatJSON,
+			want:   `{"password":"p&ss\"word","user":"admin"}`,
+		},
+		{
+			name:     "json replaces the existing document",
+			format:   esapi.PushSecretBundleFormatJSON,
+			existing: `{"other":"value"}`,
+			want:     `{"password":"p&ss\"word","user":"admin"}`,
+		},
+		{
+			name:        "json merges into the existing document",
+			format:      esapi.PushSecretBundleFormatJSON,
+			mergePolicy: esapi.PushSecretBundleMergePolicyMerge,
+			existing:    `{"other":{"nested":true},"user":"root"}`,
+			want:        `{"other":{"nested":true},"password":"p&ss\"word","user":"admin"}`,
+		},
+		{
+			name:        "yaml merges into the existing document",
+			format:      esapi.PushSecretBundleFormatYAML,
+			mergePolicy: esapi.PushSecretBundleMergePolicyMerge,
+			existing:    "other: value\n",
+			want:        "other: value\npassword: p&ss\"word\nuser: admin\n",
+		},
+		{
+			name:        "dotenv merges into the existing document",
+			format:      esapi.PushSecretBundleFormatDotenv,
+			mergePolicy: esapi.PushSecretBundleMergePolicyMerge,
+			existing:    "# comment\nexport OTHER='a b'\nuser=root\n",
+			want:        "OTHER=\"a b\"\npassword=\"p&ss\\\"word\"\nuser=\"admin\"\n",
+		},
+		{
+			name:        "invalid existing document",
+			format:      esapi.PushSecretBundleFormatDotenv,
+			mergePolicy: esapi.PushSecretBundleMergePolicyMerge,
+			existing:    "not a dotenv line",
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := &secretBundle{
+				format:      tt.format,
+				mergePolicy: tt.mergePolicy,
+				values: map[string]any{
+					"user":     "admin",
+					"password": `p&ss"word`,
+				},
+			}
+			got, err := b.document([]byte(tt.existing))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("document() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if string(got) != tt.want {
+				t.Errorf("document() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewSecretBundle(t *testing.T) {
+	from := esapi.PushSecretDataFrom{
+		Bundle: &esapi.PushSecretBundle{
+			RemoteRef: esapi.PushSecretRemoteRef{RemoteKey: "remote"},
+		},
+	}
+	tests := []struct {
+		name    string
+		data    map[string][]byte
+		want    map[string]any
+		wantErr bool
+	}{
+		{
+			name: "text values",
+			data: map[string][]byte{"user": []byte("admin"), "password": []byte("pässword")},
+			want: map[string]any{"user": "admin", "password": "pässword"},
+		},
+		{
+			name:    "binary value",
+			data:    map[string][]byte{"user": []byte("admin"), "keystore": {0xfe, 0xed, 0xfe, 0xed}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := newSecretBundle(from, &v1.Secret{Data: tt.data})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("newSecretBundle() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tt.want, b.values); diff != "" {
+				t.Errorf("unexpected values (-want, +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDecodeDotenvRoundTrip(t *testing.T) {
+	doc := map[string]any{
+		"multiline": "line1\nline2",
+		"quoted":    `"value"`,
+	}
+	encoded, err := encodeDotenv(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := decodeDotenv(encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for k, v := range doc {
+		if decoded[k] != v {
+			t.Errorf("decoded[%q] = %q, want %q", k, decoded[k], v)
+		}
+	}
+}
+
+func TestLockBundle(t *testing.T) {
+	store := func(name, namespace string, provider *esv1.SecretStoreProvider) esv1.GenericStore {
+		return &esv1.SecretStore{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec:       esv1.SecretStoreSpec{Provider: provider},
+		}
+	}
+	aws := &esv1.SecretStoreProvider{AWS: &esv1.AWSProvider{}}
+	gcp := &esv1.SecretStoreProvider{GCPSM: &esv1.GCPSMProvider{}}
+	bundle := func(remoteKey, property string) *secretBundle {
+		return &secretBundle{data: esapi.PushSecretData{
+			Match: esapi.PushSecretMatch{RemoteRef: esapi.PushSecretRemoteRef{RemoteKey: remoteKey, Property: property}},
+		}}
+	}
+	tests := []struct {
+		name         string
+		first        esv1.GenericStore
+		second       esv1.GenericStore
+		firstBundle  *secretBundle
+		secondBundle *secretBundle
+		wantConflict bool
+	}{
+		{
+			name:         "same document of different stores of a namespace",
+			first:        store("primary", "default", aws),
+			second:       store("secondary", "default", aws),
+			firstBundle:  bundle("app", "db"),
+			secondBundle: bundle("app", "cache"),
+			wantConflict: true,
+		},
+		{
+			name:         "same store name in different namespaces",
+			first:        store("primary", "default", aws),
+			second:       store("primary", "other", aws),
+			firstBundle:  bundle("app", ""),
+			secondBundle: bundle("app", ""),
+		},
+		{
+			name:         "different providers",
+			first:        store("primary", "default", aws),
+			second:       store("primary", "default", gcp),
+			firstBundle:  bundle("app", ""),
+			secondBundle: bundle("app", ""),
+		},
+		{
+			name:         "different documents",
+			first:        store("primary", "default", aws),
+			second:       store("primary", "default", aws),
+			firstBundle:  bundle("app", ""),
+			secondBundle: bundle("other", ""),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			unlock, err := lockBundle(tt.first, tt.firstBundle)
+			if err != nil {
+				t.Fatalf("lockBundle() returned error: %v", err)
+			}
+			defer unlock()
+			unlockSecond, err := lockBundle(tt.second, tt.secondBundle)
+			if got := errors.Is(err, locks.ErrConflict); got != tt.wantConflict {
+				t.Fatalf("lockBundle() conflict = %v, want %v (err: %v)", got, tt.wantConflict, err)
+			}
+			if err == nil {
+				unlockSecond()
+			}
+		})
+	}
+}
@@ -0,0 +1,198 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pushsecret
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	esapi "github.com/external-secrets/external-secrets/apis/externalsecrets/v1alpha1"
+	"github.com/external-secrets/external-secrets/pkg/controllers/util"
+	"github.com/external-secrets/external-secrets/pkg/utils"
+)
+
+const (
+	errConflict          = "remote values were changed outside of the PushSecret: %s"
+	errCheckConflict     = "could not check secret for remote changes: %w"
+	errAdoptRemoteValues = "could not adopt remote values into the source secret: %v"
+)
+
+// conflictTracker records the hashes of the values written to the providers and
+// detects remote values that no longer match the hash recorded by the previous sync.
+type conflictTracker struct {
+	policy esapi.PushSecretConflictPolicy
+	// hash returns the keyed hash of a value, see Reconciler.valueHash.
+	hash func(value any) string
+	// adoptable is set if remote values can be copied back into the source Secret.
+	adoptable bool
+	previous  map[string]map[string]string
+	hashes    map[string]map[string]string
+	conflicts []string
+	// adopted maps keys of the source Secret to the remote values copied back into it.
+	adopted map[string][]byte
+}
+
+func newConflictTracker(ps *esapi.PushSecret, hash func(value any) string) *conflictTracker {
+	return &conflictTracker{
+		policy: ps.Spec.ConflictPolicy,
+		hash:   hash,
+		// only Secrets can be written back, and templated values no longer match their source
+		adoptable: ps.Spec.Selector.Secret != nil && ps.Spec.Template == nil,
+		previous:  ps.Status.SyncedPushSecretHashes,
+		hashes:    make(map[string]map[string]string),
+		adopted:   make(map[string][]byte),
+	}
+}
+
+// enabled reports whether remote values are checked before they are overwritten.
+func (t *conflictTracker) enabled(ps *esapi.PushSecret) bool {
+	if ps.Spec.UpdatePolicy == esapi.PushSecretUpdatePolicyIfNotExists {
+		// existing remote values are never overwritten
+		return false
+	}
+	return t.policy == esapi.PushSecretConflictPolicyStop || t.policy == esapi.PushSecretConflictPolicyAdopt
+}
+
+// check reads the remote value of a ref that was written by a previous sync and
+// reports whether it differs from what was written. Missing remote values are not conflicts.
+func (t *conflictTracker) check(ctx context.Context, secretClient esv1.SecretsClient, storeKey string, data esapi.PushSecretData) ([]byte, bool, error) {
+	hash, ok := t.previous[storeKey][statusRef(data)]
+	if !ok {
+		return nil, false, nil
+	}
+	exists, err := secretClient.SecretExists(ctx, data.Match.RemoteRef)
+	if err != nil || !exists {
+		return nil, false, err
+	}
+	remote, err := secretClient.GetSecret(ctx, esv1.ExternalSecretDataRemoteRef{
+		Key:      data.GetRemoteKey(),
+		Property: data.GetProperty(),
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	// hashes recorded before they were keyed are accepted until the value is written again
+	return remote, t.hash(remote) != hash && utils.ObjectHash(remote) != hash, nil
+}
+
+// diverged resolves a remote value that was changed outside of the PushSecret.
+// values maps the keys of the source Secret to the parts of the remote value they are pushed as;
+// it is nil if the remote value cannot be adopted.
+func (t *conflictTracker) diverged(storeKey string, data esapi.PushSecretData, remote []byte, values map[string][]byte) {
+	if t.policy == esapi.PushSecretConflictPolicyAdopt && t.adoptable && values != nil {
+		maps.Copy(t.adopted, values)
+		t.record(storeKey, data, remote)
+		return
+	}
+	t.conflicts = append(t.conflicts, fmt.Sprintf("%s in %s", statusRef(data), storeKey))
+	t.keep(storeKey, data)
+}
+
+// record stores the hash of the value written to the remote ref.
+func (t *conflictTracker) record(storeKey string, data esapi.PushSecretData, value []byte) {
+	if t.hashes[storeKey] == nil {
+		t.hashes[storeKey] = make(map[string]string)
+	}
+	t.hashes[storeKey][statusRef(data)] = t.hash(value)
+}
+
+// keep carries over the hash of a remote ref that was not written.
+func (t *conflictTracker) keep(storeKey string, data esapi.PushSecretData) {
+	hash, ok := t.previous[storeKey][statusRef(data)]
+	if !ok {
+		return
+	}
+	if t.hashes[storeKey] == nil {
+		t.hashes[storeKey] = make(map[string]string)
+	}
+	t.hashes[storeKey][statusRef(data)] = hash
+}
+
+// mergedHashes returns the recorded hashes, falling back to the previous ones for stores that
+// were not synced, so a failed sync does not lose track of what was written.
+func (t *conflictTracker) mergedHashes() map[string]map[string]string {
+	out := make(map[string]map[string]string, len(t.previous))
+	for storeKey, hashes := range t.previous {
+		out[storeKey] = maps.Clone(hashes)
+	}
+	for storeKey, hashes := range t.hashes {
+		if out[storeKey] == nil {
+			out[storeKey] = make(map[string]string)
+		}
+		maps.Copy(out[storeKey], hashes)
+	}
+	return out
+}
+
+// sourceKey returns the key of the source Secret that is pushed as key with the conversion strategy.
+func sourceKey(strategy esapi.PushSecretConversionStrategy, original map[string][]byte, key string) (string, bool) {
+	for k := range original {
+		converted, err := utils.ReverseKeys(strategy, map[string][]byte{k: nil})
+		if err != nil {
+			continue
+		}
+		if _, ok := converted[key]; ok {
+			return k, true
+		}
+	}
+	return "", false
+}
+
+// adoptRemoteValues writes the adopted remote values back into the source Secret.
+func (r *Reconciler) adoptRemoteValues(ctx context.Context, secret *v1.Secret, t *conflictTracker) error {
+	if len(t.adopted) == 0 {
+		return nil
+	}
+	source := &v1.Secret{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: secret.Name, Namespace: secret.Namespace}, source); err != nil {
+		return err
+	}
+	patch := client.MergeFrom(source.DeepCopy())
+	if source.Data == nil {
+		source.Data = make(map[string][]byte)
+	}
+	maps.Copy(source.Data, t.adopted)
+	clear(t.adopted)
+	return r.Client.Patch(ctx, source, patch)
+}
+
+// markAsConflicted records the sync like markAsDone, but sets the Conflict condition
+// for the remote values that were not overwritten.
+func (r *Reconciler) markAsConflicted(ps *esapi.PushSecret, secrets esapi.SyncedPushSecretsMap, conflicts []string, start time.Time) {
+	msg := fmt.Sprintf(errConflict, strings.Join(conflicts, ", "))
+	SetPushSecretCondition(ps, *NewPushSecretCondition(esapi.PushSecretConflict, v1.ConditionTrue, esapi.ReasonConflict, msg))
+	SetPushSecretCondition(ps, *NewPushSecretCondition(esapi.PushSecretReady, v1.ConditionFalse, esapi.ReasonConflict, msg))
+	r.setSecrets(ps, secrets)
+	ps.Status.RefreshTime = metav1.NewTime(start)
+	ps.Status.SyncedResourceVersion = util.GetResourceVersion(ps.ObjectMeta)
+	r.recorder.Event(ps, v1.EventTypeWarning, esapi.ReasonConflict, msg)
+}
+
+// clearConflict resets the Conflict condition once no remote values are in conflict.
+func clearConflict(ps *esapi.PushSecret) {
+	if GetPushSecretCondition(ps.Status.Conditions, esapi.PushSecretConflict) == nil {
+		return
+	}
+	SetPushSecretCondition(ps, *NewPushSecretCondition(esapi.PushSecretConflict, v1.ConditionFalse, esapi.ReasonSynced, ""))
+}
@@ -0,0 +1,199 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pushsecret
+
+import (
+	"context"
+	"testing"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	esapi "github.com/external-secrets/external-secrets/apis/externalsecrets/v1alpha1"
+	"github.com/external-secrets/external-secrets/pkg/provider/testing/fake"
+	"github.com/external-secrets/external-secrets/pkg/utils"
+)
+
+// conflictTestHash hashes values like Reconciler.valueHash with a fixed key.
+func conflictTestHash(value any) string {
+	return utils.KeyedHash([]byte("test-key"), "test-uid", value)
+}
+
+func TestConflictTracker(t *testing.T) {
+	const storeKey = "SecretStore/test"
+	data := esapi.PushSecretData{
+		Match: esapi.PushSecretMatch{
+			SecretKey: "key",
+			RemoteRef: esapi.PushSecretRemoteRef{RemoteKey: "remote"},
+		},
+	}
+	tests := []struct {
+		name         string
+		policy       esapi.PushSecretConflictPolicy
+		previous     []byte
+		remote       []byte
+		exists       bool
+		wantDiverged bool
+		wantConflict bool
+		wantAdopted  bool
+		legacyHash   bool
+		wantHash     []byte
+	}{
+		{
+			name:   "never written",
+			policy: esapi.PushSecretConflictPolicyStop,
+			remote: []byte("edited"),
+			exists: true,
+		},
+		{
+			name:     "remote deleted",
+			policy:   esapi.PushSecretConflictPolicyStop,
+			previous: []byte("pushed"),
+		},
+		{
+			name:     "remote unchanged",
+			policy:   esapi.PushSecretConflictPolicyStop,
+			previous: []byte("pushed"),
+			remote:   []byte("pushed"),
+			exists:   true,
+		},
+		{
+			name:       "remote unchanged since an unkeyed hash was recorded",
+			policy:     esapi.PushSecretConflictPolicyStop,
+			previous:   []byte("pushed"),
+			remote:     []byte("pushed"),
+			exists:     true,
+			legacyHash: true,
+		},
+		{
+			name:         "stop on remote edit",
+			policy:       esapi.PushSecretConflictPolicyStop,
+			previous:     []byte("pushed"),
+			remote:       []byte("edited"),
+			exists:       true,
+			wantDiverged: true,
+			wantConflict: true,
+			wantHash:     []byte("pushed"),
+		},
+		{
+			name:         "adopt remote edit",
+			policy:       esapi.PushSecretConflictPolicyAdopt,
+			previous:     []byte("pushed"),
+			remote:       []byte("edited"),
+			exists:       true,
+			wantDiverged: true,
+			wantAdopted:  true,
+			wantHash:     []byte("edited"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ps := &esapi.PushSecret{
+				Spec: esapi.PushSecretSpec{
+					ConflictPolicy: tt.policy,
+					Selector: esapi.PushSecretSelector{
+						Secret: &esapi.PushSecretSecret{Name: "source"},
+					},
+				},
+			}
+			if tt.previous != nil {
+				hash := conflictTestHash(tt.previous)
+				if tt.legacyHash {
+					hash = utils.ObjectHash(tt.previous)
+				}
+				ps.Status.SyncedPushSecretHashes = map[string]map[string]string{
+					storeKey: {statusRef(data): hash},
+				}
+			}
+			c := fake.New()
+			c.SecretExistsFn = func(context.Context, esv1.PushSecretRemoteRef) (bool, error) {
+				return tt.exists, nil
+			}
+			c.WithGetSecret(tt.remote, nil)
+
+			tracker := newConflictTracker(ps, conflictTestHash)
+			if !tracker.enabled(ps) {
+				t.Fatalf("enabled() = false, want true")
+			}
+			remote, diverged, err := tracker.check(context.Background(), c, storeKey, data)
+			if err != nil {
+				t.Fatalf("check() returned error: %v", err)
+			}
+			if diverged != tt.wantDiverged {
+				t.Fatalf("check() diverged = %v, want %v", diverged, tt.wantDiverged)
+			}
+			if !diverged {
+				return
+			}
+			tracker.diverged(storeKey, data, remote, map[string][]byte{"key": remote})
+			if got := len(tracker.conflicts) > 0; got != tt.wantConflict {
+				t.Errorf("conflict = %v, want %v", got, tt.wantConflict)
+			}
+			if got := len(tracker.adopted) > 0; got != tt.wantAdopted {
+				t.Errorf("adopted = %v, want %v", got, tt.wantAdopted)
+			}
+			if got := tracker.hashes[storeKey][statusRef(data)]; got != conflictTestHash(tt.wantHash) {
+				t.Errorf("hash = %v, want the hash of %q", got, tt.wantHash)
+			}
+		})
+	}
+}
+
+func TestConflictTrackerAdoptFallsBackToStop(t *testing.T) {
+	ps := &esapi.PushSecret{
+		Spec: esapi.PushSecretSpec{
+			ConflictPolicy: esapi.PushSecretConflictPolicyAdopt,
+			Selector: esapi.PushSecretSelector{
+				GeneratorRef: &esv1.GeneratorRef{Name: "generator"},
+			},
+		},
+	}
+	tracker := newConflictTracker(ps, conflictTestHash)
+	tracker.diverged("SecretStore/test", esapi.PushSecretData{}, []byte("edited"), map[string][]byte{"key": []byte("edited")})
+	if len(tracker.conflicts) != 1 || len(tracker.adopted) != 0 {
+		t.Errorf("generated values must not be adopted, got conflicts %v and adopted %v", tracker.conflicts, tracker.adopted)
+	}
+}
+
+func TestConflictTrackerDisabledForIfNotExists(t *testing.T) {
+	ps := &esapi.PushSecret{
+		Spec: esapi.PushSecretSpec{
+			ConflictPolicy: esapi.PushSecretConflictPolicyStop,
+			UpdatePolicy:   esapi.PushSecretUpdatePolicyIfNotExists,
+		},
+	}
+	if newConflictTracker(ps, conflictTestHash).enabled(ps) {
+		t.Errorf("enabled() = true, want false for UpdatePolicy=IfNotExists")
+	}
+}
+
+func TestSecretBundleAdopt(t *testing.T) {
+	b := &secretBundle{
+		format: esapi.PushSecretBundleFormatJSON,
+		keys: map[string]string{
+			"user":     "db-user",
+			"password": "db-password",
+		},
+	}
+	original := map[string][]byte{
+		"db-user":     []byte("admin"),
+		"db-password": []byte("old"),
+	}
+	got := b.adopt([]byte(`{"user":"admin","password":"new","other":"ignored"}`), original)
+	if string(got["db-user"]) != "admin" || string(got["db-password"]) != "new" || len(got) != 2 {
+		t.Errorf("adopt() = %v, want db-user=admin and db-password=new", got)
+	}
+	if got := b.adopt([]byte("not json"), original); got != nil {
+		t.Errorf("adopt() of an invalid document = %v, want nil", got)
+	}
+}
@@ -0,0 +1,139 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pushsecret
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+
+	v1 "k8s.io/api/core/v1"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	esapi "github.com/external-secrets/external-secrets/apis/externalsecrets/v1alpha1"
+	"github.com/external-secrets/external-secrets/pkg/utils"
+)
+
+const (
+	errDataFrom           = "could not expand dataFrom[%d]: %w"
+	errDuplicateRemoteRef = "remote ref %q is pushed from both %s and %s"
+)
+
+var errRewriteMerge = errors.New("merge rewrites are not supported")
+
+// pushSecretData returns the entries of spec.data followed by one entry per key selected by spec.dataFrom,
+// and the bundles of the dataFrom entries that push their keys as a single document.
+// The dataFrom entries are computed from the current keys of the secret, so keys that were removed
+// drop out of the synced status and are deleted from the providers with the Delete deletion policy.
+func pushSecretData(ps *esapi.PushSecret, secret *v1.Secret) ([]esapi.PushSecretData, []*secretBundle, error) {
+	if len(ps.Spec.DataFrom) == 0 {
+		return ps.Spec.Data, nil, nil
+	}
+	data := slices.Clone(ps.Spec.Data)
+	var bundles []*secretBundle
+	seen := make(map[string]string, len(data))
+	for _, d := range data {
+		seen[statusRef(d)] = fmt.Sprintf("secret key %q", d.GetSecretKey())
+	}
+	claim := func(ref, source string) error {
+		if other, ok := seen[ref]; ok {
+			return fmt.Errorf(errDuplicateRemoteRef, ref, other, source)
+		}
+		seen[ref] = source
+		return nil
+	}
+	for i, from := range ps.Spec.DataFrom {
+		if from.Bundle != nil {
+			bundle, err := newSecretBundle(from, secret)
+			if err != nil {
+				return nil, nil, fmt.Errorf(errDataFrom, i, err)
+			}
+			if err := claim(statusRef(bundle.data), fmt.Sprintf("bundle dataFrom[%d]", i)); err != nil {
+				return nil, nil, err
+			}
+			bundles = append(bundles, bundle)
+			continue
+		}
+		expanded, err := expandDataFrom(from, secret)
+		if err != nil {
+			return nil, nil, fmt.Errorf(errDataFrom, i, err)
+		}
+		for _, d := range expanded {
+			if err := claim(statusRef(d), fmt.Sprintf("secret key %q", d.GetSecretKey())); err != nil {
+				return nil, nil, err
+			}
+			data = append(data, d)
+		}
+	}
+	return data, bundles, nil
+}
+
+// expandDataFrom returns one entry per matching secret key, sorted by key.
+func expandDataFrom(from esapi.PushSecretDataFrom, secret *v1.Secret) ([]esapi.PushSecretData, error) {
+	secretData, err := utils.ReverseKeys(from.ConversionStrategy, secret.Data)
+	if err != nil {
+		return nil, fmt.Errorf(errConvert, err)
+	}
+	var re *regexp.Regexp
+	if from.Match != nil && from.Match.RegExp != "" {
+		re, err = regexp.Compile(from.Match.RegExp)
+		if err != nil {
+			return nil, fmt.Errorf("could not compile regexp: %w", err)
+		}
+	}
+	var out []esapi.PushSecretData
+	for _, key := range slices.Sorted(maps.Keys(secretData)) {
+		if re != nil && !re.MatchString(key) {
+			continue
+		}
+		remoteKey, err := rewriteKey(from.Rewrite, key)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, esapi.PushSecretData{
+			Match: esapi.PushSecretMatch{
+				SecretKey: key,
+				RemoteRef: esapi.PushSecretRemoteRef{
+					RemoteKey: remoteKey,
+				},
+			},
+			Metadata:           from.Metadata,
+			ConversionStrategy: from.ConversionStrategy,
+		})
+	}
+	return out, nil
+}
+
+// rewriteKey applies the rewrite operations to a single secret key.
+func rewriteKey(rewrite []esv1.ExternalSecretRewrite, key string) (string, error) {
+	for _, op := range rewrite {
+		if op.Merge != nil {
+			return "", errRewriteMerge
+		}
+	}
+	rewritten, err := utils.RewriteMap(rewrite, map[string][]byte{key: nil})
+	if err != nil {
+		return "", err
+	}
+	for remoteKey := range rewritten {
+		if remoteKey == "" {
+			return "", fmt.Errorf("secret key %q was rewritten to an empty remote key", key)
+		}
+		return remoteKey, nil
+	}
+	return "", fmt.Errorf("secret key %q was rewritten to no remote key", key)
+}
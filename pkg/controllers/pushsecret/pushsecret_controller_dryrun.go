@@ -0,0 +1,184 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pushsecret
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	esapi "github.com/external-secrets/external-secrets/apis/externalsecrets/v1alpha1"
+	"github.com/external-secrets/external-secrets/pkg/controllers/secretstore"
+	"github.com/external-secrets/external-secrets/pkg/controllers/util"
+	"github.com/external-secrets/external-secrets/pkg/utils"
+)
+
+const (
+	errDryRun      = "could not compute the changes of the dry-run: %v"
+	msgDryRun      = "PushSecret dry-run completed, no changes were applied to the providers"
+	eventDryRunFmt = "dry-run: write=%d, skip=%d, delete=%d"
+)
+
+// planPushSecret computes the remote keys that would be written to or deleted from the providers.
+// It runs the same template and conversion pipeline as a regular sync, but only reads from the providers.
+func (r *Reconciler) planPushSecret(ctx context.Context, ps *esapi.PushSecret, secrets []v1.Secret, stores map[esapi.PushSecretStoreRef]esv1.GenericStore, mgr *secretstore.Manager) (*esapi.PushSecretDryRunStatus, error) {
+	plan := &esapi.PushSecretDryRunStatus{
+		PlanTime: metav1.Now(),
+	}
+	planned := make(esapi.SyncedPushSecretsMap)
+	for _, secret := range secrets {
+		if err := r.applyTemplate(ctx, ps, &secret); err != nil {
+			return nil, err
+		}
+		for ref, store := range stores {
+			storeKey := fmt.Sprintf("%v/%v", ref.Kind, store.GetName())
+			if planned[storeKey] == nil {
+				planned[storeKey] = make(map[string]esapi.PushSecretData)
+			}
+			secretClient, err := mgr.Get(ctx, esv1.SecretStoreRef{Name: store.GetName(), Kind: ref.Kind}, ps.GetNamespace(), nil)
+			if err != nil {
+				return nil, fmt.Errorf("could not get secrets client for store %v: %w", store.GetName(), err)
+			}
+			pushData, bundles, err := pushSecretData(ps, &secret)
+			if err != nil {
+				return nil, err
+			}
+			for _, data := range pushData {
+				secretData, err := utils.ReverseKeys(data.ConversionStrategy, secret.Data)
+				if err != nil {
+					return nil, fmt.Errorf(errConvert, err)
+				}
+				key := data.GetSecretKey()
+				value, ok := secretData[key]
+				if key != "" && !ok {
+					return nil, fmt.Errorf("secret key %v does not exist", key)
+				}
+				hash := r.valueHash(ps, value)
+				if key == "" {
+					hash = r.valueHash(ps, secretData)
+				}
+				action := esapi.PushSecretDryRunActionWrite
+				switch ps.Spec.UpdatePolicy {
+				case esapi.PushSecretUpdatePolicyIfNotExists:
+					exists, err := secretClient.SecretExists(ctx, data.Match.RemoteRef)
+					if err != nil {
+						return nil, fmt.Errorf("could not verify if secret exists in store: %w", err)
+					}
+					if exists {
+						action = esapi.PushSecretDryRunActionSkip
+					}
+				case esapi.PushSecretUpdatePolicyIfChanged:
+					converted := secret.DeepCopy()
+					converted.Data = secretData
+					changed, err := remoteValueChanged(ctx, secretClient, converted, data)
+					if err != nil {
+						return nil, fmt.Errorf("could not verify if secret changed in store: %w", err)
+					}
+					if !changed {
+						action = esapi.PushSecretDryRunActionSkip
+					}
+				}
+				plan.Changes = append(plan.Changes, esapi.PushSecretDryRunChange{
+					Store:     storeKey,
+					RemoteKey: data.GetRemoteKey(),
+					Property:  data.GetProperty(),
+					Action:    action,
+					Hash:      hash,
+				})
+				planned[storeKey][statusRef(data)] = data
+			}
+			for _, bundle := range bundles {
+				doc, changed, err := bundle.render(ctx, secretClient, ps.Spec.UpdatePolicy == esapi.PushSecretUpdatePolicyIfChanged)
+				if err != nil {
+					return nil, err
+				}
+				action := esapi.PushSecretDryRunActionWrite
+				if !changed {
+					action = esapi.PushSecretDryRunActionSkip
+				}
+				if ps.Spec.UpdatePolicy == esapi.PushSecretUpdatePolicyIfNotExists {
+					exists, err := secretClient.SecretExists(ctx, bundle.data.Match.RemoteRef)
+					if err != nil {
+						return nil, fmt.Errorf("could not verify if secret exists in store: %w", err)
+					}
+					if exists {
+						action = esapi.PushSecretDryRunActionSkip
+					}
+				}
+				plan.Changes = append(plan.Changes, esapi.PushSecretDryRunChange{
+					Store:     storeKey,
+					RemoteKey: bundle.data.GetRemoteKey(),
+					Property:  bundle.data.GetProperty(),
+					Action:    action,
+					Hash:      r.valueHash(ps, doc),
+				})
+				planned[storeKey][statusRef(bundle.data)] = bundle.data
+			}
+		}
+	}
+
+	// remote keys which were pushed before, but are no longer part of the PushSecret would be deleted
+	if ps.Spec.DeletionPolicy == esapi.PushSecretDeletionPolicyDelete {
+		for storeKey, oldData := range ps.Status.SyncedPushSecrets {
+			for ref, old := range oldData {
+				if _, ok := planned[storeKey][ref]; ok {
+					continue
+				}
+				plan.Changes = append(plan.Changes, esapi.PushSecretDryRunChange{
+					Store:     storeKey,
+					RemoteKey: old.GetRemoteKey(),
+					Property:  old.GetProperty(),
+					Action:    esapi.PushSecretDryRunActionDelete,
+				})
+			}
+		}
+	}
+
+	slices.SortFunc(plan.Changes, func(a, b esapi.PushSecretDryRunChange) int {
+		return cmp.Or(
+			cmp.Compare(a.Store, b.Store),
+			cmp.Compare(a.RemoteKey, b.RemoteKey),
+			cmp.Compare(a.Property, b.Property),
+		)
+	})
+	return plan, nil
+}
+
+func (r *Reconciler) markAsDryRun(ps *esapi.PushSecret, plan *esapi.PushSecretDryRunStatus, start time.Time) {
+	cond := NewPushSecretCondition(esapi.PushSecretReady, v1.ConditionTrue, esapi.ReasonDryRun, msgDryRun)
+	SetPushSecretCondition(ps, *cond)
+	ps.Status.DryRun = plan
+	ps.Status.RefreshTime = metav1.NewTime(start)
+	ps.Status.SyncedResourceVersion = util.GetResourceVersion(ps.ObjectMeta)
+
+	var write, skip, del int
+	for _, c := range plan.Changes {
+		switch c.Action {
+		case esapi.PushSecretDryRunActionWrite:
+			write++
+		case esapi.PushSecretDryRunActionSkip:
+			skip++
+		case esapi.PushSecretDryRunActionDelete:
+			del++
+		}
+	}
+	r.recorder.Eventf(ps, v1.EventTypeNormal, esapi.ReasonDryRun, eventDryRunFmt, write, skip, del)
+}
@@ -0,0 +1,154 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pushsecret
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/jsonpath"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	esapi "github.com/external-secrets/external-secrets/apis/externalsecrets/v1alpha1"
+)
+
+const (
+	errResourceSelectorDisabled = "reading fields of resources is disabled, enable it with --unsafe-allow-pushsecret-resources"
+	errResourceClusterScoped    = "kind %s is cluster-scoped, only fields of namespaced resources can be pushed"
+)
+
+// resolveSecretsFromConfigMaps returns the ConfigMaps selected by name or label as Secrets.
+func (r *Reconciler) resolveSecretsFromConfigMaps(ctx context.Context, namespace string, selector *esapi.PushSecretConfigMap) ([]v1.Secret, error) {
+	if selector.Name != "" {
+		configMap := &v1.ConfigMap{}
+		if err := r.Client.Get(ctx, types.NamespacedName{Name: selector.Name, Namespace: namespace}, configMap); err != nil {
+			return nil, err
+		}
+		return []v1.Secret{secretFromConfigMap(configMap)}, nil
+	}
+	labelSelector, err := metav1.LabelSelectorAsSelector(selector.Selector)
+	if err != nil {
+		return nil, err
+	}
+	var configMapList v1.ConfigMapList
+	if err := r.List(ctx, &configMapList, &client.ListOptions{LabelSelector: labelSelector, Namespace: namespace}); err != nil {
+		return nil, err
+	}
+	secrets := make([]v1.Secret, 0, len(configMapList.Items))
+	for i := range configMapList.Items {
+		secrets = append(secrets, secretFromConfigMap(&configMapList.Items[i]))
+	}
+	return secrets, nil
+}
+
+// secretFromConfigMap copies the data and binaryData of a ConfigMap into a Secret.
+func secretFromConfigMap(configMap *v1.ConfigMap) v1.Secret {
+	data := make(map[string][]byte, len(configMap.Data)+len(configMap.BinaryData))
+	for k, v := range configMap.Data {
+		data[k] = []byte(v)
+	}
+	for k, v := range configMap.BinaryData {
+		data[k] = v
+	}
+	return v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        configMap.Name,
+			Namespace:   configMap.Namespace,
+			Labels:      configMap.Labels,
+			Annotations: configMap.Annotations,
+		},
+		Data: data,
+	}
+}
+
+// resolveSecretFromResource evaluates the JSONPath fields of a resource into a Secret.
+func (r *Reconciler) resolveSecretFromResource(ctx context.Context, namespace string, resource *esapi.PushSecretResource) (*v1.Secret, error) {
+	if !r.AllowResourceSelector {
+		return nil, errors.New(errResourceSelectorDisabled)
+	}
+	gv, err := schema.ParseGroupVersion(resource.APIVersion)
+	if err != nil {
+		return nil, err
+	}
+	gvk := gv.WithKind(resource.Kind)
+	mapping, err := r.Client.RESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return nil, err
+	}
+	// the client drops the namespace of cluster-scoped objects, so they must be rejected
+	// to keep a PushSecret from reading objects outside of its namespace.
+	if mapping.Scope.Name() != meta.RESTScopeNameNamespace {
+		return nil, fmt.Errorf(errResourceClusterScoped, gvk.Kind)
+	}
+	// NOTE: unstructured objects are not cached, so this is always a live read.
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(gvk)
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: resource.Name, Namespace: namespace}, obj); err != nil {
+		return nil, err
+	}
+	data := make(map[string][]byte, len(resource.Fields))
+	for key, path := range resource.Fields {
+		value, err := jsonPathValue(obj.Object, path)
+		if err != nil {
+			return nil, fmt.Errorf("could not evaluate field %q: %w", key, err)
+		}
+		data[key] = value
+	}
+	return &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      resource.Name,
+			Namespace: namespace,
+		},
+		Data: data,
+	}, nil
+}
+
+// jsonPathValue evaluates a JSONPath expression. A single string is returned as it is,
+// any other result is encoded as JSON, with multiple results as an array.
+func jsonPathValue(obj map[string]any, path string) ([]byte, error) {
+	j := jsonpath.New("field")
+	if err := j.Parse(path); err != nil {
+		return nil, err
+	}
+	results, err := j.FindResults(obj)
+	if err != nil {
+		return nil, err
+	}
+	var values []any
+	for _, result := range results {
+		for _, v := range result {
+			values = append(values, v.Interface())
+		}
+	}
+	switch len(values) {
+	case 0:
+		return nil, fmt.Errorf("%s matched no value", path)
+	case 1:
+		if s, ok := values[0].(string); ok {
+			return []byte(s), nil
+		}
+		return json.Marshal(values[0])
+	default:
+		return json.Marshal(values)
+	}
+}
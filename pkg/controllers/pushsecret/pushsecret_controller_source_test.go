@@ -0,0 +1,180 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pushsecret
+
+import (
+	"context"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	esapi "github.com/external-secrets/external-secrets/apis/externalsecrets/v1alpha1"
+)
+
+func TestJSONPathValue(t *testing.T) {
+	obj := map[string]any{
+		"spec": map[string]any{
+			"clusterIP": "10.0.0.1",
+			"ports": []any{
+				map[string]any{"name": "http", "port": int64(80)},
+				map[string]any{"name": "https", "port": int64(443)},
+			},
+		},
+		"status": map[string]any{
+			"conditions": []any{
+				map[string]any{"type": "Ready", "status": "True"},
+			},
+		},
+	}
+	tests := []struct {
+		name    string
+		path    string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "string",
+			path: "{.spec.clusterIP}",
+			want: "10.0.0.1",
+		},
+		{
+			name: "number",
+			path: "{.spec.ports[0].port}",
+			want: "80",
+		},
+		{
+			name: "filter",
+			path: `{.status.conditions[?(@.type=="Ready")].status}`,
+			want: "True",
+		},
+		{
+			name: "object",
+			path: "{.spec.ports[1]}",
+			want: `{"name":"https","port":443}`,
+		},
+		{
+			name: "multiple values",
+			path: "{.spec.ports[*].name}",
+			want: `["http","https"]`,
+		},
+		{
+			name:    "missing field",
+			path:    "{.spec.loadBalancerIP}",
+			wantErr: true,
+		},
+		{
+			name:    "invalid expression",
+			path:    "{.spec[}",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := jsonPathValue(obj, tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("jsonPathValue() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && string(got) != tt.want {
+				t.Errorf("jsonPathValue() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSecretFromConfigMap(t *testing.T) {
+	got := secretFromConfigMap(&v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "default"},
+		Data:       map[string]string{"text": "value"},
+		BinaryData: map[string][]byte{"binary": {0x00, 0x01}},
+	})
+	if got.Name != "config" || got.Namespace != "default" {
+		t.Errorf("secretFromConfigMap() = %s/%s, want default/config", got.Namespace, got.Name)
+	}
+	if string(got.Data["text"]) != "value" || len(got.Data["binary"]) != 2 || len(got.Data) != 2 {
+		t.Errorf("secretFromConfigMap() data = %v, want text and binary", got.Data)
+	}
+}
+
+func TestResolveSecretFromResource(t *testing.T) {
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(v1.SchemeGroupVersion.WithKind("ConfigMap"), meta.RESTScopeNamespace)
+	mapper.Add(v1.SchemeGroupVersion.WithKind("Namespace"), meta.RESTScopeRoot)
+	c := fake.NewClientBuilder().
+		WithScheme(clientgoscheme.Scheme).
+		WithRESTMapper(mapper).
+		WithObjects(
+			&v1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "default"},
+				Data:       map[string]string{"key": "value"},
+			},
+			&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kube-system"}},
+		).
+		Build()
+	tests := []struct {
+		name     string
+		disabled bool
+		kind     string
+		resource string
+		want     string
+		wantErr  bool
+	}{
+		{
+			name:     "namespaced kind",
+			kind:     "ConfigMap",
+			resource: "config",
+			want:     "value",
+		},
+		{
+			name:     "cluster-scoped kind",
+			kind:     "Namespace",
+			resource: "kube-system",
+			wantErr:  true,
+		},
+		{
+			name:     "unknown kind",
+			kind:     "Unknown",
+			resource: "config",
+			wantErr:  true,
+		},
+		{
+			name:     "disabled",
+			disabled: true,
+			kind:     "ConfigMap",
+			resource: "config",
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Reconciler{Client: c, AllowResourceSelector: !tt.disabled}
+			got, err := r.resolveSecretFromResource(context.Background(), "default", &esapi.PushSecretResource{
+				APIVersion: "v1",
+				Kind:       tt.kind,
+				Name:       tt.resource,
+				Fields:     map[string]string{"key": "{.data.key}"},
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("resolveSecretFromResource() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && string(got.Data["key"]) != tt.want {
+				t.Errorf("resolveSecretFromResource() = %s, want %s", got.Data["key"], tt.want)
+			}
+		})
+	}
+}
@@ -24,6 +24,7 @@ import (
 	"time"
 
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -34,6 +35,7 @@ import (
 	ctest "github.com/external-secrets/external-secrets/pkg/controllers/commontest"
 	"github.com/external-secrets/external-secrets/pkg/controllers/pushsecret/psmetrics"
 	"github.com/external-secrets/external-secrets/pkg/provider/testing/fake"
+	"github.com/external-secrets/external-secrets/pkg/utils"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -243,6 +245,36 @@ var _ = Describe("PushSecret controller", func() {
 		}
 	}
 
+	// if DryRun is set, nothing should be pushed, the planned changes should be written to the status instead
+	syncDryRun := func(tc *testCase) {
+		fakeProvider.SetSecretFn = func() error {
+			return nil
+		}
+		tc.pushsecret.Spec.DryRun = true
+		updatedPS := &v1alpha1.PushSecret{}
+		tc.assert = func(ps *v1alpha1.PushSecret, secret *v1.Secret) bool {
+			Eventually(func() bool {
+				By("checking if the planned changes are part of the status")
+				psKey := types.NamespacedName{Name: PushSecretName, Namespace: PushSecretNamespace}
+				err := k8sClient.Get(context.Background(), psKey, updatedPS)
+				if err != nil || updatedPS.Status.DryRun == nil {
+					return false
+				}
+				expected := []v1alpha1.PushSecretDryRunChange{
+					{
+						Store:     fmt.Sprintf(storePrefixTemplate, PushSecretStore),
+						RemoteKey: defaultPath,
+						Action:    v1alpha1.PushSecretDryRunActionWrite,
+						Hash:      utils.ObjectHash(secret.Data[defaultKey]),
+					},
+				}
+				return equality.Semantic.DeepEqual(expected, updatedPS.Status.DryRun.Changes)
+			}, time.Second*10, time.Second).Should(BeTrue())
+			Expect(fakeProvider.GetPushSecretData()).To(BeEmpty())
+			return true
+		}
+	}
+
 	updateIfNotExists := func(tc *testCase) {
 		fakeProvider.SetSecretFn = func() error {
 			return nil
@@ -367,6 +399,83 @@ var _ = Describe("PushSecret controller", func() {
 		}
 	}
 
+	updateIfChanged := func(tc *testCase) {
+		fakeProvider.SetSecretFn = func() error {
+			return nil
+		}
+		fakeProvider.SecretExistsFn = func(ctx context.Context, ref esv1.PushSecretRemoteRef) (bool, error) {
+			return true, nil
+		}
+		// the provider already holds the value of the secret
+		fakeProvider.GetSecretFn = func(ctx context.Context, ref esv1.ExternalSecretDataRemoteRef) ([]byte, error) {
+			if args, ok := fakeProvider.GetPushSecretData()[ref.Key]; ok {
+				return args.Value, nil
+			}
+			return []byte(defaultVal), nil
+		}
+		tc.pushsecret.Spec.UpdatePolicy = v1alpha1.PushSecretUpdatePolicyIfChanged
+		updatedPS := &v1alpha1.PushSecret{}
+
+		tc.assert = func(ps *v1alpha1.PushSecret, secret *v1.Secret) bool {
+			By("checking if the unchanged value is not pushed")
+			Eventually(func() bool {
+				psKey := types.NamespacedName{Name: PushSecretName, Namespace: PushSecretNamespace}
+				if err := k8sClient.Get(context.Background(), psKey, updatedPS); err != nil {
+					return false
+				}
+				_, ok := updatedPS.Status.SyncedPushSecrets[fmt.Sprintf(storePrefixTemplate, PushSecretStore)][defaultPath]
+				return ok
+			}, time.Second*10, time.Second).Should(BeTrue())
+			Expect(fakeProvider.GetPushSecretData()).To(BeEmpty())
+
+			By("checking if a changed value is pushed")
+			secret.Data[defaultKey] = []byte(newVal)
+			Expect(k8sClient.Update(context.Background(), secret, &client.UpdateOptions{})).Should(Succeed())
+			Eventually(func() bool {
+				providerValue, ok := fakeProvider.GetPushSecretData()[defaultPath]
+				return ok && bytes.Equal(providerValue.Value, []byte(newVal))
+			}, time.Second*10, time.Second).Should(BeTrue())
+			return true
+		}
+	}
+
+	stopOnConflict := func(tc *testCase) {
+		fakeProvider.SetSecretFn = func() error {
+			return nil
+		}
+		fakeProvider.SecretExistsFn = func(ctx context.Context, ref esv1.PushSecretRemoteRef) (bool, error) {
+			return true, nil
+		}
+		// the value was edited in the provider after it was pushed
+		fakeProvider.GetSecretFn = func(ctx context.Context, ref esv1.ExternalSecretDataRemoteRef) ([]byte, error) {
+			return []byte("edited in provider"), nil
+		}
+		tc.pushsecret.Spec.ConflictPolicy = v1alpha1.PushSecretConflictPolicyStop
+
+		tc.assert = func(ps *v1alpha1.PushSecret, secret *v1.Secret) bool {
+			By("waiting for the value to be pushed once")
+			Eventually(func() bool {
+				_, ok := fakeProvider.GetPushSecretData()[defaultPath]
+				return ok
+			}, time.Second*10, time.Second).Should(BeTrue())
+
+			By("checking if the edited remote value is not overwritten")
+			secret.Data[defaultKey] = []byte(newVal)
+			Expect(k8sClient.Update(context.Background(), secret, &client.UpdateOptions{})).Should(Succeed())
+			Eventually(func() bool {
+				updatedPS := &v1alpha1.PushSecret{}
+				psKey := types.NamespacedName{Name: PushSecretName, Namespace: PushSecretNamespace}
+				if err := k8sClient.Get(context.Background(), psKey, updatedPS); err != nil {
+					return false
+				}
+				cond := GetPushSecretCondition(updatedPS.Status.Conditions, v1alpha1.PushSecretConflict)
+				return cond != nil && cond.Status == v1.ConditionTrue
+			}, time.Second*10, time.Second).Should(BeTrue())
+			Expect(fakeProvider.GetPushSecretData()[defaultPath].Value).To(Equal([]byte(defaultVal)))
+			return true
+		}
+	}
+
 	updateIfNotExistsSyncFailed := func(tc *testCase) {
 		fakeProvider.SetSecretFn = func() error {
 			return nil
@@ -834,6 +943,94 @@ var _ = Describe("PushSecret controller", func() {
 			return true
 		}
 	}
+	// dataFrom should push every matching key to its rewritten remote key.
+	syncWithDataFrom := func(tc *testCase) {
+		fakeProvider.SetSecretFn = func() error {
+			return nil
+		}
+		tc.pushsecret.Spec.Data = nil
+		tc.pushsecret.Spec.DataFrom = []v1alpha1.PushSecretDataFrom{
+			{
+				Match: &v1alpha1.PushSecretDataFromMatch{
+					RegExp: "key$",
+				},
+				Rewrite: []esv1.ExternalSecretRewrite{
+					{
+						Regexp: &esv1.ExternalSecretRewriteRegexp{
+							Source: "^(.*)$",
+							Target: "path/to/$1",
+						},
+					},
+				},
+			},
+		}
+		tc.secret.Data = map[string][]byte{
+			defaultKey:            []byte(defaultVal),
+			otherKey:              []byte(otherVal),
+			newKey + "-unmatched": []byte(newVal),
+		}
+		tc.assert = func(ps *v1alpha1.PushSecret, secret *v1.Secret) bool {
+			Eventually(func() bool {
+				By("checking if the matching keys were pushed")
+				setSecretArgs := fakeProvider.GetPushSecretData()
+				if len(setSecretArgs) != 2 {
+					return false
+				}
+				return bytes.Equal(setSecretArgs[defaultPath].Value, []byte(defaultVal)) &&
+					bytes.Equal(setSecretArgs[otherPath].Value, []byte(otherVal))
+			}, time.Second*10, time.Second).Should(BeTrue())
+			Eventually(func() bool {
+				By("checking if the pushed keys are part of the status")
+				updatedPS := &v1alpha1.PushSecret{}
+				psKey := types.NamespacedName{Name: PushSecretName, Namespace: PushSecretNamespace}
+				if err := k8sClient.Get(context.Background(), psKey, updatedPS); err != nil {
+					return false
+				}
+				synced := updatedPS.Status.SyncedPushSecrets[fmt.Sprintf(storePrefixTemplate, PushSecretStore)]
+				return len(synced) == 2 &&
+					synced[defaultPath].Match.SecretKey == defaultKey &&
+					synced[otherPath].Match.SecretKey == otherKey
+			}, time.Second*10, time.Second).Should(BeTrue())
+			return true
+		}
+	}
+	// a bundle should push the keys as one document, merged into the existing remote document.
+	syncWithBundle := func(tc *testCase) {
+		fakeProvider.SetSecretFn = func() error {
+			return nil
+		}
+		fakeProvider.WithGetSecret([]byte(`{"key":"old","unrelated":"kept"}`), nil)
+		tc.pushsecret.Spec.Data = nil
+		tc.pushsecret.Spec.DataFrom = []v1alpha1.PushSecretDataFrom{
+			{
+				Bundle: &v1alpha1.PushSecretBundle{
+					RemoteRef: v1alpha1.PushSecretRemoteRef{
+						RemoteKey: defaultPath,
+					},
+					Format:      v1alpha1.PushSecretBundleFormatJSON,
+					MergePolicy: v1alpha1.PushSecretBundleMergePolicyMerge,
+				},
+			},
+		}
+		tc.secret.Data = map[string][]byte{
+			defaultKey: []byte(defaultVal),
+			otherKey:   []byte(otherVal),
+		}
+		tc.assert = func(ps *v1alpha1.PushSecret, secret *v1.Secret) bool {
+			Eventually(func() bool {
+				By("checking if the merged document was pushed")
+				setSecretArgs := fakeProvider.GetPushSecretData()
+				providerValue, ok := setSecretArgs[defaultPath]
+				if !ok {
+					return false
+				}
+				return string(providerValue.Value) == `{"key":"value","other-key":"other-value","unrelated":"kept"}`
+			}, time.Second*10, time.Second).Should(BeTrue())
+			fakeProvider.WithGetSecret(nil, nil)
+			return true
+		}
+	}
+
 	// if target Secret name is not specified it should use the ExternalSecret name.
 	syncMatchingLabels := func(tc *testCase) {
 		fakeProvider.SetSecretFn = func() error {
@@ -964,6 +1161,35 @@ var _ = Describe("PushSecret controller", func() {
 			return bytes.Equal([]byte("foo-bar-from-generator"), providerValue) && checkCondition(ps.Status, expected)
 		}
 	}
+	syncWithConfigMap := func(tc *testCase) {
+		fakeProvider.SetSecretFn = func() error {
+			return nil
+		}
+		Expect(k8sClient.Create(context.Background(), &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-configmap",
+				Namespace: PushSecretNamespace,
+			},
+			Data: map[string]string{
+				defaultKey: "foo-from-configmap",
+			},
+		})).ToNot(HaveOccurred())
+		tc.pushsecret.Spec.Selector.Secret = nil
+		tc.pushsecret.Spec.Selector.ConfigMap = &v1alpha1.PushSecretConfigMap{
+			Name: "test-configmap",
+		}
+		tc.assert = func(ps *v1alpha1.PushSecret, secret *v1.Secret) bool {
+			setSecretArgs := fakeProvider.GetPushSecretData()
+			providerValue := setSecretArgs[ps.Spec.Data[0].Match.RemoteRef.RemoteKey].Value
+			expected := v1alpha1.PushSecretStatusCondition{
+				Type:    v1alpha1.PushSecretReady,
+				Status:  v1.ConditionTrue,
+				Reason:  v1alpha1.ReasonSynced,
+				Message: "PushSecret synced successfully",
+			}
+			return bytes.Equal([]byte("foo-from-configmap"), providerValue) && checkCondition(ps.Status, expected)
+		}
+	}
 	// if target Secret name is not specified it should use the ExternalSecret name.
 	syncWithClusterStoreMatchingLabels := func(tc *testCase) {
 		fakeProvider.SetSecretFn = func() error {
@@ -1247,13 +1473,18 @@ var _ = Describe("PushSecret controller", func() {
 			// this must be optional so we can test faulty es configuration
 		},
 		Entry("should sync", syncSuccessfully),
+		Entry("should not push secrets if DryRun=true", syncDryRun),
 		Entry("should not update existing secret if UpdatePolicy=IfNotExists", updateIfNotExists),
 		Entry("should only update parts of secret that don't already exist if UpdatePolicy=IfNotExists", updateIfNotExistsPartialSecrets),
 		Entry("should update the PushSecret status correctly if UpdatePolicy=IfNotExists", updateIfNotExistsSyncStatus),
 		Entry("should fail if secret existence cannot be verified if UpdatePolicy=IfNotExists", updateIfNotExistsSyncFailed),
+		Entry("should only push changed values if UpdatePolicy=IfChanged", updateIfChanged),
+		Entry("should not overwrite remote edits if ConflictPolicy=Stop", stopOnConflict),
 		Entry("should sync with template", syncSuccessfullyWithTemplate),
 		Entry("should sync with template reusing keys", syncSuccessfullyReusingKeys),
 		Entry("should sync with conversion strategy", syncSuccessfullyWithConversionStrategy),
+		Entry("should sync with dataFrom", syncWithDataFrom),
+		Entry("should sync with bundle", syncWithBundle),
 		Entry("should delete if DeletionPolicy=Delete", syncAndDeleteSuccessfully),
 		Entry("should delete after DeletionPolicy changed from Delete to None", syncChangePolicyAndDeleteSuccessfully),
 		Entry("should track deletion tasks if Delete fails", failDelete),
@@ -1263,6 +1494,7 @@ var _ = Describe("PushSecret controller", func() {
 		Entry("should sync with ClusterStore", syncWithClusterStore),
 		Entry("should sync with ClusterStore matching labels", syncWithClusterStoreMatchingLabels),
 		Entry("should sync with Generator", syncWithGenerator),
+		Entry("should sync with ConfigMap", syncWithConfigMap),
 		Entry("should fail if Secret is not created", failNoSecret),
 		Entry("should fail if Secret Key does not exist", failNoSecretKey),
 		Entry("should fail if SetSecret fails", setSecretFail),
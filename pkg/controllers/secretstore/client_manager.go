@@ -30,6 +30,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	"github.com/external-secrets/external-secrets/pkg/metrics"
 )
 
 const (
@@ -52,6 +53,17 @@ type Manager struct {
 
 	// store clients by provider type
 	clientMap map[clientKey]*clientVal
+
+	// stores which failed in GetWithFallback
+	failedStores map[esv1.SecretStoreRef]error
+
+	// caches provider responses across managers, nil if disabled
+	responseCache *ResponseCache
+	// fetch responses from the provider instead of the response cache
+	refreshResponses bool
+
+	// keeps clients across managers, nil if disabled
+	clientPool *ClientPool
 }
 
 type clientKey struct {
@@ -75,6 +87,25 @@ func NewManager(ctrlClient client.Client, controllerClass string, enableFloodgat
 	}
 }
 
+// WithResponseCache serves GetSecret and GetSecretMap of the returned clients from the given cache.
+func (m *Manager) WithResponseCache(c *ResponseCache) *Manager {
+	m.responseCache = c
+	return m
+}
+
+// WithRefreshedResponses makes the returned clients fetch responses from the provider
+// instead of the response cache, the fetched responses replace the cached ones.
+func (m *Manager) WithRefreshedResponses(refresh bool) *Manager {
+	m.refreshResponses = refresh
+	return m
+}
+
+// WithClientPool reuses clients of the given pool, the clients are returned to the pool on Close.
+func (m *Manager) WithClientPool(p *ClientPool) *Manager {
+	m.clientPool = p
+	return m
+}
+
 func (m *Manager) GetFromStore(ctx context.Context, store esv1.GenericStore, namespace string) (esv1.SecretsClient, error) {
 	storeProvider, err := esv1.GetProvider(store)
 	if err != nil {
@@ -82,23 +113,38 @@ func (m *Manager) GetFromStore(ctx context.Context, store esv1.GenericStore, nam
 	}
 	secretClient := m.getStoredClient(ctx, storeProvider, store)
 	if secretClient != nil {
-		return secretClient, nil
+		return m.decorate(ctx, secretClient, store, namespace), nil
 	}
-	m.log.V(1).Info("creating new client",
-		"provider", fmt.Sprintf("%T", storeProvider),
-		"store", fmt.Sprintf("%s/%s", store.GetNamespace(), store.GetName()))
-	// secret client is created only if we are going to refresh
-	// this skip an unnecessary check/request in the case we are not going to do anything
-	secretClient, err = storeProvider.NewClient(ctx, store, m.client, namespace)
-	if err != nil {
-		return nil, err
+	var slot *poolSlot
+	if m.clientPool != nil {
+		secretClient, slot = m.clientPool.Get(ctx, m.client, store, namespace)
+	}
+	if secretClient == nil {
+		m.log.V(1).Info("creating new client",
+			"provider", fmt.Sprintf("%T", storeProvider),
+			"store", fmt.Sprintf("%s/%s", store.GetNamespace(), store.GetName()))
+		// secret client is created only if we are going to refresh
+		// this skip an unnecessary check/request in the case we are not going to do anything
+		secretClient, err = storeProvider.NewClient(ctx, store, m.client, namespace)
+		metrics.ObserveAuthCall(store.GetKind(), store.GetName(), store.GetNamespace())
+		if err != nil {
+			return nil, err
+		}
+		secretClient = slot.wrap(secretClient)
 	}
 	idx := storeKey(storeProvider)
 	m.clientMap[idx] = &clientVal{
 		client: secretClient,
 		store:  store,
 	}
-	return secretClient, nil
+	return m.decorate(ctx, secretClient, store, namespace), nil
+}
+
+// decorate applies the rate limit of the store and the response cache to a client.
+// Responses served from the cache don't count against the rate limit.
+func (m *Manager) decorate(ctx context.Context, secretClient esv1.SecretsClient, store esv1.GenericStore, namespace string) esv1.SecretsClient {
+	secretClient = withRateLimit(secretClient, store, namespace)
+	return m.responseCache.wrap(ctx, m.client, secretClient, store, namespace, m.refreshResponses)
 }
 
 // Get returns a provider client from the given storeRef or sourceRef.secretStoreRef
@@ -135,6 +181,40 @@ func (m *Manager) Get(ctx context.Context, storeRef esv1.SecretStoreRef, namespa
 	return m.GetFromStore(ctx, store, namespace)
 }
 
+// GetWithFallback calls fn with the client of the first store of storeRefs.
+// If the client can't be created or fn fails because the store is unavailable,
+// fn is called again with the client of the next store.
+// Other errors of fn, like a missing secret, key or property or ErrThrottled,
+// are returned right away, the next store would see the same request.
+// A store which was unavailable once is skipped for the lifetime of the manager,
+// so an unreachable store is not queried again for every remote ref of an ExternalSecret.
+// It returns the store which served the call.
+func (m *Manager) GetWithFallback(ctx context.Context, storeRefs []esv1.SecretStoreRef, namespace string, fn func(esv1.SecretsClient) error) (esv1.SecretStoreRef, error) {
+	var errs error
+	for _, storeRef := range storeRefs {
+		if err, ok := m.failedStores[storeRef]; ok {
+			errs = errors.Join(errs, err)
+			continue
+		}
+		secretClient, err := m.Get(ctx, storeRef, namespace, nil)
+		if err == nil {
+			err = fn(secretClient)
+			if !isStoreUnavailable(err) {
+				return storeRef, err
+			}
+		}
+		if len(storeRefs) > 1 {
+			m.log.V(1).Info("store failed, falling back to the next store", "store", storeRef.Name, "kind", storeRef.Kind, "error", err.Error())
+		}
+		if m.failedStores == nil {
+			m.failedStores = make(map[esv1.SecretStoreRef]error)
+		}
+		m.failedStores[storeRef] = err
+		errs = errors.Join(errs, err)
+	}
+	return esv1.SecretStoreRef{}, errs
+}
+
 // returns a previously stored client from the cache if store and store-version match
 // if a client exists for the same provider which points to a different store or store version
 // it will be cleaned up.
@@ -194,7 +274,7 @@ func (m *Manager) getStore(ctx context.Context, storeRef *esv1.SecretStoreRef, n
 	return &store, nil
 }
 
-// Close cleans up all clients.
+// Close cleans up all clients, pooled clients are returned to their pool.
 func (m *Manager) Close(ctx context.Context) error {
 	var errs []string
 	for key, val := range m.clientMap {
@@ -16,6 +16,7 @@ package secretstore
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/go-logr/logr"
@@ -236,7 +237,7 @@ func TestManagerGet(t *testing.T) {
 				c, ok := mgr.clientMap[provKey]
 				assert.True(t, ok)
 				assert.Same(t, c.client, clientA)
-				assert.Same(t, sc, clientA)
+				assert.Same(t, unwrapRateLimit(sc), clientA)
 			},
 
 			afterClose: func() {
@@ -281,7 +282,7 @@ func TestManagerGet(t *testing.T) {
 				c, ok := mgr.clientMap[provKey]
 				assert.True(t, ok)
 				assert.Same(t, c.client, clientB)
-				assert.Same(t, sc, clientB)
+				assert.Same(t, unwrapRateLimit(sc), clientB)
 				assert.True(t, clientA.closeCalled)
 			},
 			afterClose: func() {
@@ -315,6 +316,87 @@ func TestManagerGet(t *testing.T) {
 	}
 }
 
+func TestManagerGetWithFallback(t *testing.T) {
+	scheme := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(esv1.AddToScheme(scheme))
+
+	fakeProvider := &WrapProvider{}
+	esv1.ForceRegister(fakeProvider, &esv1.SecretStoreProvider{
+		AWS: &esv1.AWSProvider{},
+	}, esv1.MaintenanceStatusMaintained)
+
+	const testNamespace = "foo"
+	newStore := func(name string) *esv1.SecretStore {
+		return &esv1.SecretStore{
+			TypeMeta:   metav1.TypeMeta{Kind: esv1.SecretStoreKind},
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testNamespace},
+			Spec: esv1.SecretStoreSpec{
+				Provider: &esv1.SecretStoreProvider{AWS: &esv1.AWSProvider{}},
+			},
+		}
+	}
+	kube := fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(newStore("primary"), newStore("secondary")).Build()
+
+	created := map[string]int{}
+	fakeProvider.newClientFunc = func(_ context.Context, store esv1.GenericStore, _ client.Client, _ string) (esv1.SecretsClient, error) {
+		created[store.GetName()]++
+		if store.GetName() == "primary" {
+			return nil, errors.New("connection refused")
+		}
+		return &MockFakeClient{id: store.GetName()}, nil
+	}
+
+	mgr := &Manager{
+		log:       logr.Discard(),
+		client:    kube,
+		clientMap: make(map[clientKey]*clientVal),
+	}
+	defer mgr.Close(context.Background())
+	storeRefs := []esv1.SecretStoreRef{{Name: "primary"}, {Name: "secondary"}}
+
+	// the secondary store serves the data if the primary fails
+	var served string
+	ref, err := mgr.GetWithFallback(context.Background(), storeRefs, testNamespace, func(c esv1.SecretsClient) error {
+		served = unwrapRateLimit(c).(*MockFakeClient).id
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "secondary", ref.Name)
+	assert.Equal(t, "secondary", served)
+
+	// the failed primary store is not tried again
+	_, err = mgr.GetWithFallback(context.Background(), storeRefs, testNamespace, func(_ esv1.SecretsClient) error {
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, created["primary"])
+
+	// a missing secret does not fall back
+	ref, err = mgr.GetWithFallback(context.Background(), []esv1.SecretStoreRef{{Name: "secondary"}, {Name: "primary"}}, testNamespace, func(_ esv1.SecretsClient) error {
+		return esv1.NoSecretErr
+	})
+	assert.ErrorIs(t, err, esv1.NoSecretErr)
+	assert.Equal(t, "secondary", ref.Name)
+
+	// data errors and throttling don't fall back and don't mark the store as failed
+	for _, fnErr := range []error{errors.New("key foo does not exist in secret bar"), ErrThrottled} {
+		ref, err = mgr.GetWithFallback(context.Background(), []esv1.SecretStoreRef{{Name: "secondary"}, {Name: "primary"}}, testNamespace, func(_ esv1.SecretsClient) error {
+			return fnErr
+		})
+		assert.ErrorIs(t, err, fnErr)
+		assert.Equal(t, "secondary", ref.Name)
+		assert.NotContains(t, mgr.failedStores, esv1.SecretStoreRef{Name: "secondary"})
+	}
+
+	// all stores failing returns all errors
+	_, err = mgr.GetWithFallback(context.Background(), storeRefs, testNamespace, func(_ esv1.SecretsClient) error {
+		return context.DeadlineExceeded
+	})
+	assert.ErrorContains(t, err, "connection refused")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
 func TestShouldProcessSecret(t *testing.T) {
 	scheme := runtime.NewScheme()
 
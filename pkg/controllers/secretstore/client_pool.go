@@ -0,0 +1,356 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	esmeta "github.com/external-secrets/external-secrets/apis/meta/v1"
+	"github.com/external-secrets/external-secrets/pkg/cache"
+	"github.com/external-secrets/external-secrets/pkg/metrics"
+)
+
+// ClientPool keeps provider clients across reconciles, so providers with expensive authentication,
+// e.g. Vault Kubernetes auth or AWS AssumeRole chains, don't log in again for every reconcile.
+// Only clients implementing esv1.PoolableClient are pooled.
+//
+// A pooled client is used by a single Manager at a time, concurrent reconciles using the same store
+// create additional clients which are returned to the pool as well.
+// Clients are keyed by the UID of the store and the namespace they were created for.
+// They are versioned by the resourceVersion of the store and of the Secrets and ConfigMaps it references,
+// so changed credentials create new clients.
+// Clients are closed once they are older than maxAge, their store changed, a call failed,
+// or they are evicted from the pool.
+type ClientPool struct {
+	log    logr.Logger
+	maxAge time.Duration
+
+	mu      sync.Mutex
+	cache   *cache.Cache[*idleClients]
+	size    int
+	evicted []*pooledClient
+}
+
+type idleClients struct {
+	clients []*pooledClient
+}
+
+// NewClientPool creates a pool holding the idle clients of up to size stores and namespaces.
+func NewClientPool(size int, maxAge time.Duration) (*ClientPool, error) {
+	p := &ClientPool{
+		log:    ctrl.Log.WithName("clientpool"),
+		maxAge: maxAge,
+	}
+	c, err := cache.New(size, func(idle *idleClients) {
+		// called with p.mu held, clients are closed after it is released
+		p.evicted = append(p.evicted, idle.clients...)
+		p.size -= len(idle.clients)
+	})
+	if err != nil {
+		return nil, err
+	}
+	p.cache = c
+	return p, nil
+}
+
+// poolSlot is where a newly created client of a store is returned to.
+type poolSlot struct {
+	pool    *ClientPool
+	key     cache.Key
+	version string
+}
+
+// Get takes an idle client of the store from the pool.
+// If there is none, the returned slot wraps the newly created client so it is returned to the pool when closed.
+// A nil slot means the client can't be pooled.
+func (p *ClientPool) Get(ctx context.Context, kube client.Client, store esv1.GenericStore, namespace string) (esv1.SecretsClient, *poolSlot) {
+	version, err := clientVersion(ctx, kube, store, namespace)
+	if err != nil {
+		// the client is not pooled, creating it reports the error
+		p.log.V(1).Info("not pooling client", "store", store.GetName(), "error", err.Error())
+		return nil, nil
+	}
+	slot := &poolSlot{
+		pool: p,
+		key: cache.Key{
+			Kind:      store.GetKind(),
+			Name:      string(store.GetObjectMeta().UID),
+			Namespace: namespace,
+		},
+		version: version,
+	}
+
+	p.mu.Lock()
+	var found *pooledClient
+	if idle, ok := p.cache.Get(version, slot.key); ok {
+		for len(idle.clients) > 0 && found == nil {
+			c := idle.clients[len(idle.clients)-1]
+			idle.clients = idle.clients[:len(idle.clients)-1]
+			p.size--
+			if p.expired(c) {
+				p.evicted = append(p.evicted, c)
+				continue
+			}
+			found = c
+		}
+	}
+	p.updated(ctx)
+
+	if found == nil {
+		return nil, slot
+	}
+	found.failed = false
+	found.Reset()
+	return found.client(), slot
+}
+
+// wrap makes a client return to the pool when it is closed.
+func (s *poolSlot) wrap(secretClient esv1.SecretsClient) esv1.SecretsClient {
+	poolable, ok := secretClient.(esv1.PoolableClient)
+	if s == nil || !ok {
+		return secretClient
+	}
+	c := &pooledClient{
+		PoolableClient: poolable,
+		slot:           s,
+		created:        time.Now(),
+	}
+	return c.client()
+}
+
+// put returns a client to the pool, unless it expired, failed, or its store changed in the meantime.
+func (p *ClientPool) put(ctx context.Context, c *pooledClient) {
+	p.mu.Lock()
+	if c.failed || p.expired(c) {
+		p.evicted = append(p.evicted, c)
+		p.updated(ctx)
+		return
+	}
+	idle, ok := p.cache.Get(c.slot.version, c.slot.key)
+	if !ok {
+		idle = &idleClients{}
+		p.cache.Add(c.slot.version, c.slot.key, idle)
+	}
+	idle.clients = append(idle.clients, c)
+	p.size++
+	p.updated(ctx)
+}
+
+func (p *ClientPool) expired(c *pooledClient) bool {
+	return p.maxAge > 0 && time.Since(c.created) > p.maxAge
+}
+
+// updated reports the pool size, releases p.mu and closes evicted clients.
+func (p *ClientPool) updated(ctx context.Context) {
+	evicted := p.evicted
+	p.evicted = nil
+	metrics.SetClientPoolSize(p.size)
+	p.mu.Unlock()
+
+	for _, c := range evicted {
+		if err := c.PoolableClient.Close(ctx); err != nil {
+			p.log.Error(err, "could not close pooled client", "kind", c.slot.key.Kind, "namespace", c.slot.key.Namespace)
+		}
+	}
+}
+
+// pooledClient is a client of the pool, closing it returns it to the pool.
+// Clients are discarded instead if a call failed, e.g. because their credentials expired.
+type pooledClient struct {
+	esv1.PoolableClient
+	slot    *poolSlot
+	created time.Time
+	failed  bool
+}
+
+// client returns the pooled client, keeping batch support of the underlying client.
+func (c *pooledClient) client() esv1.SecretsClient {
+	if _, ok := c.PoolableClient.(esv1.BatchSecretsClient); ok {
+		return &batchPooledClient{c}
+	}
+	return c
+}
+
+// batchPooledClient is a pooledClient of a client which fetches secrets in batches.
+type batchPooledClient struct {
+	*pooledClient
+}
+
+func (c *batchPooledClient) GetSecrets(ctx context.Context, refs []esv1.ExternalSecretDataRemoteRef) ([][]byte, []error, error) {
+	secrets, _, errs, err := c.GetSecretsWithMetadata(ctx, refs)
+	return secrets, errs, err
+}
+
+func (c *batchPooledClient) GetSecretsWithMetadata(ctx context.Context, refs []esv1.ExternalSecretDataRemoteRef) ([][]byte, []*esv1.SecretMetadata, []error, error) {
+	secrets, metadata, errs, err := esv1.GetSecretsWithMetadata(ctx, c.PoolableClient.(esv1.BatchSecretsClient), refs)
+	c.observe(err)
+	return secrets, metadata, errs, err
+}
+
+func (c *pooledClient) GetSecret(ctx context.Context, ref esv1.ExternalSecretDataRemoteRef) ([]byte, error) {
+	secret, err := c.PoolableClient.GetSecret(ctx, ref)
+	c.observe(err)
+	return secret, err
+}
+
+// GetSecretWithMetadata keeps the metadata of the underlying client, if it returns any.
+func (c *pooledClient) GetSecretWithMetadata(ctx context.Context, ref esv1.ExternalSecretDataRemoteRef) ([]byte, *esv1.SecretMetadata, error) {
+	secret, metadata, err := esv1.GetSecretWithMetadata(ctx, c.PoolableClient, ref)
+	c.observe(err)
+	return secret, metadata, err
+}
+
+func (c *pooledClient) GetSecretMap(ctx context.Context, ref esv1.ExternalSecretDataRemoteRef) (map[string][]byte, error) {
+	secretMap, err := c.PoolableClient.GetSecretMap(ctx, ref)
+	c.observe(err)
+	return secretMap, err
+}
+
+func (c *pooledClient) GetAllSecrets(ctx context.Context, ref esv1.ExternalSecretFind) (map[string][]byte, error) {
+	secretMap, err := c.PoolableClient.GetAllSecrets(ctx, ref)
+	c.observe(err)
+	return secretMap, err
+}
+
+func (c *pooledClient) PushSecret(ctx context.Context, secret *corev1.Secret, data esv1.PushSecretData) error {
+	err := c.PoolableClient.PushSecret(ctx, secret, data)
+	c.observe(err)
+	return err
+}
+
+func (c *pooledClient) DeleteSecret(ctx context.Context, remoteRef esv1.PushSecretRemoteRef) error {
+	err := c.PoolableClient.DeleteSecret(ctx, remoteRef)
+	c.observe(err)
+	return err
+}
+
+func (c *pooledClient) SecretExists(ctx context.Context, remoteRef esv1.PushSecretRemoteRef) (bool, error) {
+	exists, err := c.PoolableClient.SecretExists(ctx, remoteRef)
+	c.observe(err)
+	return exists, err
+}
+
+func (c *pooledClient) Close(ctx context.Context) error {
+	c.slot.pool.put(ctx, c)
+	return nil
+}
+
+func (c *pooledClient) observe(err error) {
+	if err != nil && !errors.Is(err, esv1.NoSecretErr) {
+		c.failed = true
+	}
+}
+
+// clientVersion returns the version of the clients of a store,
+// made of the resourceVersion of the store and of the Secrets and ConfigMaps its provider references.
+func clientVersion(ctx context.Context, kube client.Client, store esv1.GenericStore, namespace string) (string, error) {
+	versions, err := referenceVersions(ctx, kube, store, namespace)
+	if err != nil {
+		return "", err
+	}
+	return strings.Join(append([]string{store.GetObjectMeta().ResourceVersion}, versions...), ","), nil
+}
+
+// referenceVersions returns the sorted resourceVersions of the Secrets and ConfigMaps the provider of a store references.
+func referenceVersions(ctx context.Context, kube client.Client, store esv1.GenericStore, namespace string) ([]string, error) {
+	// references of a SecretStore are always in its namespace,
+	// references of a ClusterSecretStore default to the namespace of the ExternalSecret
+	refNamespace := func(ns *string) string {
+		if store.GetKind() != esv1.ClusterSecretStoreKind {
+			return store.GetNamespace()
+		}
+		if ns != nil {
+			return *ns
+		}
+		return namespace
+	}
+
+	refs := map[string]client.Object{}
+	addRef := func(obj client.Object, name string, ns *string) {
+		obj.SetName(name)
+		obj.SetNamespace(refNamespace(ns))
+		refs[fmt.Sprintf("%T/%s/%s", obj, obj.GetNamespace(), name)] = obj
+	}
+	walkProviderRefs(reflect.ValueOf(store.GetSpec().Provider), func(v any) {
+		switch ref := v.(type) {
+		case esmeta.SecretKeySelector:
+			if ref.Name != "" {
+				addRef(&corev1.Secret{}, ref.Name, ref.Namespace)
+			}
+		case esv1.CAProvider:
+			if ref.Type == esv1.CAProviderTypeConfigMap {
+				addRef(&corev1.ConfigMap{}, ref.Name, ref.Namespace)
+			} else if ref.Type == esv1.CAProviderTypeSecret {
+				addRef(&corev1.Secret{}, ref.Name, ref.Namespace)
+			}
+		}
+	})
+
+	versions := make([]string, 0, len(refs))
+	for ref, obj := range refs {
+		if obj.GetNamespace() == "" {
+			return nil, fmt.Errorf("%s is referenced without a namespace", ref)
+		}
+		if err := kube.Get(ctx, client.ObjectKeyFromObject(obj), obj); err != nil {
+			return nil, err
+		}
+		versions = append(versions, ref+"="+obj.GetResourceVersion())
+	}
+	slices.Sort(versions)
+	return versions, nil
+}
+
+// walkProviderRefs calls fn with every SecretKeySelector and CAProvider of a provider spec.
+func walkProviderRefs(v reflect.Value, fn func(any)) {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if !v.IsNil() {
+			walkProviderRefs(v.Elem(), fn)
+		}
+	case reflect.Struct:
+		switch v.Type() {
+		case reflect.TypeFor[esmeta.SecretKeySelector](), reflect.TypeFor[esv1.CAProvider]():
+			fn(v.Interface())
+			return
+		}
+		for i := range v.NumField() {
+			if v.Type().Field(i).IsExported() {
+				walkProviderRefs(v.Field(i), fn)
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := range v.Len() {
+			walkProviderRefs(v.Index(i), fn)
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			walkProviderRefs(iter.Value(), fn)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretstore
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	esmeta "github.com/external-secrets/external-secrets/apis/meta/v1"
+)
+
+type poolableClient struct {
+	MockFakeClient
+	resets int
+	err    error
+}
+
+func (c *poolableClient) Reset() {
+	c.resets++
+}
+
+func (c *poolableClient) GetSecret(_ context.Context, _ esv1.ExternalSecretDataRemoteRef) ([]byte, error) {
+	return nil, c.err
+}
+
+func TestClientPool(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(esv1.AddToScheme(scheme))
+
+	fakeProvider := &WrapProvider{}
+	esv1.ForceRegister(fakeProvider, &esv1.SecretStoreProvider{
+		AWS: &esv1.AWSProvider{},
+	}, esv1.MaintenanceStatusMaintained)
+
+	store := &esv1.SecretStore{
+		TypeMeta:   metav1.TypeMeta{Kind: esv1.SecretStoreKind},
+		ObjectMeta: metav1.ObjectMeta{Name: "aws", Namespace: "default", UID: "1234"},
+		Spec: esv1.SecretStoreSpec{
+			Provider: &esv1.SecretStoreProvider{AWS: &esv1.AWSProvider{
+				Auth: esv1.AWSAuth{SecretRef: &esv1.AWSAuthSecretRef{
+					AccessKeyID:     esmeta.SecretKeySelector{Name: "credentials", Key: "id"},
+					SecretAccessKey: esmeta.SecretKeySelector{Name: "credentials", Key: "secret"},
+				}},
+			}},
+		},
+	}
+	credentials := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "credentials", Namespace: "default"}}
+	kube := fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(store, credentials).Build()
+
+	var created []*poolableClient
+	fakeProvider.newClientFunc = func(_ context.Context, _ esv1.GenericStore, _ client.Client, _ string) (esv1.SecretsClient, error) {
+		created = append(created, &poolableClient{})
+		return created[len(created)-1], nil
+	}
+
+	pool, err := NewClientPool(10, time.Hour)
+	require.NoError(t, err)
+	newManager := func() *Manager {
+		mgr := &Manager{
+			log:       logr.Discard(),
+			client:    kube,
+			clientMap: make(map[clientKey]*clientVal),
+		}
+		return mgr.WithClientPool(pool)
+	}
+	get := func(mgr *Manager) {
+		t.Helper()
+		_, err := mgr.Get(ctx, esv1.SecretStoreRef{Name: "aws"}, "default", nil)
+		require.NoError(t, err)
+	}
+
+	// a closed client is reused by the next manager
+	first := newManager()
+	get(first)
+	require.NoError(t, first.Close(ctx))
+	second := newManager()
+	get(second)
+	assert.Len(t, created, 1)
+	assert.Equal(t, 1, created[0].resets)
+
+	// concurrent managers use their own clients
+	third := newManager()
+	get(third)
+	assert.Len(t, created, 2)
+	require.NoError(t, second.Close(ctx))
+	require.NoError(t, third.Close(ctx))
+
+	// changed credentials create a new client and close the stale ones
+	credentials.Data = map[string][]byte{"id": []byte("changed")}
+	require.NoError(t, kube.Update(ctx, credentials))
+	fourth := newManager()
+	get(fourth)
+	assert.Len(t, created, 3)
+	assert.True(t, created[0].closeCalled)
+	assert.True(t, created[1].closeCalled)
+
+	// a client which failed is closed instead of returned to the pool
+	created[2].err = errors.New("token expired")
+	secretClient, err := fourth.Get(ctx, esv1.SecretStoreRef{Name: "aws"}, "default", nil)
+	require.NoError(t, err)
+	_, err = secretClient.GetSecret(ctx, esv1.ExternalSecretDataRemoteRef{Key: "foo"})
+	assert.Error(t, err)
+	require.NoError(t, fourth.Close(ctx))
+	assert.True(t, created[2].closeCalled)
+	get(newManager())
+	assert.Len(t, created, 4)
+
+	// clients which are not poolable are not pooled
+	fakeProvider.newClientFunc = func(_ context.Context, _ esv1.GenericStore, _ client.Client, _ string) (esv1.SecretsClient, error) {
+		return &MockFakeClient{}, nil
+	}
+	store.Generation++
+	require.NoError(t, kube.Update(ctx, store))
+	fifth := newManager()
+	secretClient, err = fifth.Get(ctx, esv1.SecretStoreRef{Name: "aws"}, "default", nil)
+	require.NoError(t, err)
+	require.NoError(t, fifth.Close(ctx))
+	assert.True(t, unwrapRateLimit(secretClient).(*MockFakeClient).closeCalled)
+}
@@ -29,6 +29,7 @@ import (
 	esapi "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
 	ctrlmetrics "github.com/external-secrets/external-secrets/pkg/controllers/metrics"
 	"github.com/external-secrets/external-secrets/pkg/controllers/secretstore/cssmetrics"
+	"github.com/external-secrets/external-secrets/pkg/controllers/util"
 
 	// Loading registered providers.
 	_ "github.com/external-secrets/external-secrets/pkg/provider/register"
@@ -41,7 +42,9 @@ type ClusterStoreReconciler struct {
 	Scheme          *runtime.Scheme
 	ControllerClass string
 	RequeueInterval time.Duration
-	recorder        record.EventRecorder
+	// Schedule spreads the validation of stores over time, nil if disabled.
+	Schedule *util.Schedule
+	recorder record.EventRecorder
 }
 
 func (r *ClusterStoreReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -57,6 +60,7 @@ func (r *ClusterStoreReconciler) Reconcile(ctx context.Context, req ctrl.Request
 	err := r.Get(ctx, req.NamespacedName, &css)
 	if apierrors.IsNotFound(err) {
 		cssmetrics.RemoveMetrics(req.Namespace, req.Name)
+		removeRateLimiter(esapi.ClusterSecretStoreKind, req.Namespace, req.Name)
 		return ctrl.Result{}, nil
 	} else if err != nil {
 		log.Error(err, "unable to get ClusterSecretStore")
@@ -68,6 +72,7 @@ func (r *ClusterStoreReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		GaugeVecGetter:  cssmetrics.GetGaugeVec,
 		Recorder:        r.recorder,
 		RequeueInterval: r.RequeueInterval,
+		Schedule:        r.Schedule,
 	})
 }
 
@@ -28,6 +28,7 @@ import (
 
 	esapi "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
 	"github.com/external-secrets/external-secrets/pkg/controllers/secretstore/metrics"
+	"github.com/external-secrets/external-secrets/pkg/controllers/util"
 )
 
 const (
@@ -49,6 +50,8 @@ type Opts struct {
 	GaugeVecGetter  metrics.GaugeVevGetter
 	Recorder        record.EventRecorder
 	RequeueInterval time.Duration
+	// Schedule spreads the validation of stores over time, nil if disabled.
+	Schedule *util.Schedule
 }
 
 func reconcile(ctx context.Context, req ctrl.Request, ss esapi.GenericStore, cl client.Client, log logr.Logger, opts Opts) (ctrl.Result, error) {
@@ -62,6 +65,16 @@ func reconcile(ctx context.Context, req ctrl.Request, ss esapi.GenericStore, cl
 	if ss.GetSpec().RefreshInterval != 0 {
 		requeueInterval = time.Second * time.Duration(ss.GetSpec().RefreshInterval)
 	}
+	requeueInterval += opts.Schedule.Jitter(requeueInterval)
+
+	// stores which were validated before the controller started are validated again spread over the startup stagger window,
+	// new or changed stores are never postponed
+	if GetSecretStoreCondition(ss.GetStatus(), esapi.SecretStoreReady) != nil && !storeChanged(ss) {
+		if delay := opts.Schedule.Stagger(req.String()); delay > 0 {
+			log.V(1).Info("postponing validation", "delay", delay)
+			return ctrl.Result{RequeueAfter: delay}, nil
+		}
+	}
 
 	// patch status when done processing
 	p := client.MergeFrom(ss.Copy())
@@ -72,6 +85,10 @@ func reconcile(ctx context.Context, req ctrl.Request, ss esapi.GenericStore, cl
 		}
 	}()
 
+	status := ss.GetStatus()
+	status.ObservedGeneration = ss.GetGeneration()
+	ss.SetStatus(status)
+
 	// validateStore modifies the store conditions
 	// we have to patch the status
 	log.V(1).Info("validating")
@@ -102,8 +119,9 @@ func reconcile(ctx context.Context, req ctrl.Request, ss esapi.GenericStore, cl
 	}
 
 	capStatus := esapi.SecretStoreStatus{
-		Capabilities: storeProvider.Capabilities(),
-		Conditions:   ss.GetStatus().Conditions,
+		Capabilities:       storeProvider.Capabilities(),
+		Conditions:         ss.GetStatus().Conditions,
+		ObservedGeneration: ss.GetStatus().ObservedGeneration,
 	}
 	ss.SetStatus(capStatus)
 
@@ -116,6 +134,13 @@ func reconcile(ctx context.Context, req ctrl.Request, ss esapi.GenericStore, cl
 	}, err
 }
 
+// storeChanged reports whether the spec of the store changed since it was last validated.
+// Stores validated before the generation was recorded count as unchanged.
+func storeChanged(ss esapi.GenericStore) bool {
+	observed := ss.GetStatus().ObservedGeneration
+	return observed != 0 && observed != ss.GetGeneration()
+}
+
 // validateStore tries to construct a new client
 // if it fails sets a condition and writes events.
 func validateStore(ctx context.Context, namespace, controllerClass string, store esapi.GenericStore,
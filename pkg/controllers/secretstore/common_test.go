@@ -16,6 +16,7 @@ package secretstore
 
 import (
 	"context"
+	"testing"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
@@ -285,3 +286,27 @@ func hasEvent(involvedKind, name, reason string) bool {
 	}
 	return false
 }
+
+func TestStoreChanged(t *testing.T) {
+	tests := []struct {
+		name       string
+		generation int64
+		observed   int64
+		want       bool
+	}{
+		{name: "unchanged", generation: 2, observed: 2},
+		{name: "changed", generation: 3, observed: 2, want: true},
+		{name: "validated before the generation was recorded", generation: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &esapi.SecretStore{
+				ObjectMeta: metav1.ObjectMeta{Generation: tt.generation},
+				Status:     esapi.SecretStoreStatus{ObservedGeneration: tt.observed},
+			}
+			if got := storeChanged(store); got != tt.want {
+				t.Errorf("storeChanged() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretstore
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"syscall"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+)
+
+// unavailableMessages are matched against errors of providers which don't wrap
+// the transport error but only keep its message.
+var unavailableMessages = []string{
+	"connection refused",
+	"connection reset",
+	"no such host",
+	"i/o timeout",
+	"context deadline exceeded",
+	"tls handshake timeout",
+	strings.ToLower(http.StatusText(http.StatusInternalServerError)),
+	strings.ToLower(http.StatusText(http.StatusBadGateway)),
+	strings.ToLower(http.StatusText(http.StatusServiceUnavailable)),
+	strings.ToLower(http.StatusText(http.StatusGatewayTimeout)),
+}
+
+// isStoreUnavailable reports whether err means the store could not be reached
+// or failed on its side: transport errors, timeouts and 5xx responses.
+// Errors about the requested data, like a missing key or property,
+// and ErrThrottled are not, the next store would see the same request.
+func isStoreUnavailable(err error) bool {
+	if err == nil || errors.Is(err, ErrThrottled) || errors.Is(err, esv1.NoSecretErr) {
+		return false
+	}
+	if errors.Is(err, context.DeadlineExceeded) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNRESET) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	// e.g. the response errors of the AWS SDK
+	var httpErr interface{ HTTPStatusCode() int }
+	if errors.As(err, &httpErr) {
+		return httpErr.HTTPStatusCode() >= http.StatusInternalServerError
+	}
+	if s, ok := status.FromError(err); ok {
+		return s.Code() == codes.Unavailable || s.Code() == codes.DeadlineExceeded || s.Code() == codes.Internal
+	}
+	if apierrors.IsServerTimeout(err) || apierrors.IsTimeout(err) ||
+		apierrors.IsServiceUnavailable(err) || apierrors.IsInternalError(err) {
+		return true
+	}
+	msg := strings.ToLower(err.Error())
+	for _, m := range unavailableMessages {
+		if strings.Contains(msg, m) {
+			return true
+		}
+	}
+	return false
+}
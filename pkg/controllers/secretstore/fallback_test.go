@@ -0,0 +1,74 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"syscall"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+)
+
+type httpStatusError int
+
+func (e httpStatusError) Error() string {
+	return fmt.Sprintf("status %d", int(e))
+}
+
+func (e httpStatusError) HTTPStatusCode() int {
+	return int(e)
+}
+
+func TestIsStoreUnavailable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "nil", err: nil, want: false},
+		{name: "missing secret", err: esv1.NoSecretErr, want: false},
+		{name: "throttled", err: fmt.Errorf("%w: SecretStore %q allows 1 calls per second", ErrThrottled, "test"), want: false},
+		{name: "missing key", err: errors.New("key foo does not exist in secret bar"), want: false},
+		{name: "invalid property", err: errors.New("property foo.bar does not exist"), want: false},
+		{name: "deadline", err: fmt.Errorf("could not get secret: %w", context.DeadlineExceeded), want: true},
+		{name: "connection refused", err: fmt.Errorf("could not get secret: %w", syscall.ECONNREFUSED), want: true},
+		{name: "net error", err: &net.OpError{Op: "dial", Err: errors.New("unreachable")}, want: true},
+		{name: "http 503", err: fmt.Errorf("could not get secret: %w", httpStatusError(503)), want: true},
+		{name: "http 404", err: fmt.Errorf("could not get secret: %w", httpStatusError(404)), want: false},
+		{name: "http 403", err: httpStatusError(403), want: false},
+		{name: "grpc unavailable", err: status.Error(codes.Unavailable, "unavailable"), want: true},
+		{name: "grpc not found", err: status.Error(codes.NotFound, "not found"), want: false},
+		{name: "kubernetes timeout", err: apierrors.NewServerTimeout(schema.GroupResource{Resource: "secrets"}, "get", 1), want: true},
+		{name: "kubernetes forbidden", err: apierrors.NewForbidden(schema.GroupResource{Resource: "secrets"}, "foo", errors.New("denied")), want: false},
+		{name: "flattened transport error", err: errors.New("dial tcp 10.0.0.1:443: connect: connection refused"), want: true},
+		{name: "flattened 5xx", err: errors.New("unexpected response: 502 Bad Gateway"), want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isStoreUnavailable(tt.err); got != tt.want {
+				t.Errorf("isStoreUnavailable(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,267 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretstore
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+	"golang.org/x/time/rate"
+	corev1 "k8s.io/api/core/v1"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	"github.com/external-secrets/external-secrets/pkg/metrics"
+	"github.com/external-secrets/external-secrets/pkg/utils"
+)
+
+const (
+	defaultRateLimitMaxWait = 10 * time.Second
+
+	callGetAllSecrets = "GetAllSecrets"
+	callPushSecret    = "PushSecret"
+	callDeleteSecret  = "DeleteSecret"
+	callSecretExists  = "SecretExists"
+)
+
+// ErrThrottled is returned by calls exceeding the rate limit of a store.
+var ErrThrottled = errors.New("rate limit of the store exceeded")
+
+var (
+	// rate limiters are shared by all clients of a store.
+	rateLimitersMu sync.Mutex
+	rateLimiters   = map[string]*storeRateLimiter{}
+
+	// inFlight coalesces identical reads of all clients of a store.
+	inFlight singleflight.Group
+)
+
+type storeRateLimiter struct {
+	qps     int32
+	burst   int32
+	limiter *rate.Limiter
+}
+
+// rateLimiterFor returns the rate limiter of a store.
+// It is replaced when the rate limit of the store changes.
+func rateLimiterFor(store esv1.GenericStore, spec *esv1.SecretStoreRateLimit) *rate.Limiter {
+	burst := spec.Burst
+	if burst <= 0 {
+		burst = spec.QPS
+	}
+	key := rateLimiterKey(store.GetKind(), store.GetNamespace(), store.GetName())
+
+	rateLimitersMu.Lock()
+	defer rateLimitersMu.Unlock()
+	if l, ok := rateLimiters[key]; ok && l.qps == spec.QPS && l.burst == burst {
+		return l.limiter
+	}
+	l := &storeRateLimiter{
+		qps:     spec.QPS,
+		burst:   burst,
+		limiter: rate.NewLimiter(rate.Limit(spec.QPS), int(burst)),
+	}
+	rateLimiters[key] = l
+	return l.limiter
+}
+
+// removeRateLimiter removes the rate limiter of a deleted store.
+func removeRateLimiter(kind, namespace, name string) {
+	rateLimitersMu.Lock()
+	defer rateLimitersMu.Unlock()
+	delete(rateLimiters, rateLimiterKey(kind, namespace, name))
+}
+
+func rateLimiterKey(kind, namespace, name string) string {
+	return fmt.Sprintf("%s/%s/%s", kind, namespace, name)
+}
+
+// withRateLimit returns a client coalescing identical reads of all clients of the store,
+// which also enforces the rate limit of the store if it has one.
+func withRateLimit(secretClient esv1.SecretsClient, store esv1.GenericStore, namespace string) esv1.SecretsClient {
+	rc := &rateLimitedClient{
+		SecretsClient: secretClient,
+		store:         store,
+		namespace:     namespace,
+	}
+	if spec := store.GetSpec().RateLimit; spec != nil && spec.QPS > 0 {
+		rc.limiter = rateLimiterFor(store, spec)
+		rc.maxWait = defaultRateLimitMaxWait
+		if spec.MaxWait != nil {
+			rc.maxWait = spec.MaxWait.Duration
+		}
+	}
+	if _, ok := secretClient.(esv1.BatchSecretsClient); ok {
+		return &batchRateLimitedClient{rc}
+	}
+	return rc
+}
+
+// rateLimitedClient decorates a SecretsClient with the rate limit of its store.
+// Identical GetSecret and GetSecretMap calls in flight at the same time share a single call.
+type rateLimitedClient struct {
+	esv1.SecretsClient
+	// limiter is nil if the store has no rate limit
+	limiter   *rate.Limiter
+	maxWait   time.Duration
+	store     esv1.GenericStore
+	namespace string
+}
+
+func (c *rateLimitedClient) GetSecret(ctx context.Context, ref esv1.ExternalSecretDataRemoteRef) ([]byte, error) {
+	secret, _, err := c.GetSecretWithMetadata(ctx, ref)
+	return secret, err
+}
+
+// GetSecretWithMetadata shares a single call with identical GetSecret calls,
+// which return the same value.
+func (c *rateLimitedClient) GetSecretWithMetadata(ctx context.Context, ref esv1.ExternalSecretDataRemoteRef) ([]byte, *esv1.SecretMetadata, error) {
+	v, err := c.share(ctx, c.flightKey(callGetSecret, ref), func(ctx context.Context) (any, error) {
+		if err := c.wait(ctx, callGetSecret); err != nil {
+			return nil, err
+		}
+		secret, metadata, err := esv1.GetSecretWithMetadata(ctx, c.SecretsClient, ref)
+		return cachedResponse{secret: secret, metadata: metadata}, err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	resp := v.(cachedResponse)
+	return bytes.Clone(resp.secret), cloneMetadata(resp.metadata), nil
+}
+
+func (c *rateLimitedClient) GetSecretMap(ctx context.Context, ref esv1.ExternalSecretDataRemoteRef) (map[string][]byte, error) {
+	v, err := c.share(ctx, c.flightKey(callGetSecretMap, ref), func(ctx context.Context) (any, error) {
+		if err := c.wait(ctx, callGetSecretMap); err != nil {
+			return nil, err
+		}
+		return c.SecretsClient.GetSecretMap(ctx, ref)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return cloneSecretMap(v.(map[string][]byte)), nil
+}
+
+func (c *rateLimitedClient) GetAllSecrets(ctx context.Context, ref esv1.ExternalSecretFind) (map[string][]byte, error) {
+	if err := c.wait(ctx, callGetAllSecrets); err != nil {
+		return nil, err
+	}
+	return c.SecretsClient.GetAllSecrets(ctx, ref)
+}
+
+func (c *rateLimitedClient) PushSecret(ctx context.Context, secret *corev1.Secret, data esv1.PushSecretData) error {
+	if err := c.wait(ctx, callPushSecret); err != nil {
+		return err
+	}
+	return c.SecretsClient.PushSecret(ctx, secret, data)
+}
+
+func (c *rateLimitedClient) DeleteSecret(ctx context.Context, remoteRef esv1.PushSecretRemoteRef) error {
+	if err := c.wait(ctx, callDeleteSecret); err != nil {
+		return err
+	}
+	return c.SecretsClient.DeleteSecret(ctx, remoteRef)
+}
+
+func (c *rateLimitedClient) SecretExists(ctx context.Context, remoteRef esv1.PushSecretRemoteRef) (bool, error) {
+	if err := c.wait(ctx, callSecretExists); err != nil {
+		return false, err
+	}
+	return c.SecretsClient.SecretExists(ctx, remoteRef)
+}
+
+// batchRateLimitedClient is a rateLimitedClient of a client which fetches secrets in batches.
+type batchRateLimitedClient struct {
+	*rateLimitedClient
+}
+
+// GetSecrets counts as a single call against the rate limit, batches are not coalesced.
+func (c *batchRateLimitedClient) GetSecrets(ctx context.Context, refs []esv1.ExternalSecretDataRemoteRef) ([][]byte, []error, error) {
+	secrets, _, errs, err := c.GetSecretsWithMetadata(ctx, refs)
+	return secrets, errs, err
+}
+
+func (c *batchRateLimitedClient) GetSecretsWithMetadata(ctx context.Context, refs []esv1.ExternalSecretDataRemoteRef) ([][]byte, []*esv1.SecretMetadata, []error, error) {
+	if err := c.wait(ctx, callGetSecrets); err != nil {
+		return nil, nil, nil, err
+	}
+	return esv1.GetSecretsWithMetadata(ctx, c.SecretsClient.(esv1.BatchSecretsClient), refs)
+}
+
+// share runs fn once for all identical calls in flight.
+// fn runs with the client and the context of the caller which started it, as the client is closed once that caller is done.
+// If that caller is canceled, the others start the call again, while every caller stops waiting once its own context is done.
+func (c *rateLimitedClient) share(ctx context.Context, key string, fn func(context.Context) (any, error)) (any, error) {
+	for {
+		ch := inFlight.DoChan(key, func() (any, error) {
+			v, err := fn(ctx)
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				// the result may be caused by the closed client, it is never shared
+				return nil, ctxErr
+			}
+			return v, err
+		})
+		select {
+		case res := <-ch:
+			if res.Err != nil && ctx.Err() == nil && (errors.Is(res.Err, context.Canceled) || errors.Is(res.Err, context.DeadlineExceeded)) {
+				// the caller which started the call is done, but this one is not
+				continue
+			}
+			return res.Val, res.Err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// wait blocks until the rate limit allows a call.
+// It fails right away with ErrThrottled if that takes longer than maxWait,
+// so reconciles don't block the workers of the controller.
+func (c *rateLimitedClient) wait(ctx context.Context, call string) error {
+	if c.limiter == nil {
+		return nil
+	}
+	r := c.limiter.Reserve()
+	delay := r.Delay()
+	if delay == 0 {
+		return nil
+	}
+	if delay > c.maxWait {
+		r.Cancel()
+		metrics.ObserveThrottledCall(c.store.GetKind(), c.store.GetName(), c.store.GetNamespace(), call, true)
+		return fmt.Errorf("%w: %s %q allows %v calls per second", ErrThrottled, c.store.GetKind(), c.store.GetName(), c.limiter.Limit())
+	}
+	metrics.ObserveThrottledCall(c.store.GetKind(), c.store.GetName(), c.store.GetNamespace(), call, false)
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		r.Cancel()
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// flightKey identifies identical calls, which includes the namespace,
+// as the credentials of a ClusterSecretStore may depend on the namespace of the ExternalSecret.
+func (c *rateLimitedClient) flightKey(call string, ref esv1.ExternalSecretDataRemoteRef) string {
+	return fmt.Sprintf("%s/%s/%s/%s/%s/%s", c.store.GetKind(), c.store.GetNamespace(), c.store.GetName(), c.namespace, call, utils.ObjectHash(ref))
+}
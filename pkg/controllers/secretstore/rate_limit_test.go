@@ -0,0 +1,173 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretstore
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+)
+
+type blockingClient struct {
+	MockFakeClient
+	calls   atomic.Int32
+	release chan struct{}
+}
+
+func (c *blockingClient) GetSecret(ctx context.Context, _ esv1.ExternalSecretDataRemoteRef) ([]byte, error) {
+	c.calls.Add(1)
+	select {
+	case <-c.release:
+		return []byte("value"), nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// unwrapRateLimit returns the client decorated by withRateLimit.
+func unwrapRateLimit(secretClient esv1.SecretsClient) esv1.SecretsClient {
+	switch c := secretClient.(type) {
+	case *rateLimitedClient:
+		return c.SecretsClient
+	case *batchRateLimitedClient:
+		return c.SecretsClient
+	}
+	return secretClient
+}
+
+func newRateLimitedStore(name string, rateLimit *esv1.SecretStoreRateLimit) *esv1.SecretStore {
+	return &esv1.SecretStore{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec: esv1.SecretStoreSpec{
+			Provider:  &esv1.SecretStoreProvider{AWS: &esv1.AWSProvider{}},
+			RateLimit: rateLimit,
+		},
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	ctx := context.Background()
+	store := newRateLimitedStore("throttled", &esv1.SecretStoreRateLimit{QPS: 1, MaxWait: &metav1.Duration{}})
+	upstream := &countingClient{}
+	ref := esv1.ExternalSecretDataRemoteRef{Key: "db"}
+
+	// the burst defaults to qps, the limit is shared by all clients of the store
+	_, err := withRateLimit(upstream, store, "default").GetSecret(ctx, ref)
+	require.NoError(t, err)
+	_, err = withRateLimit(upstream, store, "default").GetSecret(ctx, ref)
+	assert.ErrorIs(t, err, ErrThrottled)
+	assert.Equal(t, 1, upstream.calls)
+
+	// a changed rate limit replaces the limiter
+	store.Spec.RateLimit = &esv1.SecretStoreRateLimit{QPS: 1, Burst: 2, MaxWait: &metav1.Duration{}}
+	_, err = withRateLimit(upstream, store, "default").GetSecret(ctx, ref)
+	require.NoError(t, err)
+	assert.Equal(t, 2, upstream.calls)
+
+	// calls wait up to maxWait
+	store = newRateLimitedStore("waiting", &esv1.SecretStoreRateLimit{QPS: 20, Burst: 1, MaxWait: &metav1.Duration{Duration: time.Second}})
+	for range 2 {
+		_, err = withRateLimit(upstream, store, "default").GetSecret(ctx, ref)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 4, upstream.calls)
+
+	// stores without a rate limit are not limited
+	store = newRateLimitedStore("unlimited", nil)
+	for range 3 {
+		_, err = withRateLimit(upstream, store, "default").GetSecret(ctx, ref)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 7, upstream.calls)
+
+	// the rate limiter of a deleted store is removed
+	removeRateLimiter(esv1.SecretStoreKind, "default", "throttled")
+	rateLimitersMu.Lock()
+	assert.NotContains(t, rateLimiters, rateLimiterKey(esv1.SecretStoreKind, "default", "throttled"))
+	rateLimitersMu.Unlock()
+}
+
+func TestRateLimitCoalescing(t *testing.T) {
+	ctx := context.Background()
+	ref := esv1.ExternalSecretDataRemoteRef{Key: "db"}
+
+	// identical calls are coalesced with and without a rate limit
+	for _, store := range []*esv1.SecretStore{
+		newRateLimitedStore("coalescing", &esv1.SecretStoreRateLimit{QPS: 100}),
+		newRateLimitedStore("coalescing-unlimited", nil),
+	} {
+		upstream := &blockingClient{release: make(chan struct{})}
+		var wg sync.WaitGroup
+		results := make([][]byte, 5)
+		for i := range results {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				secret, err := withRateLimit(upstream, store, "default").GetSecret(ctx, ref)
+				assert.NoError(t, err)
+				results[i] = secret
+			}()
+		}
+		// wait for the first call to be in flight, and give the others time to join it
+		require.Eventually(t, func() bool { return upstream.calls.Load() == 1 }, time.Second, time.Millisecond)
+		time.Sleep(50 * time.Millisecond)
+		close(upstream.release)
+		wg.Wait()
+
+		assert.Equal(t, int32(1), upstream.calls.Load(), store.Name)
+		for _, secret := range results {
+			assert.Equal(t, "value", string(secret), store.Name)
+		}
+	}
+}
+
+func TestRateLimitCoalescingCanceledCaller(t *testing.T) {
+	store := newRateLimitedStore("coalescing-canceled", nil)
+	upstream := &blockingClient{release: make(chan struct{})}
+	ref := esv1.ExternalSecretDataRemoteRef{Key: "db"}
+
+	// the caller starting the shared call is canceled
+	firstCtx, cancel := context.WithCancel(context.Background())
+	firstErr := make(chan error, 1)
+	go func() {
+		_, err := withRateLimit(upstream, store, "default").GetSecret(firstCtx, ref)
+		firstErr <- err
+	}()
+	require.Eventually(t, func() bool { return upstream.calls.Load() == 1 }, time.Second, time.Millisecond)
+
+	second := make(chan []byte, 1)
+	go func() {
+		secret, err := withRateLimit(upstream, store, "default").GetSecret(context.Background(), ref)
+		assert.NoError(t, err)
+		second <- secret
+	}()
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	assert.ErrorIs(t, <-firstErr, context.Canceled)
+
+	// the other callers start the call again with their own client
+	require.Eventually(t, func() bool { return upstream.calls.Load() == 2 }, time.Second, time.Millisecond)
+	close(upstream.release)
+	assert.Equal(t, "value", string(<-second))
+	assert.Equal(t, int32(2), upstream.calls.Load())
+}
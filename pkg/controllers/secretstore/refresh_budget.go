@@ -0,0 +1,85 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretstore
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+)
+
+var (
+	// refresh budgets are shared by all ExternalSecrets of a store.
+	refreshBudgetsMu sync.Mutex
+	refreshBudgets   = map[string]*refreshBudget{}
+)
+
+// refreshBudget hands out the refreshes of a store at an even pace.
+// Refreshes which have to wait get a reserved slot, so they are let through when they come back at that time.
+type refreshBudget struct {
+	perMinute int32
+	limiter   *rate.Limiter
+	reserved  map[string]time.Time
+}
+
+// ReserveRefresh takes a periodic refresh of the resource with the given key from the refresh budget of the store.
+// It returns how long the refresh has to be postponed, which is zero if the store has no refresh budget.
+// A postponed refresh keeps its slot, so calling ReserveRefresh again at that time lets it through.
+func ReserveRefresh(store esv1.GenericStore, key string) time.Duration {
+	return reserveRefresh(store, key, time.Now())
+}
+
+func reserveRefresh(store esv1.GenericStore, key string, now time.Time) time.Duration {
+	spec := store.GetSpec().RefreshBudget
+	if spec == nil || spec.RefreshesPerMinute <= 0 {
+		return 0
+	}
+	storeKey := fmt.Sprintf("%s/%s/%s", store.GetKind(), store.GetNamespace(), store.GetName())
+
+	refreshBudgetsMu.Lock()
+	defer refreshBudgetsMu.Unlock()
+	b, ok := refreshBudgets[storeKey]
+	if !ok || b.perMinute != spec.RefreshesPerMinute {
+		b = &refreshBudget{
+			perMinute: spec.RefreshesPerMinute,
+			limiter:   rate.NewLimiter(rate.Every(time.Minute/time.Duration(spec.RefreshesPerMinute)), 1),
+			reserved:  map[string]time.Time{},
+		}
+		refreshBudgets[storeKey] = b
+	}
+
+	if at, ok := b.reserved[key]; ok {
+		if now.Before(at) {
+			return at.Sub(now)
+		}
+		delete(b.reserved, key)
+		return 0
+	}
+	// slots of resources which never came back, e.g. because they were deleted, are dropped
+	for k, at := range b.reserved {
+		if now.Sub(at) > time.Minute {
+			delete(b.reserved, k)
+		}
+	}
+	delay := b.limiter.ReserveN(now, 1).DelayFrom(now)
+	if delay > 0 {
+		b.reserved[key] = now.Add(delay)
+	}
+	return delay
+}
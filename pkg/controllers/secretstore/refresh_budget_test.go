@@ -0,0 +1,51 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretstore
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+)
+
+func TestReserveRefresh(t *testing.T) {
+	store := &esv1.SecretStore{
+		ObjectMeta: metav1.ObjectMeta{Name: "budgeted", Namespace: "default"},
+		Spec: esv1.SecretStoreSpec{
+			Provider:      &esv1.SecretStoreProvider{AWS: &esv1.AWSProvider{}},
+			RefreshBudget: &esv1.SecretStoreRefreshBudget{RefreshesPerMinute: 6},
+		},
+	}
+	now := time.Now()
+
+	// refreshes are spread evenly over the minute
+	assert.Zero(t, reserveRefresh(store, "default/a", now))
+	assert.Equal(t, 10*time.Second, reserveRefresh(store, "default/b", now))
+	assert.Equal(t, 20*time.Second, reserveRefresh(store, "default/c", now))
+
+	// a postponed refresh keeps its slot
+	assert.Equal(t, 5*time.Second, reserveRefresh(store, "default/b", now.Add(5*time.Second)))
+	assert.Zero(t, reserveRefresh(store, "default/b", now.Add(10*time.Second)))
+	assert.Zero(t, reserveRefresh(store, "default/c", now.Add(20*time.Second)))
+	assert.Equal(t, 10*time.Second, reserveRefresh(store, "default/a", now.Add(20*time.Second)))
+
+	// stores without a refresh budget don't postpone refreshes
+	store.Spec.RefreshBudget = nil
+	assert.Zero(t, reserveRefresh(store, "default/d", now))
+}
@@ -0,0 +1,235 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretstore
+
+import (
+	"bytes"
+	"context"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	"github.com/external-secrets/external-secrets/pkg/cache"
+	"github.com/external-secrets/external-secrets/pkg/metrics"
+	"github.com/external-secrets/external-secrets/pkg/utils"
+)
+
+const (
+	callGetSecret    = "GetSecret"
+	callGetSecrets   = "GetSecrets"
+	callGetSecretMap = "GetSecretMap"
+)
+
+// ResponseCache caches the responses of providers across reconciles,
+// so ExternalSecrets reading the same remote ref don't each call the provider.
+// Responses are cached per store, namespace and remote ref (including version and property).
+// They are versioned by the generation of the store and the resourceVersion of the Secrets and ConfigMaps
+// it references, so a changed store or changed credentials invalidate its responses.
+// Errors are never cached.
+type ResponseCache struct {
+	cache *cache.Cache[cachedResponse]
+	ttl   time.Duration
+}
+
+type cachedResponse struct {
+	remoteKey string
+	secret    []byte
+	metadata  *esv1.SecretMetadata
+	secretMap map[string][]byte
+}
+
+// NewResponseCache creates a cache holding up to size responses.
+// ttl applies to stores which don't configure spec.responseCache.ttl.
+func NewResponseCache(size int, ttl time.Duration) (*ResponseCache, error) {
+	c, err := cache.New[cachedResponse](size, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &ResponseCache{cache: c, ttl: ttl}, nil
+}
+
+// Evict removes the responses of the given remote keys of all stores,
+// and returns the number of removed responses.
+func (c *ResponseCache) Evict(keys []string) int {
+	if c == nil || len(keys) == 0 {
+		return 0
+	}
+	return c.cache.RemoveFunc(func(_ cache.Key, cached cachedResponse) bool {
+		return slices.Contains(keys, cached.remoteKey)
+	})
+}
+
+// wrap returns a client serving GetSecret and GetSecretMap from the cache,
+// or the client itself if caching is disabled for the store.
+// With refresh, responses are not served from the cache but still replace the cached ones.
+func (c *ResponseCache) wrap(ctx context.Context, kube client.Client, secretClient esv1.SecretsClient, store esv1.GenericStore, namespace string, refresh bool) esv1.SecretsClient {
+	if c == nil {
+		return secretClient
+	}
+	ttl := c.ttl
+	if spec := store.GetSpec().ResponseCache; spec != nil && spec.TTL != nil {
+		ttl = spec.TTL.Duration
+	}
+	if ttl <= 0 {
+		return secretClient
+	}
+	versions, err := referenceVersions(ctx, kube, store, namespace)
+	if err != nil {
+		// the client reports the error once it is used
+		return secretClient
+	}
+	cc := &cachingClient{
+		SecretsClient: secretClient,
+		cache:         c.cache,
+		ttl:           ttl,
+		version:       strings.Join(append([]string{strconv.FormatInt(store.GetGeneration(), 10)}, versions...), ","),
+		refresh:       refresh,
+		store:         store,
+		namespace:     namespace,
+	}
+	if _, ok := secretClient.(esv1.BatchSecretsClient); ok {
+		return &batchCachingClient{cc}
+	}
+	return cc
+}
+
+// cachingClient decorates a SecretsClient with the response cache.
+type cachingClient struct {
+	esv1.SecretsClient
+	cache     *cache.Cache[cachedResponse]
+	ttl       time.Duration
+	version   string
+	refresh   bool
+	store     esv1.GenericStore
+	namespace string
+}
+
+func (c *cachingClient) GetSecret(ctx context.Context, ref esv1.ExternalSecretDataRemoteRef) ([]byte, error) {
+	secret, _, err := c.GetSecretWithMetadata(ctx, ref)
+	return secret, err
+}
+
+// GetSecretWithMetadata shares its responses with GetSecret and GetSecrets.
+func (c *cachingClient) GetSecretWithMetadata(ctx context.Context, ref esv1.ExternalSecretDataRemoteRef) ([]byte, *esv1.SecretMetadata, error) {
+	key := c.key(callGetSecret, ref)
+	if cached, ok := c.get(callGetSecret, key); ok {
+		return bytes.Clone(cached.secret), cloneMetadata(cached.metadata), nil
+	}
+	secret, metadata, err := esv1.GetSecretWithMetadata(ctx, c.SecretsClient, ref)
+	if err != nil {
+		return nil, nil, err
+	}
+	c.cache.AddWithTTL(c.version, key, cachedResponse{remoteKey: ref.Key, secret: bytes.Clone(secret), metadata: cloneMetadata(metadata)}, c.ttl)
+	return secret, metadata, nil
+}
+
+func (c *cachingClient) GetSecretMap(ctx context.Context, ref esv1.ExternalSecretDataRemoteRef) (map[string][]byte, error) {
+	key := c.key(callGetSecretMap, ref)
+	if cached, ok := c.get(callGetSecretMap, key); ok {
+		return cloneSecretMap(cached.secretMap), nil
+	}
+	secretMap, err := c.SecretsClient.GetSecretMap(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	c.cache.AddWithTTL(c.version, key, cachedResponse{remoteKey: ref.Key, secretMap: cloneSecretMap(secretMap)}, c.ttl)
+	return secretMap, nil
+}
+
+// batchCachingClient is a cachingClient of a client which fetches secrets in batches.
+type batchCachingClient struct {
+	*cachingClient
+}
+
+func (c *batchCachingClient) GetSecrets(ctx context.Context, refs []esv1.ExternalSecretDataRemoteRef) ([][]byte, []error, error) {
+	secrets, _, errs, err := c.GetSecretsWithMetadata(ctx, refs)
+	return secrets, errs, err
+}
+
+// GetSecretsWithMetadata serves the refs it can from the cache and fetches the others in a single batch.
+// Its responses are shared with GetSecret.
+func (c *batchCachingClient) GetSecretsWithMetadata(ctx context.Context, refs []esv1.ExternalSecretDataRemoteRef) ([][]byte, []*esv1.SecretMetadata, []error, error) {
+	secrets := make([][]byte, len(refs))
+	metadata := make([]*esv1.SecretMetadata, len(refs))
+	errs := make([]error, len(refs))
+	var missing []int
+	var missingRefs []esv1.ExternalSecretDataRemoteRef
+	for i, ref := range refs {
+		if cached, ok := c.get(callGetSecret, c.key(callGetSecret, ref)); ok {
+			secrets[i], metadata[i] = bytes.Clone(cached.secret), cloneMetadata(cached.metadata)
+			continue
+		}
+		missing = append(missing, i)
+		missingRefs = append(missingRefs, ref)
+	}
+	if len(missing) == 0 {
+		return secrets, metadata, errs, nil
+	}
+	fetched, fetchedMetadata, fetchErrs, err := esv1.GetSecretsWithMetadata(ctx, c.SecretsClient.(esv1.BatchSecretsClient), missingRefs)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	for j, i := range missing {
+		secrets[i], metadata[i], errs[i] = fetched[j], fetchedMetadata[j], fetchErrs[j]
+		if errs[i] == nil {
+			c.cache.AddWithTTL(c.version, c.key(callGetSecret, refs[i]), cachedResponse{remoteKey: refs[i].Key, secret: bytes.Clone(secrets[i]), metadata: cloneMetadata(metadata[i])}, c.ttl)
+		}
+	}
+	return secrets, metadata, errs, nil
+}
+
+func (c *cachingClient) get(call string, key cache.Key) (cachedResponse, bool) {
+	if c.refresh {
+		return cachedResponse{}, false
+	}
+	cached, ok := c.cache.Get(c.version, key)
+	metrics.ObserveResponseCache(c.store.GetKind(), c.store.GetName(), c.store.GetNamespace(), call, ok)
+	return cached, ok
+}
+
+// key identifies a response. The namespace is part of the key,
+// as the credentials of a ClusterSecretStore may depend on the namespace of the ExternalSecret.
+func (c *cachingClient) key(call string, ref esv1.ExternalSecretDataRemoteRef) cache.Key {
+	return cache.Key{
+		Kind:      c.store.GetKind(),
+		Name:      c.store.GetName(),
+		Namespace: c.namespace,
+		Subkey:    call + "/" + utils.ObjectHash(ref),
+	}
+}
+
+func cloneSecretMap(secretMap map[string][]byte) map[string][]byte {
+	if secretMap == nil {
+		return nil
+	}
+	clone := maps.Clone(secretMap)
+	for k, v := range clone {
+		clone[k] = bytes.Clone(v)
+	}
+	return clone
+}
+
+func cloneMetadata(metadata *esv1.SecretMetadata) *esv1.SecretMetadata {
+	if metadata == nil {
+		return nil
+	}
+	clone := *metadata
+	return &clone
+}
@@ -0,0 +1,210 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretstore
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	esmeta "github.com/external-secrets/external-secrets/apis/meta/v1"
+)
+
+type countingClient struct {
+	MockFakeClient
+	calls int
+	err   error
+}
+
+func (c *countingClient) GetSecret(_ context.Context, ref esv1.ExternalSecretDataRemoteRef) ([]byte, error) {
+	c.calls++
+	if c.err != nil {
+		return nil, c.err
+	}
+	return []byte(ref.Key + ref.Version), nil
+}
+
+func (c *countingClient) GetSecretMap(_ context.Context, ref esv1.ExternalSecretDataRemoteRef) (map[string][]byte, error) {
+	c.calls++
+	return map[string][]byte{ref.Key: []byte("value")}, nil
+}
+
+type batchClient struct {
+	countingClient
+	batches [][]esv1.ExternalSecretDataRemoteRef
+}
+
+func (c *batchClient) GetSecrets(ctx context.Context, refs []esv1.ExternalSecretDataRemoteRef) ([][]byte, []error, error) {
+	c.batches = append(c.batches, refs)
+	secrets := make([][]byte, len(refs))
+	errs := make([]error, len(refs))
+	for i, ref := range refs {
+		if ref.Key == "missing" {
+			errs[i] = esv1.NoSecretErr
+			continue
+		}
+		secrets[i], errs[i] = c.countingClient.GetSecret(ctx, ref)
+	}
+	return secrets, errs, nil
+}
+
+func TestResponseCache(t *testing.T) {
+	ctx := context.Background()
+	rc, err := NewResponseCache(10, time.Hour)
+	require.NoError(t, err)
+	store := &esv1.SecretStore{
+		ObjectMeta: metav1.ObjectMeta{Name: "store", Namespace: "default", Generation: 1},
+		Spec:       esv1.SecretStoreSpec{Provider: &esv1.SecretStoreProvider{AWS: &esv1.AWSProvider{}}},
+	}
+	upstream := &countingClient{}
+	ref := esv1.ExternalSecretDataRemoteRef{Key: "db"}
+
+	// identical calls are served from the cache, also by other clients of the same store
+	for range 2 {
+		secret, err := rc.wrap(ctx, nil, upstream, store, "default", false).GetSecret(ctx, ref)
+		require.NoError(t, err)
+		assert.Equal(t, "db", string(secret))
+	}
+	assert.Equal(t, 1, upstream.calls)
+
+	// version, property and call are part of the key
+	_, err = rc.wrap(ctx, nil, upstream, store, "default", false).GetSecret(ctx, esv1.ExternalSecretDataRemoteRef{Key: "db", Version: "2"})
+	require.NoError(t, err)
+	_, err = rc.wrap(ctx, nil, upstream, store, "default", false).GetSecret(ctx, esv1.ExternalSecretDataRemoteRef{Key: "db", Property: "password"})
+	require.NoError(t, err)
+	secretMap, err := rc.wrap(ctx, nil, upstream, store, "default", false).GetSecretMap(ctx, ref)
+	require.NoError(t, err)
+	assert.Equal(t, 4, upstream.calls)
+
+	// cached responses can't be modified by callers
+	secretMap["db"][0] = 'X'
+	secretMap, err = rc.wrap(ctx, nil, upstream, store, "default", false).GetSecretMap(ctx, ref)
+	require.NoError(t, err)
+	assert.Equal(t, "value", string(secretMap["db"]))
+	assert.Equal(t, 4, upstream.calls)
+
+	// a changed store invalidates its responses
+	store.Generation = 2
+	_, err = rc.wrap(ctx, nil, upstream, store, "default", false).GetSecret(ctx, ref)
+	require.NoError(t, err)
+	assert.Equal(t, 5, upstream.calls)
+
+	// errors are not cached
+	failing := &countingClient{err: errors.New("boom")}
+	for range 2 {
+		_, err = rc.wrap(ctx, nil, failing, store, "other", false).GetSecret(ctx, ref)
+		assert.Error(t, err)
+	}
+	assert.Equal(t, 2, failing.calls)
+
+	// stores can disable caching
+	store.Spec.ResponseCache = &esv1.SecretStoreResponseCache{TTL: &metav1.Duration{}}
+	assert.Same(t, upstream, rc.wrap(ctx, nil, upstream, store, "default", false))
+
+	// and so does a nil cache
+	var disabled *ResponseCache
+	assert.Same(t, upstream, disabled.wrap(ctx, nil, upstream, store, "default", false))
+}
+
+func TestResponseCacheInvalidation(t *testing.T) {
+	ctx := context.Background()
+	rc, err := NewResponseCache(10, time.Hour)
+	require.NoError(t, err)
+	store := &esv1.SecretStore{
+		ObjectMeta: metav1.ObjectMeta{Name: "store", Namespace: "default", Generation: 1},
+		Spec: esv1.SecretStoreSpec{Provider: &esv1.SecretStoreProvider{AWS: &esv1.AWSProvider{
+			Auth: esv1.AWSAuth{SecretRef: &esv1.AWSAuthSecretRef{
+				AccessKeyID: esmeta.SecretKeySelector{Name: "credentials", Key: "id"},
+			}},
+		}}},
+	}
+	credentials := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "credentials", Namespace: "default"}}
+	kube := fakeclient.NewClientBuilder().WithObjects(credentials).Build()
+	upstream := &countingClient{}
+	ref := esv1.ExternalSecretDataRemoteRef{Key: "db"}
+	get := func(refresh bool) {
+		_, err := rc.wrap(ctx, kube, upstream, store, "default", refresh).GetSecret(ctx, ref)
+		require.NoError(t, err)
+	}
+
+	get(false)
+	get(false)
+	assert.Equal(t, 1, upstream.calls)
+
+	// a refresh is fetched from the provider and replaces the cached response
+	get(true)
+	assert.Equal(t, 2, upstream.calls)
+	get(false)
+	assert.Equal(t, 2, upstream.calls)
+
+	// changed credentials invalidate the responses of the store
+	credentials.Data = map[string][]byte{"id": []byte("rotated")}
+	require.NoError(t, kube.Update(ctx, credentials))
+	get(false)
+	assert.Equal(t, 3, upstream.calls)
+
+	// evicted remote keys are fetched again
+	assert.Equal(t, 0, rc.Evict([]string{"other"}))
+	assert.Equal(t, 1, rc.Evict([]string{"db"}))
+	get(false)
+	assert.Equal(t, 4, upstream.calls)
+
+	// the client is not cached if a referenced Secret can't be read
+	require.NoError(t, kube.Delete(ctx, credentials))
+	assert.Same(t, upstream, rc.wrap(ctx, kube, upstream, store, "default", false))
+}
+
+func TestResponseCacheBatch(t *testing.T) {
+	ctx := context.Background()
+	rc, err := NewResponseCache(10, time.Hour)
+	require.NoError(t, err)
+	store := &esv1.SecretStore{
+		ObjectMeta: metav1.ObjectMeta{Name: "store", Namespace: "default", Generation: 1},
+		Spec:       esv1.SecretStoreSpec{Provider: &esv1.SecretStoreProvider{AWS: &esv1.AWSProvider{}}},
+	}
+	upstream := &batchClient{}
+	_, err = rc.wrap(ctx, nil, upstream, store, "default", false).GetSecret(ctx, esv1.ExternalSecretDataRemoteRef{Key: "cached"})
+	require.NoError(t, err)
+
+	// cached refs are served from the cache, the others are fetched in a single batch
+	secretClient, ok := rc.wrap(ctx, nil, upstream, store, "default", false).(esv1.BatchSecretsClient)
+	require.True(t, ok)
+	refs := []esv1.ExternalSecretDataRemoteRef{{Key: "cached"}, {Key: "db"}, {Key: "missing"}}
+	secrets, errs, err := secretClient.GetSecrets(ctx, refs)
+	require.NoError(t, err)
+	assert.Equal(t, [][]byte{[]byte("cached"), []byte("db"), nil}, secrets)
+	assert.Equal(t, []error{nil, nil, esv1.NoSecretErr}, errs)
+	assert.Equal(t, [][]esv1.ExternalSecretDataRemoteRef{refs[1:]}, upstream.batches)
+
+	// fetched values are cached for GetSecret, missing secrets are not
+	_, err = rc.wrap(ctx, nil, upstream, store, "default", false).GetSecret(ctx, esv1.ExternalSecretDataRemoteRef{Key: "db"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, upstream.calls)
+	_, _, err = secretClient.GetSecrets(ctx, refs)
+	require.NoError(t, err)
+	assert.Equal(t, [][]esv1.ExternalSecretDataRemoteRef{refs[1:], refs[2:]}, upstream.batches)
+
+	// clients without batch support are not turned into batch clients
+	_, ok = rc.wrap(ctx, nil, &countingClient{}, store, "default", false).(esv1.BatchSecretsClient)
+	assert.False(t, ok)
+}
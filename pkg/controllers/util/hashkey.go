@@ -0,0 +1,69 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// HashKeySecretKey is the key of the hash key in its Secret.
+	HashKeySecretKey = "key"
+
+	hashKeySize = 32
+)
+
+// LoadHashKey returns the key of the hashes of secret values recorded in status, see utils.KeyedHash.
+// The Secret holding it is created with a random key if it does not exist, so the key is shared by all replicas
+// and survives restarts. The reader must not be cached, as it is used before the caches are started.
+func LoadHashKey(ctx context.Context, reader client.Reader, writer client.Writer, name types.NamespacedName) ([]byte, error) {
+	secret := &v1.Secret{}
+	err := reader.Get(ctx, name, secret)
+	if apierrors.IsNotFound(err) {
+		key := make([]byte, hashKeySize)
+		if _, err := rand.Read(key); err != nil {
+			return nil, err
+		}
+		secret = &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name.Name,
+				Namespace: name.Namespace,
+			},
+			Type: v1.SecretTypeOpaque,
+			Data: map[string][]byte{HashKeySecretKey: key},
+		}
+		err = writer.Create(ctx, secret)
+		if apierrors.IsAlreadyExists(err) {
+			// created by another replica in the meantime
+			err = reader.Get(ctx, name, secret)
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not load hash key from secret %s: %w", name, err)
+	}
+	key := secret.Data[HashKeySecretKey]
+	if len(key) < hashKeySize {
+		return nil, fmt.Errorf("hash key in secret %s must be at least %d bytes", name, hashKeySize)
+	}
+	return key, nil
+}
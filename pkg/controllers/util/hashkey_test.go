@@ -0,0 +1,58 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestLoadHashKey(t *testing.T) {
+	ctx := context.Background()
+	name := types.NamespacedName{Name: "hash-key", Namespace: "external-secrets"}
+	c := fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).Build()
+
+	// the key is created once and loaded afterwards
+	key, err := LoadHashKey(ctx, c, c, name)
+	if err != nil {
+		t.Fatalf("LoadHashKey() returned an unexpected error: %v", err)
+	}
+	if len(key) != hashKeySize {
+		t.Errorf("unexpected size of key: %d", len(key))
+	}
+	loaded, err := LoadHashKey(ctx, c, c, name)
+	if err != nil {
+		t.Fatalf("LoadHashKey() returned an unexpected error: %v", err)
+	}
+	if !bytes.Equal(key, loaded) {
+		t.Errorf("expected the existing key to be loaded")
+	}
+
+	// short keys are rejected
+	short := fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).WithObjects(&v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: name.Name, Namespace: name.Namespace},
+		Data:       map[string][]byte{HashKeySecretKey: []byte("short")},
+	}).Build()
+	if _, err := LoadHashKey(ctx, short, short, name); err == nil {
+		t.Errorf("expected an error for a short key")
+	}
+}
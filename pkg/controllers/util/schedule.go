@@ -0,0 +1,75 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math/rand/v2"
+	"time"
+)
+
+// Schedule spreads the periodic reconciles of resources over time,
+// so they don't all hit the providers at once after a restart of the controller.
+// A nil Schedule does not change any requeue.
+type Schedule struct {
+	jitter  int
+	stagger time.Duration
+	start   time.Time
+	now     func() time.Time
+}
+
+// NewSchedule returns a Schedule which adds up to jitter percent of the interval to every requeue,
+// and spreads resources due when the controller starts over the stagger window.
+func NewSchedule(jitter int, stagger time.Duration) (*Schedule, error) {
+	if jitter < 0 || jitter > 100 {
+		return nil, fmt.Errorf("refresh jitter must be between 0 and 100, got %d", jitter)
+	}
+	if stagger < 0 {
+		return nil, fmt.Errorf("startup stagger must not be negative, got %s", stagger)
+	}
+	return &Schedule{
+		jitter:  jitter,
+		stagger: stagger,
+		start:   time.Now(),
+		now:     time.Now,
+	}, nil
+}
+
+// Jitter returns a random share of up to the configured percentage of the interval.
+// It is added to the time until the next periodic reconcile of a resource.
+func (s *Schedule) Jitter(interval time.Duration) time.Duration {
+	if s == nil || s.jitter == 0 || interval <= 0 {
+		return 0
+	}
+	maxJitter := int64(interval) * int64(s.jitter) / 100
+	if maxJitter <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int64N(maxJitter))
+}
+
+// Stagger returns how long a resource which is due should wait before it is reconciled.
+// Every key gets a fixed offset within the stagger window after the start of the controller,
+// so the delay is zero once that offset passed, or outside the window.
+func (s *Schedule) Stagger(key string) time.Duration {
+	if s == nil || s.stagger <= 0 {
+		return 0
+	}
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(key))
+	offset := time.Duration(h.Sum64() % uint64(s.stagger))
+	return max(s.start.Add(offset).Sub(s.now()), 0)
+}
@@ -0,0 +1,76 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduleJitter(t *testing.T) {
+	s, err := NewSchedule(10, 0)
+	require.NoError(t, err)
+	for range 100 {
+		jitter := s.Jitter(time.Hour)
+		assert.GreaterOrEqual(t, jitter, time.Duration(0))
+		assert.Less(t, jitter, 6*time.Minute)
+	}
+
+	// no jitter is added if disabled
+	var unset *Schedule
+	assert.Zero(t, unset.Jitter(time.Hour))
+	s, err = NewSchedule(0, 0)
+	require.NoError(t, err)
+	assert.Zero(t, s.Jitter(time.Hour))
+
+	_, err = NewSchedule(101, 0)
+	assert.Error(t, err)
+	_, err = NewSchedule(0, -time.Second)
+	assert.Error(t, err)
+}
+
+func TestScheduleStagger(t *testing.T) {
+	s, err := NewSchedule(0, 10*time.Minute)
+	require.NoError(t, err)
+	now := s.start
+	s.now = func() time.Time { return now }
+
+	// keys are spread over the window, and keep their offset
+	delays := map[time.Duration]bool{}
+	for i := range 20 {
+		key := fmt.Sprintf("default/es-%d", i)
+		delay := s.Stagger(key)
+		assert.Less(t, delay, 10*time.Minute)
+		assert.Equal(t, delay, s.Stagger(key))
+		delays[delay] = true
+	}
+	assert.Greater(t, len(delays), 1)
+
+	// resources are not delayed once their offset passed
+	delay := s.Stagger("default/es-0")
+	now = now.Add(delay)
+	assert.Zero(t, s.Stagger("default/es-0"))
+	now = s.start.Add(10 * time.Minute)
+	for i := range 20 {
+		assert.Zero(t, s.Stagger(fmt.Sprintf("default/es-%d", i)))
+	}
+
+	var unset *Schedule
+	assert.Zero(t, unset.Stagger("default/es-0"))
+}
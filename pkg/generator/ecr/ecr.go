@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/ecr"
@@ -60,6 +61,15 @@ func (g *Generator) Cleanup(ctx context.Context, jsonSpec *apiextensions.JSON, _
 	return nil
 }
 
+// Expiry returns the expiry of the generated authorization token.
+func (g *Generator) Expiry(data map[string][]byte) time.Time {
+	exp, err := strconv.ParseInt(string(data["expires_at"]), 10, 64)
+	if err != nil {
+		return time.Time{}
+	}
+	return time.Unix(exp, 0)
+}
+
 func (g *Generator) generate(
 	ctx context.Context,
 	jsonSpec *apiextensions.JSON,
@@ -23,6 +23,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -50,6 +51,7 @@ type Github struct {
 
 const (
 	defaultLoginUsername = "token"
+	expiresAtKey         = "expires_at"
 	defaultGithubAPI     = "https://api.github.com"
 
 	errNoSpec    = "no config spec provided"
@@ -73,6 +75,15 @@ func (g *Generator) Cleanup(ctx context.Context, jsonSpec *apiextensions.JSON, _
 	return nil
 }
 
+// Expiry returns the expiry of the generated token.
+func (g *Generator) Expiry(data map[string][]byte) time.Time {
+	exp, err := strconv.ParseInt(string(data[expiresAtKey]), 10, 64)
+	if err != nil {
+		return time.Time{}
+	}
+	return time.Unix(exp, 0)
+}
+
 func (g *Generator) generate(
 	ctx context.Context,
 	jsonSpec *apiextensions.JSON,
@@ -140,9 +151,16 @@ func (g *Generator) generate(
 	if !ok {
 		return nil, nil, errors.New("token isn't a string or token key doesn't exist")
 	}
-	return map[string][]byte{
+	data := map[string][]byte{
 		defaultLoginUsername: []byte(accessToken),
-	}, nil, nil
+	}
+	// the expiry is returned as a Unix timestamp, like the expiry of other access tokens
+	if expiresAt, ok := gat["expires_at"].(string); ok {
+		if exp, err := time.Parse(time.RFC3339, expiresAt); err == nil {
+			data[expiresAtKey] = []byte(strconv.FormatInt(exp.Unix(), 10))
+		}
+	}
+	return data, nil, nil
 }
 
 func newGHClient(ctx context.Context, k client.Client, n string, hc *http.Client,
@@ -65,6 +65,15 @@ func (g *Generator) Cleanup(_ context.Context, jsonSpec *apiextensions.JSON, sta
 	return nil
 }
 
+// Expiry returns the expiry of the generated robot token.
+func (g *Generator) Expiry(data map[string][]byte) time.Time {
+	exp, err := strconv.ParseFloat(string(data["expiry"]), 64)
+	if err != nil {
+		return time.Time{}
+	}
+	return time.Unix(int64(exp), 0)
+}
+
 func (g *Generator) generate(
 	ctx context.Context,
 	jsonSpec *apiextensions.JSON,
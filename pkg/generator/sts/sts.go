@@ -19,6 +19,7 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/sts"
@@ -107,6 +108,15 @@ func (g *Generator) Cleanup(_ context.Context, jsonSpec *apiextensions.JSON, sta
 	return nil
 }
 
+// Expiry returns the expiry of the generated session credentials.
+func (g *Generator) Expiry(data map[string][]byte) time.Time {
+	exp, err := strconv.ParseInt(string(data["expiration"]), 10, 64)
+	if err != nil {
+		return time.Time{}
+	}
+	return time.Unix(exp, 0)
+}
+
 type stsFactoryFunc func(cfg *aws.Config) stsAPI
 
 func stsFactory(cfg *aws.Config) stsAPI {
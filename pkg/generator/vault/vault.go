@@ -19,6 +19,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strconv"
+	"time"
 
 	vault "github.com/hashicorp/vault/api"
 	apiextensions "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
@@ -65,6 +67,16 @@ func (g *Generator) Cleanup(_ context.Context, jsonSpec *apiextensions.JSON, sta
 	return nil
 }
 
+// Expiry returns the expiry of the lease of the generated secret. The lease duration is only
+// part of the results of type Auth and Raw, it is counted from the time the secret was generated.
+func (g *Generator) Expiry(data map[string][]byte) time.Time {
+	lease, err := strconv.ParseInt(string(data["lease_duration"]), 10, 64)
+	if err != nil || lease <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(time.Duration(lease) * time.Second)
+}
+
 func (g *Generator) generate(ctx context.Context, c *provider.Provider, jsonSpec *apiextensions.JSON, kube client.Client, corev1 typedcorev1.CoreV1Interface, namespace string) (map[string][]byte, genv1alpha1.GeneratorProviderState, error) {
 	if jsonSpec == nil {
 		return nil, nil, errors.New(errNoSpec)
@@ -15,6 +15,8 @@ limitations under the License.
 package metrics
 
 import (
+	"strconv"
+
 	"github.com/prometheus/client_golang/prometheus"
 	"sigs.k8s.io/controller-runtime/pkg/metrics"
 
@@ -24,6 +26,16 @@ import (
 const (
 	ExternalSecretSubsystem = "externalsecret"
 	providerAPICalls        = "provider_api_calls_count"
+	providerResponseCache   = "provider_response_cache_count"
+	providerAuthCalls       = "provider_auth_calls_count"
+	clientPoolSize          = "client_pool_size"
+	providerThrottledCalls  = "provider_throttled_calls_count"
+	shardOwned              = "shard_owned"
+	shardMembers            = "shard_members"
+	shardReconciles         = "shard_reconcile_count"
+
+	responseCacheHit  = "hit"
+	responseCacheMiss = "miss"
 )
 
 var (
@@ -32,12 +44,101 @@ var (
 		Name:      providerAPICalls,
 		Help:      "Number of API calls towards the secret provider",
 	}, []string{"provider", "call", "status"})
+
+	responseCacheTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: ExternalSecretSubsystem,
+		Name:      providerResponseCache,
+		Help:      "Number of lookups in the provider response cache",
+	}, []string{"kind", "name", "namespace", "call", "result"})
+
+	authCallsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: ExternalSecretSubsystem,
+		Name:      providerAuthCalls,
+		Help:      "Number of provider clients created, each of which authenticates against the provider",
+	}, []string{"kind", "name", "namespace"})
+
+	throttledCallsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: ExternalSecretSubsystem,
+		Name:      providerThrottledCalls,
+		Help:      "Number of provider calls delayed or rejected by the rate limit of the store",
+	}, []string{"kind", "name", "namespace", "call", "result"})
+
+	clientPoolSizeGauge = prometheus.NewGauge(prometheus.GaugeOpts{
+		Subsystem: ExternalSecretSubsystem,
+		Name:      clientPoolSize,
+		Help:      "Number of idle provider clients in the client pool",
+	})
+
+	shardOwnedGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: ExternalSecretSubsystem,
+		Name:      shardOwned,
+		Help:      "Whether a shard of ExternalSecrets is held by this replica",
+	}, []string{"shard"})
+
+	shardMembersGauge = prometheus.NewGauge(prometheus.GaugeOpts{
+		Subsystem: ExternalSecretSubsystem,
+		Name:      shardMembers,
+		Help:      "Number of live replicas the shards of ExternalSecrets are balanced among",
+	})
+
+	shardReconcilesTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: ExternalSecretSubsystem,
+		Name:      shardReconciles,
+		Help:      "Number of reconciles of ExternalSecrets by shard",
+	}, []string{"shard"})
 )
 
 func ObserveAPICall(provider, call string, err error) {
 	syncCallsTotal.WithLabelValues(provider, call, deriveStatus(err)).Inc()
 }
 
+// ObserveResponseCache counts a hit or miss of the response cache for the given store.
+func ObserveResponseCache(kind, name, namespace, call string, hit bool) {
+	result := responseCacheMiss
+	if hit {
+		result = responseCacheHit
+	}
+	responseCacheTotal.WithLabelValues(kind, name, namespace, call, result).Inc()
+}
+
+// ObserveAuthCall counts the creation of a provider client for the given store.
+func ObserveAuthCall(kind, name, namespace string) {
+	authCallsTotal.WithLabelValues(kind, name, namespace).Inc()
+}
+
+// ObserveThrottledCall counts a call which was delayed or rejected by the rate limit of the given store.
+func ObserveThrottledCall(kind, name, namespace, call string, rejected bool) {
+	result := "delayed"
+	if rejected {
+		result = "rejected"
+	}
+	throttledCallsTotal.WithLabelValues(kind, name, namespace, call, result).Inc()
+}
+
+// SetClientPoolSize reports the number of idle clients in the client pool.
+func SetClientPoolSize(size int) {
+	clientPoolSizeGauge.Set(float64(size))
+}
+
+// SetShardOwned reports whether a shard is held by this replica.
+func SetShardOwned(shard int, owned bool) {
+	value := 0.0
+	if owned {
+		value = 1
+	}
+	shardOwnedGauge.WithLabelValues(strconv.Itoa(shard)).Set(value)
+}
+
+// SetShardMembers reports the number of live replicas sharing the shards.
+func SetShardMembers(members int) {
+	shardMembersGauge.Set(float64(members))
+}
+
+// ObserveShardReconcile counts a reconcile of an ExternalSecret of a shard.
+func ObserveShardReconcile(shard int) {
+	shardReconcilesTotal.WithLabelValues(strconv.Itoa(shard)).Inc()
+}
+
 func deriveStatus(err error) string {
 	if err != nil {
 		return constants.StatusError
@@ -46,5 +147,6 @@ func deriveStatus(err error) string {
 }
 
 func init() {
-	metrics.Registry.MustRegister(syncCallsTotal)
+	metrics.Registry.MustRegister(syncCallsTotal, responseCacheTotal, authCallsTotal, throttledCallsTotal, clientPoolSizeGauge,
+		shardOwnedGauge, shardMembersGauge, shardReconcilesTotal)
 }
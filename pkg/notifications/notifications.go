@@ -0,0 +1,175 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package notifications turns change notifications of providers into refreshes of the ExternalSecrets
+// referencing the changed remote keys, so they do not have to be polled with a short refreshInterval.
+package notifications
+
+import (
+	"context"
+	"regexp"
+	"time"
+
+	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	"github.com/external-secrets/external-secrets/pkg/controllers/secretstore"
+)
+
+const (
+	// IndexRemoteKeyField is the field index of ExternalSecrets by the remote keys they reference.
+	IndexRemoteKeyField = "notifications.remoteKeys"
+
+	// indexFindMarker is indexed for ExternalSecrets which find remote keys by name,
+	// as the regular expression has to be matched against every notified key.
+	indexFindMarker = "*find*"
+)
+
+// Source parses the notifications of a provider.
+type Source interface {
+	// Name of the source, notifications are received at /<name>.
+	Name() string
+	// Parse returns the remote keys which changed according to the body of a notification.
+	Parse(body []byte) (*Result, error)
+}
+
+// Result of parsing a notification.
+type Result struct {
+	// Keys which changed at the provider.
+	Keys []string
+	// Response is written to the sender instead of an empty response if set,
+	// e.g. to answer a subscription handshake.
+	Response any
+}
+
+// RemoteKeys is the indexer func for IndexRemoteKeyField.
+func RemoteKeys(obj client.Object) []string {
+	es, ok := obj.(*esv1.ExternalSecret)
+	if !ok {
+		return nil
+	}
+	var keys []string
+	for _, data := range es.Spec.Data {
+		keys = append(keys, data.RemoteRef.Key)
+	}
+	for _, ref := range es.Spec.DataFrom {
+		if ref.Extract != nil {
+			keys = append(keys, ref.Extract.Key)
+		}
+		if ref.Find != nil && ref.Find.Name != nil {
+			keys = append(keys, indexFindMarker)
+		}
+	}
+	return keys
+}
+
+// Dispatcher requests a refresh of the ExternalSecrets referencing a notified key
+// by updating their force-sync annotation.
+// ExternalSecrets are matched by their remote keys, regardless of the store they reference.
+// Notified keys are evicted from the response cache, so the refresh sees the changed values.
+type Dispatcher struct {
+	client        client.Client
+	log           logr.Logger
+	responseCache *secretstore.ResponseCache
+}
+
+// NewDispatcher returns a Dispatcher updating ExternalSecrets with the given client,
+// which must have the IndexRemoteKeyField index.
+func NewDispatcher(c client.Client, log logr.Logger) *Dispatcher {
+	return &Dispatcher{
+		client: c,
+		log:    log,
+	}
+}
+
+// WithResponseCache evicts notified keys from the given cache.
+func (d *Dispatcher) WithResponseCache(c *secretstore.ResponseCache) *Dispatcher {
+	d.responseCache = c
+	return d
+}
+
+// Notify requests a refresh of all ExternalSecrets referencing one of the keys,
+// and returns the number of ExternalSecrets which are refreshed.
+// The refresh is requested through the API server, so it is not lost if the controller restarts,
+// and it is picked up by whichever replica reconciles the ExternalSecret.
+// Notifications are ignored by the CreatedOnce and OnChange refresh policies.
+func (d *Dispatcher) Notify(ctx context.Context, keys []string) (int, error) {
+	if evicted := d.responseCache.Evict(keys); evicted > 0 {
+		d.log.V(1).Info("evicted notified keys from the response cache", "responses", evicted)
+	}
+
+	matched := make(map[types.NamespacedName]*esv1.ExternalSecret)
+	for _, key := range keys {
+		list := &esv1.ExternalSecretList{}
+		if err := d.client.List(ctx, list, client.MatchingFields{IndexRemoteKeyField: key}); err != nil {
+			return 0, err
+		}
+		for i := range list.Items {
+			matched[client.ObjectKeyFromObject(&list.Items[i])] = &list.Items[i]
+		}
+	}
+
+	list := &esv1.ExternalSecretList{}
+	if err := d.client.List(ctx, list, client.MatchingFields{IndexRemoteKeyField: indexFindMarker}); err != nil {
+		return 0, err
+	}
+	for i := range list.Items {
+		if findsAnyKey(&list.Items[i], keys) {
+			matched[client.ObjectKeyFromObject(&list.Items[i])] = &list.Items[i]
+		}
+	}
+
+	now := time.Now().UTC().Format(time.RFC3339Nano)
+	var refreshed int
+	for _, es := range matched {
+		switch es.Spec.RefreshPolicy {
+		case esv1.RefreshPolicyCreatedOnce, esv1.RefreshPolicyOnChange:
+			continue
+		}
+		patch := client.MergeFrom(es.DeepCopy())
+		if es.Annotations == nil {
+			es.Annotations = make(map[string]string)
+		}
+		es.Annotations[esv1.AnnotationForceSync] = now
+		if err := d.client.Patch(ctx, es, patch); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return refreshed, err
+		}
+		refreshed++
+	}
+	return refreshed, nil
+}
+
+func findsAnyKey(es *esv1.ExternalSecret, keys []string) bool {
+	for _, ref := range es.Spec.DataFrom {
+		if ref.Find == nil || ref.Find.Name == nil {
+			continue
+		}
+		re, err := regexp.Compile(ref.Find.Name.RegExp)
+		if err != nil {
+			continue
+		}
+		for _, key := range keys {
+			if re.MatchString(key) {
+				return true
+			}
+		}
+	}
+	return false
+}
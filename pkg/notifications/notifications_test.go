@@ -0,0 +1,89 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notifications
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+)
+
+func newTestExternalSecret(name string, spec esv1.ExternalSecretSpec) *esv1.ExternalSecret {
+	return &esv1.ExternalSecret{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec:       spec,
+	}
+}
+
+func TestDispatcherNotify(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := esv1.AddToScheme(scheme); err != nil {
+		t.Fatalf("could not build scheme: %v", err)
+	}
+	byData := newTestExternalSecret("by-data", esv1.ExternalSecretSpec{
+		Data: []esv1.ExternalSecretData{{SecretKey: "foo", RemoteRef: esv1.ExternalSecretDataRemoteRef{Key: "db"}}},
+	})
+	byExtract := newTestExternalSecret("by-extract", esv1.ExternalSecretSpec{
+		DataFrom: []esv1.ExternalSecretDataFromRemoteRef{{Extract: &esv1.ExternalSecretDataRemoteRef{Key: "db"}}},
+	})
+	byFind := newTestExternalSecret("by-find", esv1.ExternalSecretSpec{
+		DataFrom: []esv1.ExternalSecretDataFromRemoteRef{{Find: &esv1.ExternalSecretFind{Name: &esv1.FindName{RegExp: "^app/.*"}}}},
+	})
+	other := newTestExternalSecret("other", esv1.ExternalSecretSpec{
+		Data: []esv1.ExternalSecretData{{SecretKey: "foo", RemoteRef: esv1.ExternalSecretDataRemoteRef{Key: "cache"}}},
+	})
+	onChange := newTestExternalSecret("on-change", esv1.ExternalSecretSpec{
+		RefreshPolicy: esv1.RefreshPolicyOnChange,
+		Data:          []esv1.ExternalSecretData{{SecretKey: "foo", RemoteRef: esv1.ExternalSecretDataRemoteRef{Key: "db"}}},
+	})
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(byData, byExtract, byFind, other, onChange).
+		WithIndex(&esv1.ExternalSecret{}, IndexRemoteKeyField, RemoteKeys).
+		Build()
+	d := NewDispatcher(c, logr.Discard())
+
+	refreshed, err := d.Notify(context.Background(), []string{"db", "app/config"})
+	if err != nil {
+		t.Fatalf("Notify() returned an unexpected error: %v", err)
+	}
+	if refreshed != 3 {
+		t.Errorf("unexpected number of refreshed ExternalSecrets: %d", refreshed)
+	}
+
+	// the force-sync annotation of the refreshed ExternalSecrets is updated
+	list := &esv1.ExternalSecretList{}
+	if err := c.List(context.Background(), list); err != nil {
+		t.Fatalf("could not list ExternalSecrets: %v", err)
+	}
+	var names []string
+	for _, es := range list.Items {
+		if es.Annotations[esv1.AnnotationForceSync] != "" {
+			names = append(names, es.Name)
+		}
+	}
+	sort.Strings(names)
+	if diff := cmp.Diff([]string{"by-data", "by-extract", "by-find"}, names); diff != "" {
+		t.Errorf("unexpected refreshed ExternalSecrets (-want, +got)\n%s", diff)
+	}
+}
@@ -0,0 +1,152 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notifications
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+const (
+	maxBodySize       = 1 << 20
+	readHeaderTimeout = 10 * time.Second
+	shutdownTimeout   = 10 * time.Second
+
+	// tokenQueryParam can be used instead of the Authorization header,
+	// for senders which can not set headers, e.g. Event Grid webhooks.
+	tokenQueryParam = "token"
+)
+
+// Server receives notifications over HTTP and passes the changed keys to the Dispatcher.
+// It runs on every replica, as the Dispatcher requests refreshes through the API server.
+// Senders are expected to retry notifications which were not accepted.
+type Server struct {
+	// Addr the server listens on.
+	Addr string
+	// Token notifications must present as bearer token, or in the token query parameter.
+	Token string
+	// AllowUnauthenticated accepts all notifications if no Token is set, otherwise they are rejected.
+	AllowUnauthenticated bool
+
+	Dispatcher *Dispatcher
+	Sources    []Source
+	Log        logr.Logger
+}
+
+// DefaultSources returns the sources of all supported providers.
+func DefaultSources() []Source {
+	return []Source{
+		&Generic{},
+		&AWSEventBridge{},
+		&GCPPubSub{},
+		&AzureEventGrid{},
+	}
+}
+
+// Start implements manager.Runnable.
+func (s *Server) Start(ctx context.Context) error {
+	srv := &http.Server{
+		Addr:              s.Addr,
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: readHeaderTimeout,
+	}
+	errCh := make(chan error, 1)
+	go func() {
+		s.Log.Info("starting notifications server", "addr", s.Addr)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errCh <- err
+		}
+		close(errCh)
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	return srv.Shutdown(shutdownCtx)
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable.
+func (s *Server) NeedLeaderElection() bool {
+	return false
+}
+
+// Handler returns the handler serving every source at /<name>.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	for _, source := range s.Sources {
+		mux.Handle("POST /"+source.Name(), s.handle(source))
+	}
+	return mux
+}
+
+func (s *Server) handle(source Source) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		if !s.authorized(req) {
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+		body, err := io.ReadAll(http.MaxBytesReader(w, req.Body, maxBodySize))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+			return
+		}
+		result, err := source.Parse(body)
+		if err != nil {
+			s.Log.V(1).Info("invalid notification", "source", source.Name(), "error", err.Error())
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if len(result.Keys) > 0 {
+			refreshed, err := s.Dispatcher.Notify(req.Context(), result.Keys)
+			if err != nil {
+				s.Log.Error(err, "could not dispatch notification", "source", source.Name())
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+				return
+			}
+			s.Log.V(1).Info("received notification", "source", source.Name(), "keys", result.Keys, "refreshed", refreshed)
+		}
+
+		if result.Response == nil {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(result.Response)
+	}
+}
+
+func (s *Server) authorized(req *http.Request) bool {
+	if s.Token == "" {
+		return s.AllowUnauthenticated
+	}
+	token := req.URL.Query().Get(tokenQueryParam)
+	if bearer, ok := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer "); ok {
+		token = bearer
+	}
+	return subtle.ConstantTimeCompare([]byte(token), []byte(s.Token)) == 1
+}
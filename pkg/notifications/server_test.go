@@ -0,0 +1,188 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notifications
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+)
+
+func TestSources(t *testing.T) {
+	tests := []struct {
+		name         string
+		source       Source
+		body         string
+		expectedKeys []string
+		expectedResp any
+	}{
+		{
+			name:         "generic",
+			source:       &Generic{},
+			body:         `{"keys": ["foo", "bar"]}`,
+			expectedKeys: []string{"foo", "bar"},
+		},
+		{
+			name:   "aws secrets manager",
+			source: &AWSEventBridge{},
+			body: `{
+				"source": "aws.secretsmanager",
+				"detail-type": "AWS API Call via CloudTrail",
+				"detail": {
+					"eventName": "PutSecretValue",
+					"requestParameters": {"secretId": "arn:aws:secretsmanager:eu-west-1:123456789012:secret:prod/db-AbCdEf"}
+				}
+			}`,
+			expectedKeys: []string{"arn:aws:secretsmanager:eu-west-1:123456789012:secret:prod/db-AbCdEf", "prod/db"},
+		},
+		{
+			name:   "aws parameter store",
+			source: &AWSEventBridge{},
+			body: `{
+				"source": "aws.ssm",
+				"detail-type": "Parameter Store Change",
+				"detail": {"name": "/prod/db/password", "operation": "Update"}
+			}`,
+			expectedKeys: []string{"/prod/db/password"},
+		},
+		{
+			name:   "gcp pubsub",
+			source: &GCPPubSub{},
+			body: `{
+				"message": {
+					"attributes": {"eventType": "SECRET_VERSION_ADD", "secretId": "projects/my-project/secrets/db"},
+					"messageId": "1"
+				},
+				"subscription": "projects/my-project/subscriptions/eso"
+			}`,
+			expectedKeys: []string{"projects/my-project/secrets/db", "db"},
+		},
+		{
+			name:   "azure event grid",
+			source: &AzureEventGrid{},
+			body: `[
+				{"eventType": "Microsoft.KeyVault.SecretNewVersionCreated", "data": {"ObjectType": "Secret", "ObjectName": "db"}},
+				{"eventType": "Microsoft.KeyVault.CertificateNewVersionCreated", "data": {"ObjectType": "Certificate", "ObjectName": "tls"}}
+			]`,
+			expectedKeys: []string{"db", "secret/db", "cert/tls"},
+		},
+		{
+			name:         "azure event grid handshake",
+			source:       &AzureEventGrid{},
+			body:         `[{"eventType": "Microsoft.EventGrid.SubscriptionValidationEvent", "data": {"validationCode": "abc"}}]`,
+			expectedResp: map[string]string{"validationResponse": "abc"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tt.source.Parse([]byte(tt.body))
+			if err != nil {
+				t.Fatalf("Parse() returned an unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tt.expectedKeys, result.Keys); diff != "" {
+				t.Errorf("unexpected keys (-want, +got)\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.expectedResp, result.Response); diff != "" {
+				t.Errorf("unexpected response (-want, +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestServer(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := esv1.AddToScheme(scheme); err != nil {
+		t.Fatalf("could not build scheme: %v", err)
+	}
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(newTestExternalSecret("es", esv1.ExternalSecretSpec{
+			Data: []esv1.ExternalSecretData{{SecretKey: "foo", RemoteRef: esv1.ExternalSecretDataRemoteRef{Key: "db"}}},
+		})).
+		WithIndex(&esv1.ExternalSecret{}, IndexRemoteKeyField, RemoteKeys).
+		Build()
+	s := &Server{
+		Token:      "secret-token",
+		Dispatcher: NewDispatcher(c, logr.Discard()),
+		Sources:    DefaultSources(),
+		Log:        logr.Discard(),
+	}
+	srv := httptest.NewServer(s.Handler())
+	defer srv.Close()
+
+	post := func(path, auth, body string) (int, string) {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodPost, srv.URL+path, strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("could not build request: %v", err)
+		}
+		if auth != "" {
+			req.Header.Set("Authorization", auth)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		defer resp.Body.Close()
+		b, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(b)
+	}
+
+	if code, _ := post("/generic", "", `{"keys": ["db"]}`); code != http.StatusUnauthorized {
+		t.Errorf("expected unauthenticated notifications to be rejected, got: %d", code)
+	}
+	if code, _ := post("/generic", "Bearer wrong", `{"keys": ["db"]}`); code != http.StatusUnauthorized {
+		t.Errorf("expected notifications with a wrong token to be rejected, got: %d", code)
+	}
+	if code, _ := post("/generic", "Bearer secret-token", `not json`); code != http.StatusBadRequest {
+		t.Errorf("expected invalid notifications to be rejected, got: %d", code)
+	}
+	if code, _ := post("/generic", "Bearer secret-token", `{"keys": ["db"]}`); code != http.StatusOK {
+		t.Errorf("unexpected status: %d", code)
+	}
+	es := &esv1.ExternalSecret{}
+	if err := c.Get(context.Background(), types.NamespacedName{Name: "es", Namespace: "default"}, es); err != nil {
+		t.Fatalf("could not get ExternalSecret: %v", err)
+	}
+	if es.Annotations[esv1.AnnotationForceSync] == "" {
+		t.Errorf("expected a refresh of the notified ExternalSecret to be requested")
+	}
+
+	code, body := post("/azure?token=secret-token", "", `[{"eventType": "Microsoft.EventGrid.SubscriptionValidationEvent", "data": {"validationCode": "abc"}}]`)
+	if code != http.StatusOK || strings.TrimSpace(body) != `{"validationResponse":"abc"}` {
+		t.Errorf("unexpected handshake response: %d %s", code, body)
+	}
+
+	// without a token, notifications are only accepted if explicitly allowed
+	s.Token = ""
+	if code, _ := post("/generic", "", `{"keys": ["db"]}`); code != http.StatusUnauthorized {
+		t.Errorf("expected notifications to be rejected without a token, got: %d", code)
+	}
+	s.AllowUnauthenticated = true
+	if code, _ := post("/generic", "", `{"keys": ["db"]}`); code != http.StatusOK {
+		t.Errorf("expected unauthenticated notifications to be accepted if allowed, got: %d", code)
+	}
+}
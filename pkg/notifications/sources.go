@@ -0,0 +1,179 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notifications
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// Generic receives the changed keys as {"keys": ["..."]}.
+type Generic struct{}
+
+func (g *Generic) Name() string {
+	return "generic"
+}
+
+func (g *Generic) Parse(body []byte) (*Result, error) {
+	var notification struct {
+		Keys []string `json:"keys"`
+	}
+	if err := json.Unmarshal(body, &notification); err != nil {
+		return nil, err
+	}
+	return &Result{Keys: notification.Keys}, nil
+}
+
+// AWSEventBridge receives events of AWS Secrets Manager and Parameter Store,
+// delivered by an EventBridge API destination.
+// Secrets Manager events are the CloudTrail events of API calls, e.g. PutSecretValue.
+type AWSEventBridge struct{}
+
+func (a *AWSEventBridge) Name() string {
+	return "aws"
+}
+
+func (a *AWSEventBridge) Parse(body []byte) (*Result, error) {
+	var event struct {
+		Source string `json:"source"`
+		Detail struct {
+			// Parameter Store Change
+			Name string `json:"name"`
+			// AWS API Call via CloudTrail
+			RequestParameters struct {
+				SecretID string `json:"secretId"`
+				Name     string `json:"name"`
+			} `json:"requestParameters"`
+			// AWS Service Event via CloudTrail, e.g. RotationSucceeded
+			AdditionalEventData struct {
+				SecretID string `json:"SecretId"`
+			} `json:"additionalEventData"`
+		} `json:"detail"`
+	}
+	if err := json.Unmarshal(body, &event); err != nil {
+		return nil, err
+	}
+
+	var keys []string
+	switch event.Source {
+	case "aws.secretsmanager":
+		for _, id := range []string{event.Detail.RequestParameters.SecretID, event.Detail.RequestParameters.Name, event.Detail.AdditionalEventData.SecretID} {
+			if id == "" {
+				continue
+			}
+			keys = append(keys, id)
+			if name, ok := secretNameFromARN(id); ok {
+				keys = append(keys, name)
+			}
+		}
+	case "aws.ssm":
+		if event.Detail.Name != "" {
+			keys = append(keys, event.Detail.Name)
+		}
+	}
+	return &Result{Keys: keys}, nil
+}
+
+// secretNameFromARN returns the name of a secret from its ARN,
+// which has a suffix of 6 random characters, e.g. arn:aws:secretsmanager:eu-west-1:123456789012:secret:db-AbCdEf.
+func secretNameFromARN(arn string) (string, bool) {
+	_, name, ok := strings.Cut(arn, ":secret:")
+	if !ok || !strings.HasPrefix(arn, "arn:") {
+		return "", false
+	}
+	if i := len(name) - 7; i > 0 && name[i] == '-' {
+		name = name[:i]
+	}
+	return name, true
+}
+
+// GCPPubSub receives the notifications of GCP Secret Manager, delivered by a Pub/Sub push subscription
+// of a topic configured on the secrets.
+type GCPPubSub struct{}
+
+func (g *GCPPubSub) Name() string {
+	return "gcp"
+}
+
+func (g *GCPPubSub) Parse(body []byte) (*Result, error) {
+	var push struct {
+		Message struct {
+			Attributes map[string]string `json:"attributes"`
+		} `json:"message"`
+	}
+	if err := json.Unmarshal(body, &push); err != nil {
+		return nil, err
+	}
+
+	// secretId is the resource name of the secret, e.g. projects/my-project/secrets/my-secret
+	secretID := push.Message.Attributes["secretId"]
+	if secretID == "" {
+		return &Result{}, nil
+	}
+	keys := []string{secretID}
+	if i := strings.LastIndex(secretID, "/secrets/"); i >= 0 {
+		keys = append(keys, secretID[i+len("/secrets/"):])
+	}
+	return &Result{Keys: keys}, nil
+}
+
+// AzureEventGrid receives events of Azure Key Vault, delivered by an Event Grid webhook subscription
+// using the Event Grid event schema.
+type AzureEventGrid struct{}
+
+const (
+	eventGridValidationEvent = "Microsoft.EventGrid.SubscriptionValidationEvent"
+	eventGridKeyVaultPrefix  = "Microsoft.KeyVault."
+)
+
+func (a *AzureEventGrid) Name() string {
+	return "azure"
+}
+
+func (a *AzureEventGrid) Parse(body []byte) (*Result, error) {
+	var events []struct {
+		EventType string `json:"eventType"`
+		Data      struct {
+			ValidationCode string `json:"validationCode"`
+			ObjectType     string `json:"ObjectType"`
+			ObjectName     string `json:"ObjectName"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &events); err != nil {
+		return nil, err
+	}
+
+	result := &Result{}
+	for _, event := range events {
+		if event.EventType == eventGridValidationEvent {
+			// answer the handshake Event Grid sends when the subscription is created
+			result.Response = map[string]string{"validationResponse": event.Data.ValidationCode}
+			continue
+		}
+		if !strings.HasPrefix(event.EventType, eventGridKeyVaultPrefix) || event.Data.ObjectName == "" {
+			continue
+		}
+		// remote keys of the Key Vault provider are prefixed with the type of the object, secrets are not by default
+		switch event.Data.ObjectType {
+		case "Secret":
+			result.Keys = append(result.Keys, event.Data.ObjectName, "secret/"+event.Data.ObjectName)
+		case "Certificate":
+			result.Keys = append(result.Keys, "cert/"+event.Data.ObjectName)
+		case "Key":
+			result.Keys = append(result.Keys, "key/"+event.Data.ObjectName)
+		}
+	}
+	return result, nil
+}
@@ -26,6 +26,7 @@ import (
 // Client implements the aws parameterstore interface.
 type Client struct {
 	GetParameterFn           GetParameterFn
+	GetParametersFn          GetParametersFn
 	GetParametersByPathFn    GetParametersByPathFn
 	PutParameterFn           PutParameterFn
 	PutParameterCalledN      int
@@ -38,6 +39,7 @@ type Client struct {
 }
 
 type GetParameterFn func(context.Context, *ssm.GetParameterInput, ...func(*ssm.Options)) (*ssm.GetParameterOutput, error)
+type GetParametersFn func(context.Context, *ssm.GetParametersInput, ...func(*ssm.Options)) (*ssm.GetParametersOutput, error)
 type GetParametersByPathFn func(context.Context, *ssm.GetParametersByPathInput, ...func(*ssm.Options)) (*ssm.GetParametersByPathOutput, error)
 type PutParameterFn func(context.Context, *ssm.PutParameterInput, ...func(*ssm.Options)) (*ssm.PutParameterOutput, error)
 type DescribeParametersFn func(context.Context, *ssm.DescribeParametersInput, ...func(*ssm.Options)) (*ssm.DescribeParametersOutput, error)
@@ -77,6 +79,10 @@ func (sm *Client) GetParameter(ctx context.Context, input *ssm.GetParameterInput
 	return sm.GetParameterFn(ctx, input, options...)
 }
 
+func (sm *Client) GetParameters(ctx context.Context, input *ssm.GetParametersInput, options ...func(*ssm.Options)) (*ssm.GetParametersOutput, error) {
+	return sm.GetParametersFn(ctx, input, options...)
+}
+
 func (sm *Client) GetParametersByPath(ctx context.Context, input *ssm.GetParametersByPathInput, options ...func(*ssm.Options)) (*ssm.GetParametersByPathOutput, error) {
 	return sm.GetParametersByPathFn(ctx, input, options...)
 }
@@ -19,6 +19,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -58,10 +59,12 @@ type PushSecretMetadataSpec struct {
 
 // https://github.com/external-secrets/external-secrets/issues/644
 var (
-	_               esv1.SecretsClient = &ParameterStore{}
-	managedBy                          = "managed-by"
-	externalSecrets                    = "external-secrets"
-	logger                             = ctrl.Log.WithName("provider").WithName("parameterstore")
+	_               esv1.SecretsClient      = &ParameterStore{}
+	_               esv1.PoolableClient     = &ParameterStore{}
+	_               esv1.BatchSecretsClient = &ParameterStore{}
+	managedBy                               = "managed-by"
+	externalSecrets                         = "external-secrets"
+	logger                                  = ctrl.Log.WithName("provider").WithName("parameterstore")
 )
 
 // ParameterStore is a provider for AWS ParameterStore.
@@ -76,6 +79,7 @@ type ParameterStore struct {
 // see: https://docs.aws.amazon.com/sdk-for-go/api/service/ssm/ssmiface/
 type PMInterface interface {
 	GetParameter(ctx context.Context, input *ssm.GetParameterInput, opts ...func(*ssm.Options)) (*ssm.GetParameterOutput, error)
+	GetParameters(ctx context.Context, input *ssm.GetParametersInput, opts ...func(*ssm.Options)) (*ssm.GetParametersOutput, error)
 	GetParametersByPath(ctx context.Context, input *ssm.GetParametersByPathInput, opts ...func(*ssm.Options)) (*ssm.GetParametersByPathOutput, error)
 	PutParameter(ctx context.Context, input *ssm.PutParameterInput, opts ...func(*ssm.Options)) (*ssm.PutParameterOutput, error)
 	DescribeParameters(ctx context.Context, input *ssm.DescribeParametersInput, opts ...func(*ssm.Options)) (*ssm.DescribeParametersOutput, error)
@@ -88,6 +92,9 @@ type PMInterface interface {
 const (
 	errUnexpectedFindOperator    = "unexpected find operator"
 	errCodeAccessDeniedException = "AccessDeniedException"
+
+	// getParametersMaxNames is the maximum number of names of a GetParameters call.
+	getParametersMaxNames = 10
 )
 
 // New constructs a ParameterStore Provider that is specific to a store.
@@ -532,21 +539,78 @@ func (pm *ParameterStore) GetSecret(ctx context.Context, ref esv1.ExternalSecret
 	if err != nil {
 		return nil, util.SanitizeErr(err)
 	}
+	return parameterValue(out.Parameter, ref)
+}
+
+// GetSecrets fetches the parameters of refs with GetParameters,
+// and extracts the values of the refs like GetSecret.
+// Refs fetching metadata are fetched one by one. So are all refs if GetParameters fails,
+// e.g. because it is not allowed by IAM.
+func (pm *ParameterStore) GetSecrets(ctx context.Context, refs []esv1.ExternalSecretDataRemoteRef) ([][]byte, []error, error) {
+	var names []string
+	for _, ref := range refs {
+		name := *pm.parameterNameWithVersion(ref)
+		if ref.MetadataPolicy == esv1.ExternalSecretMetadataPolicyFetch || slices.Contains(names, name) {
+			continue
+		}
+		names = append(names, name)
+	}
+	params := make(map[string]*ssmTypes.Parameter, len(names))
+	invalid := make(map[string]bool)
+	for chunk := range slices.Chunk(names, getParametersMaxNames) {
+		out, err := pm.client.GetParameters(ctx, &ssm.GetParametersInput{
+			Names:          chunk,
+			WithDecryption: aws.Bool(true),
+		})
+		metrics.ObserveAPICall(constants.ProviderAWSPS, constants.CallAWSPSGetParameters, err)
+		if err != nil {
+			logger.Info("could not fetch parameters in a batch, fetching them one by one", "error", util.SanitizeErr(err).Error())
+			break
+		}
+		for i := range out.Parameters {
+			// parameters are requested by name or ARN, with an optional version selector
+			param := &out.Parameters[i]
+			params[aws.ToString(param.Name)+aws.ToString(param.Selector)] = param
+			params[aws.ToString(param.ARN)+aws.ToString(param.Selector)] = param
+		}
+		for _, name := range out.InvalidParameters {
+			invalid[name] = true
+		}
+	}
+
+	secrets := make([][]byte, len(refs))
+	errs := make([]error, len(refs))
+	for i, ref := range refs {
+		name := *pm.parameterNameWithVersion(ref)
+		switch param, ok := params[name]; {
+		case ok && ref.MetadataPolicy != esv1.ExternalSecretMetadataPolicyFetch:
+			secrets[i], errs[i] = parameterValue(param, ref)
+		case invalid[name] && ref.MetadataPolicy != esv1.ExternalSecretMetadataPolicyFetch:
+			errs[i] = esv1.NoSecretErr
+		default:
+			secrets[i], errs[i] = pm.GetSecret(ctx, ref)
+		}
+	}
+	return secrets, errs, nil
+}
+
+// parameterValue returns the value of a parameter, or the property of its value referenced by ref.
+func parameterValue(param *ssmTypes.Parameter, ref esv1.ExternalSecretDataRemoteRef) ([]byte, error) {
 	if ref.Property == "" {
-		if out.Parameter.Value != nil {
-			return []byte(*out.Parameter.Value), nil
+		if param.Value != nil {
+			return []byte(*param.Value), nil
 		}
 		return nil, fmt.Errorf("invalid secret received. parameter value is nil for key: %s", ref.Key)
 	}
 	idx := strings.Index(ref.Property, ".")
 	if idx > -1 {
 		refProperty := strings.ReplaceAll(ref.Property, ".", "\\.")
-		val := gjson.Get(*out.Parameter.Value, refProperty)
+		val := gjson.Get(*param.Value, refProperty)
 		if val.Exists() {
 			return []byte(val.String()), nil
 		}
 	}
-	val := gjson.Get(*out.Parameter.Value, ref.Property)
+	val := gjson.Get(*param.Value, ref.Property)
 	if !val.Exists() {
 		return nil, fmt.Errorf("key %s does not exist in secret %s", ref.Property, ref.Key)
 	}
@@ -617,6 +681,9 @@ func (pm *ParameterStore) parameterNameWithVersion(ref esv1.ExternalSecretDataRe
 	return &name
 }
 
+// Reset implements esv1.PoolableClient, the client has no state to reset.
+func (pm *ParameterStore) Reset() {}
+
 func (pm *ParameterStore) Close(_ context.Context) error {
 	return nil
 }
@@ -917,6 +917,49 @@ func TestGetSecret(t *testing.T) {
 	}
 }
 
+func TestGetSecrets(t *testing.T) {
+	var batches [][]string
+	fakeClient := &fakeps.Client{
+		GetParametersFn: func(_ context.Context, in *ssm.GetParametersInput, _ ...func(*ssm.Options)) (*ssm.GetParametersOutput, error) {
+			batches = append(batches, in.Names)
+			return &ssm.GetParametersOutput{
+				Parameters: []ssmtypes.Parameter{
+					{Name: aws.String("/app/db"), ARN: aws.String("arn:db"), Value: aws.String(`{"user":"admin"}`)},
+					{Name: aws.String("/app/db"), ARN: aws.String("arn:db"), Selector: aws.String(":2"), Value: aws.String("v2")},
+				},
+				InvalidParameters: []string{"/app/missing"},
+			}, nil
+		},
+	}
+	pm := ParameterStore{client: fakeClient, prefix: "/app/"}
+
+	refs := []esv1.ExternalSecretDataRemoteRef{
+		{Key: "db", Property: "user"},
+		{Key: "db"},
+		{Key: "db", Version: "2"},
+		{Key: "missing"},
+		{Key: "db", Property: invalidProp},
+	}
+	secrets, errs, err := pm.GetSecrets(context.Background(), refs)
+	require.NoError(t, err)
+	assert.Equal(t, [][]byte{[]byte("admin"), []byte(`{"user":"admin"}`), []byte("v2"), nil, nil}, secrets)
+	assert.Equal(t, []error{nil, nil, nil, esv1.NoSecretErr}, errs[:4])
+	assert.ErrorContains(t, errs[4], errInvalidProperty)
+	assert.Equal(t, [][]string{{"/app/db", "/app/db:2", "/app/missing"}}, batches)
+
+	// if the batch fails, e.g. because it is not allowed, the parameters are fetched one by one
+	fakeClient.GetParametersFn = func(_ context.Context, _ *ssm.GetParametersInput, _ ...func(*ssm.Options)) (*ssm.GetParametersOutput, error) {
+		return nil, errors.New("access denied")
+	}
+	fakeClient.GetParameterFn = fakeps.NewGetParameterFn(&ssm.GetParameterOutput{
+		Parameter: &ssmtypes.Parameter{Value: aws.String("single")},
+	}, nil)
+	secrets, errs, err = pm.GetSecrets(context.Background(), refs[1:3])
+	require.NoError(t, err)
+	assert.Equal(t, [][]byte{[]byte("single"), []byte("single")}, secrets)
+	assert.Equal(t, []error{nil, nil}, errs)
+}
+
 func TestGetSecretMap(t *testing.T) {
 	// good case: default version & deserialization
 	simpleJSON := func(pstc *parameterstoreTestCase) {
@@ -60,8 +60,15 @@ const (
 	ResourceNotFoundException = "ResourceNotFoundException"
 )
 
+// batchGetSecretValueMaxIDs is the maximum number of secret ids of a BatchGetSecretValue call.
+const batchGetSecretValueMaxIDs = 20
+
 // https://github.com/external-secrets/external-secrets/issues/644
 var _ esv1.SecretsClient = &SecretsManager{}
+var _ esv1.PoolableClient = &SecretsManager{}
+var _ esv1.BatchSecretsClient = &SecretsManager{}
+var _ esv1.MetadataSecretsClient = &SecretsManager{}
+var _ esv1.MetadataBatchSecretsClient = &SecretsManager{}
 
 // SecretsManager is a provider for AWS SecretsManager.
 type SecretsManager struct {
@@ -123,7 +130,7 @@ func (sm *SecretsManager) fetch(ctx context.Context, ref esv1.ExternalSecretData
 	key := sm.prefix + ref.Key
 	log.Info("fetching secret value", "key", key, "version", ver, "value", valueFrom)
 
-	cacheKey := fmt.Sprintf("%s#%s#%s", key, ver, valueFrom)
+	cacheKey := secretCacheKey(key, ver, valueFrom)
 	if secretOut, found := sm.cache[cacheKey]; found {
 		log.Info("found secret in cache", "key", key, "version", ver)
 		return secretOut, nil
@@ -139,8 +146,80 @@ func (sm *SecretsManager) fetch(ctx context.Context, ref esv1.ExternalSecretData
 	return secretOut, nil
 }
 
+func secretCacheKey(key, ver, valueFrom string) string {
+	return fmt.Sprintf("%s#%s#%s", key, ver, valueFrom)
+}
+
+// invalidateCache drops the cached values of all versions of a secret.
+func (sm *SecretsManager) invalidateCache(key string) {
+	for cacheKey := range sm.cache {
+		if strings.HasPrefix(cacheKey, key+"#") {
+			delete(sm.cache, cacheKey)
+		}
+	}
+}
+
+// GetSecrets fetches the current versions of the secrets of refs with BatchGetSecretValue,
+// and extracts the values of the refs like GetSecret.
+// Refs of a specific version or fetching metadata, and secrets the batch could not return,
+// are fetched one by one. So are all refs if BatchGetSecretValue fails, e.g. because it is not allowed by IAM.
+func (sm *SecretsManager) GetSecrets(ctx context.Context, refs []esv1.ExternalSecretDataRemoteRef) ([][]byte, []error, error) {
+	secrets, _, errs, err := sm.GetSecretsWithMetadata(ctx, refs)
+	return secrets, errs, err
+}
+
+// GetSecretsWithMetadata fetches the secrets like GetSecrets, along with the metadata of their versions.
+func (sm *SecretsManager) GetSecretsWithMetadata(ctx context.Context, refs []esv1.ExternalSecretDataRemoteRef) ([][]byte, []*esv1.SecretMetadata, []error, error) {
+	var ids []string
+	for _, ref := range refs {
+		key := sm.prefix + ref.Key
+		if ref.Version != "" || ref.MetadataPolicy == esv1.ExternalSecretMetadataPolicyFetch || slices.Contains(ids, key) {
+			continue
+		}
+		if _, found := sm.cache[secretCacheKey(key, "AWSCURRENT", "SECRET")]; !found {
+			ids = append(ids, key)
+		}
+	}
+	for chunk := range slices.Chunk(ids, batchGetSecretValueMaxIDs) {
+		out, err := sm.client.BatchGetSecretValue(ctx, &awssm.BatchGetSecretValueInput{
+			SecretIdList: chunk,
+		})
+		metrics.ObserveAPICall(constants.ProviderAWSSM, constants.CallAWSSMBatchGetSecretValue, err)
+		if err != nil {
+			log.Info("could not fetch secrets in a batch, fetching them one by one", "error", util.SanitizeErr(err).Error())
+			break
+		}
+		for _, secret := range out.SecretValues {
+			// the values are cached by the id they were requested with, which is either the name or the ARN
+			for _, id := range chunk {
+				if id != aws.ToString(secret.Name) && id != aws.ToString(secret.ARN) {
+					continue
+				}
+				sm.cache[secretCacheKey(id, "AWSCURRENT", "SECRET")] = &awssm.GetSecretValueOutput{
+					ARN:           secret.ARN,
+					CreatedDate:   secret.CreatedDate,
+					Name:          secret.Name,
+					SecretBinary:  secret.SecretBinary,
+					SecretString:  secret.SecretString,
+					VersionId:     secret.VersionId,
+					VersionStages: secret.VersionStages,
+				}
+			}
+		}
+	}
+
+	secrets := make([][]byte, len(refs))
+	metadata := make([]*esv1.SecretMetadata, len(refs))
+	errs := make([]error, len(refs))
+	for i, ref := range refs {
+		secrets[i], metadata[i], errs[i] = sm.GetSecretWithMetadata(ctx, ref)
+	}
+	return secrets, metadata, errs, nil
+}
+
 func (sm *SecretsManager) DeleteSecret(ctx context.Context, remoteRef esv1.PushSecretRemoteRef) error {
 	secretName := sm.prefix + remoteRef.GetRemoteKey()
+	defer sm.invalidateCache(secretName)
 	secretValue := awssm.GetSecretValueInput{
 		SecretId: &secretName,
 	}
@@ -215,6 +294,8 @@ func (sm *SecretsManager) PushSecret(ctx context.Context, secret *corev1.Secret,
 	}
 
 	secretName := sm.prefix + psd.GetRemoteKey()
+	// the values read before are outdated once the secret is written
+	defer sm.invalidateCache(secretName)
 	describeSecretInput := awssm.DescribeSecretInput{SecretId: &secretName}
 	describeSecretOutput, err := sm.client.DescribeSecret(ctx, &describeSecretInput)
 	metrics.ObserveAPICall(constants.ProviderAWSSM, constants.CallAWSSMDescribeSecret, err)
@@ -428,13 +509,34 @@ func (sm *SecretsManager) fetchAndSet(ctx context.Context, data map[string][]byt
 
 // GetSecret returns a single secret from the provider.
 func (sm *SecretsManager) GetSecret(ctx context.Context, ref esv1.ExternalSecretDataRemoteRef) ([]byte, error) {
+	secret, _, err := sm.GetSecretWithMetadata(ctx, ref)
+	return secret, err
+}
+
+// GetSecretWithMetadata returns the secret along with its VersionId and the creation date of the version.
+func (sm *SecretsManager) GetSecretWithMetadata(ctx context.Context, ref esv1.ExternalSecretDataRemoteRef) ([]byte, *esv1.SecretMetadata, error) {
 	secretOut, err := sm.fetch(ctx, ref)
 	if errors.Is(err, esv1.NoSecretErr) {
-		return nil, err
+		return nil, nil, err
 	}
 	if err != nil {
-		return nil, util.SanitizeErr(err)
+		return nil, nil, util.SanitizeErr(err)
 	}
+	secret, err := sm.secretValue(secretOut, ref)
+	if err != nil {
+		return nil, nil, err
+	}
+	metadata := &esv1.SecretMetadata{}
+	if secretOut.VersionId != nil {
+		metadata.Version = *secretOut.VersionId
+	}
+	if secretOut.CreatedDate != nil {
+		metadata.LastModified = *secretOut.CreatedDate
+	}
+	return secret, metadata, nil
+}
+
+func (sm *SecretsManager) secretValue(secretOut *awssm.GetSecretValueOutput, ref esv1.ExternalSecretDataRemoteRef) ([]byte, error) {
 	if ref.Property == "" {
 		if secretOut.SecretString != nil {
 			return []byte(*secretOut.SecretString), nil
@@ -512,6 +614,11 @@ func (sm *SecretsManager) GetSecretMap(ctx context.Context, ref esv1.ExternalSec
 	return secretData, nil
 }
 
+// Reset drops the secrets cached during the previous reconcile.
+func (sm *SecretsManager) Reset() {
+	sm.cache = make(map[string]*awssm.GetSecretValueOutput)
+}
+
 func (sm *SecretsManager) Close(_ context.Context) error {
 	return nil
 }
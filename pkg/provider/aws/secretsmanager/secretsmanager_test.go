@@ -41,6 +41,7 @@ import (
 	fakesm "github.com/external-secrets/external-secrets/pkg/provider/aws/secretsmanager/fake"
 	"github.com/external-secrets/external-secrets/pkg/provider/aws/util"
 	"github.com/external-secrets/external-secrets/pkg/provider/testing/fake"
+	"github.com/external-secrets/external-secrets/pkg/provider/testing/conformance"
 )
 
 type secretsManagerTestCase struct {
@@ -339,6 +340,63 @@ func TestCaching(t *testing.T) {
 	}
 }
 
+func TestGetSecrets(t *testing.T) {
+	fakeClient := fakesm.NewClient()
+	var batches [][]string
+	fakeClient.BatchGetSecretValueFn = func(_ context.Context, in *awssm.BatchGetSecretValueInput, _ ...func(*awssm.Options)) (*awssm.BatchGetSecretValueOutput, error) {
+		batches = append(batches, in.SecretIdList)
+		return &awssm.BatchGetSecretValueOutput{
+			SecretValues: []types.SecretValueEntry{
+				{Name: aws.String("foo"), ARN: aws.String("arn:foo"), SecretString: aws.String(`{"user":"admin","password":"secret"}`)},
+				{Name: aws.String("bar"), ARN: aws.String("arn:bar"), SecretString: aws.String("plain")},
+			},
+		}, nil
+	}
+	var gets []string
+	fakeClient.GetSecretValueFn = func(_ context.Context, in *awssm.GetSecretValueInput, _ ...func(*awssm.Options)) (*awssm.GetSecretValueOutput, error) {
+		gets = append(gets, aws.ToString(in.SecretId))
+		if aws.ToString(in.SecretId) == "missing" {
+			return nil, &types.ResourceNotFoundException{}
+		}
+		return &awssm.GetSecretValueOutput{Name: in.SecretId, SecretString: aws.String("versioned")}, nil
+	}
+	sm := SecretsManager{
+		cache:  make(map[string]*awssm.GetSecretValueOutput),
+		client: fakeClient,
+	}
+
+	refs := []esv1.ExternalSecretDataRemoteRef{
+		{Key: "foo", Property: "user"},
+		{Key: "foo", Property: "password"},
+		{Key: "arn:bar"},
+		{Key: "foo", Version: "uuid-1"},
+		{Key: "missing"},
+	}
+	secrets, errs, err := sm.GetSecrets(context.Background(), refs)
+	require.NoError(t, err)
+	assert.Equal(t, [][]byte{[]byte("admin"), []byte("secret"), []byte("plain"), []byte("versioned"), nil}, secrets)
+	assert.Equal(t, []error{nil, nil, nil, nil, esv1.NoSecretErr}, errs)
+	// every secret is requested once, specific versions and secrets missing from the batch are fetched one by one
+	assert.Equal(t, [][]string{{"foo", "arn:bar", "missing"}}, batches)
+	assert.Equal(t, []string{"foo", "missing"}, gets)
+
+	// cached secrets are not requested again
+	_, _, err = sm.GetSecrets(context.Background(), refs[:3])
+	require.NoError(t, err)
+	assert.Len(t, batches, 1)
+
+	// if the batch fails, e.g. because it is not allowed, the secrets are fetched one by one
+	fakeClient.BatchGetSecretValueFn = func(_ context.Context, _ *awssm.BatchGetSecretValueInput, _ ...func(*awssm.Options)) (*awssm.BatchGetSecretValueOutput, error) {
+		return nil, errors.New("access denied")
+	}
+	gets = nil
+	secrets, errs, err = sm.GetSecrets(context.Background(), []esv1.ExternalSecretDataRemoteRef{{Key: "baz"}})
+	require.NoError(t, err)
+	assert.Equal(t, [][]byte{[]byte("versioned")}, secrets)
+	assert.Equal(t, []error{nil}, errs)
+	assert.Equal(t, []string{"baz"}, gets)
+}
+
 func TestGetSecretMap(t *testing.T) {
 	// good case: default version & deserialization
 	setDeserialization := func(smtc *secretsManagerTestCase) {
@@ -1993,3 +2051,74 @@ func (f *FakeCredProvider) Retrieve(ctx context.Context) (aws.Credentials, error
 func (f *FakeCredProvider) IsExpired() bool {
 	return true
 }
+
+// memorySecretsManager is an in-memory Secrets Manager which keeps the current version of each secret.
+type memorySecretsManager struct {
+	SMInterface
+	secrets map[string]*awssm.GetSecretValueOutput
+	tags    map[string][]types.Tag
+}
+
+func (m *memorySecretsManager) GetSecretValue(_ context.Context, in *awssm.GetSecretValueInput, _ ...func(*awssm.Options)) (*awssm.GetSecretValueOutput, error) {
+	secret, ok := m.secrets[*in.SecretId]
+	if !ok {
+		return nil, &types.ResourceNotFoundException{}
+	}
+	return secret, nil
+}
+
+func (m *memorySecretsManager) DescribeSecret(_ context.Context, in *awssm.DescribeSecretInput, _ ...func(*awssm.Options)) (*awssm.DescribeSecretOutput, error) {
+	secret, ok := m.secrets[*in.SecretId]
+	if !ok {
+		return nil, &types.ResourceNotFoundException{}
+	}
+	return &awssm.DescribeSecretOutput{
+		Name:               secret.Name,
+		Tags:               m.tags[*in.SecretId],
+		VersionIdsToStages: map[string][]string{*secret.VersionId: {"AWSCURRENT"}},
+	}, nil
+}
+
+func (m *memorySecretsManager) CreateSecret(_ context.Context, in *awssm.CreateSecretInput, _ ...func(*awssm.Options)) (*awssm.CreateSecretOutput, error) {
+	m.secrets[*in.Name] = &awssm.GetSecretValueOutput{
+		Name:         in.Name,
+		SecretBinary: in.SecretBinary,
+		SecretString: in.SecretString,
+		VersionId:    in.ClientRequestToken,
+	}
+	m.tags[*in.Name] = in.Tags
+	return &awssm.CreateSecretOutput{Name: in.Name}, nil
+}
+
+func (m *memorySecretsManager) PutSecretValue(_ context.Context, in *awssm.PutSecretValueInput, _ ...func(*awssm.Options)) (*awssm.PutSecretValueOutput, error) {
+	m.secrets[*in.SecretId] = &awssm.GetSecretValueOutput{
+		Name:         in.SecretId,
+		SecretBinary: in.SecretBinary,
+		SecretString: in.SecretString,
+		VersionId:    in.ClientRequestToken,
+	}
+	return &awssm.PutSecretValueOutput{}, nil
+}
+
+func (m *memorySecretsManager) TagResource(_ context.Context, in *awssm.TagResourceInput, _ ...func(*awssm.Options)) (*awssm.TagResourceOutput, error) {
+	m.tags[*in.SecretId] = append(m.tags[*in.SecretId], in.Tags...)
+	return &awssm.TagResourceOutput{}, nil
+}
+
+func TestPushSecretConformance(t *testing.T) {
+	for _, property := range []string{"", "conformance"} {
+		t.Run("property="+property, func(t *testing.T) {
+			sm := &SecretsManager{
+				client: &memorySecretsManager{
+					secrets: make(map[string]*awssm.GetSecretValueOutput),
+					tags:    make(map[string][]types.Tag),
+				},
+				cache: make(map[string]*awssm.GetSecretValueOutput),
+			}
+			conformance.RunPushSecret(t, sm, conformance.PushSecretRef{
+				RemoteKey: "conformance-secret",
+				Property:  property,
+			})
+		})
+	}
+}
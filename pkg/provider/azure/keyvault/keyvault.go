@@ -99,6 +99,8 @@ const (
 
 // https://github.com/external-secrets/external-secrets/issues/644
 var _ esv1.SecretsClient = &Azure{}
+var _ esv1.PoolableClient = &Azure{}
+var _ esv1.MetadataSecretsClient = &Azure{}
 var _ esv1.Provider = &Azure{}
 
 // interface to keyvault.BaseClient.
@@ -734,22 +736,52 @@ func parseError(err error) error {
 // Retrieves a secret/Key/Certificate/Tag with the secret name defined in ref.Name
 // The Object Type is defined as a prefix in the ref.Name , if no prefix is defined , we assume a secret is required.
 func (a *Azure) GetSecret(ctx context.Context, ref esv1.ExternalSecretDataRemoteRef) ([]byte, error) {
+	secret, _, err := a.GetSecretWithMetadata(ctx, ref)
+	return secret, err
+}
+
+// GetSecretWithMetadata returns the secret along with the version id, the update time and the expiry of its version.
+// Certificates and keys have no metadata.
+func (a *Azure) GetSecretWithMetadata(ctx context.Context, ref esv1.ExternalSecretDataRemoteRef) ([]byte, *esv1.SecretMetadata, error) {
 	objectType, secretName := getObjType(ref)
+	if objectType != defaultObjType {
+		secret, err := a.getObject(ctx, ref, objectType, secretName)
+		return secret, nil, err
+	}
+
+	// returns a SecretBundle with the secret value
+	// https://pkg.go.dev/github.com/Azure/azure-sdk-for-go/services/keyvault/v7.0/keyvault#SecretBundle
+	secretResp, err := a.baseClient.GetSecret(ctx, *a.provider.VaultURL, secretName, ref.Version)
+	metrics.ObserveAPICall(constants.ProviderAzureKV, constants.CallAzureKVGetSecret, err)
+	err = parseError(err)
+	if err != nil {
+		return nil, nil, err
+	}
+	var secret []byte
+	if ref.MetadataPolicy == esv1.ExternalSecretMetadataPolicyFetch {
+		secret, err = getSecretTag(secretResp.Tags, ref.Property)
+	} else {
+		secret, err = getProperty(*secretResp.Value, ref.Property, ref.Key)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	versionMetadata := &esv1.SecretMetadata{}
+	if secretResp.ID != nil {
+		versionMetadata.Version = path.Base(*secretResp.ID)
+	}
+	if secretResp.Attributes != nil && secretResp.Attributes.Updated != nil {
+		versionMetadata.LastModified = time.Time(*secretResp.Attributes.Updated)
+	}
+	if secretResp.Attributes != nil && secretResp.Attributes.Expires != nil {
+		versionMetadata.Expires = time.Time(*secretResp.Attributes.Expires)
+	}
+	return secret, versionMetadata, nil
+}
 
+// getObject returns a certificate or a key.
+func (a *Azure) getObject(ctx context.Context, ref esv1.ExternalSecretDataRemoteRef, objectType, secretName string) ([]byte, error) {
 	switch objectType {
-	case defaultObjType:
-		// returns a SecretBundle with the secret value
-		// https://pkg.go.dev/github.com/Azure/azure-sdk-for-go/services/keyvault/v7.0/keyvault#SecretBundle
-		secretResp, err := a.baseClient.GetSecret(ctx, *a.provider.VaultURL, secretName, ref.Version)
-		metrics.ObserveAPICall(constants.ProviderAzureKV, constants.CallAzureKVGetSecret, err)
-		err = parseError(err)
-		if err != nil {
-			return nil, err
-		}
-		if ref.MetadataPolicy == esv1.ExternalSecretMetadataPolicyFetch {
-			return getSecretTag(secretResp.Tags, ref.Property)
-		}
-		return getProperty(*secretResp.Value, ref.Property, ref.Key)
 	case objectTypeCert:
 		// returns a CertBundle. We return CER contents of x509 certificate
 		// see: https://pkg.go.dev/github.com/Azure/azure-sdk-for-go/services/keyvault/v7.0/keyvault#CertificateBundle
@@ -1136,6 +1168,9 @@ func getAuthorizerForClientCertificate(clientID string, certificateBytes []byte,
 	return clientCertificateConfig.Authorizer()
 }
 
+// Reset implements esv1.PoolableClient, the client has no state to reset.
+func (a *Azure) Reset() {}
+
 func (a *Azure) Close(_ context.Context) error {
 	return nil
 }
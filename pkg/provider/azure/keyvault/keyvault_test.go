@@ -35,6 +35,7 @@ import (
 	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
 	v1 "github.com/external-secrets/external-secrets/apis/meta/v1"
 	"github.com/external-secrets/external-secrets/pkg/provider/azure/keyvault/fake"
+	"github.com/external-secrets/external-secrets/pkg/provider/testing/conformance"
 	testingfake "github.com/external-secrets/external-secrets/pkg/provider/testing/fake"
 	"github.com/external-secrets/external-secrets/pkg/utils"
 	"github.com/external-secrets/external-secrets/pkg/utils/metadata"
@@ -1860,3 +1861,37 @@ func TestAzureKeyVaultSecretExists(t *testing.T) {
 		}
 	}
 }
+
+// memoryKeyVault is an in-memory Key Vault which keeps the current version of each secret.
+type memoryKeyVault struct {
+	SecretClient
+	secrets map[string]keyvault.SecretBundle
+}
+
+func (m *memoryKeyVault) GetSecret(_ context.Context, _, secretName, _ string) (keyvault.SecretBundle, error) {
+	secret, ok := m.secrets[secretName]
+	if !ok {
+		return keyvault.SecretBundle{}, autorest.DetailedError{StatusCode: 404}
+	}
+	return secret, nil
+}
+
+func (m *memoryKeyVault) SetSecret(_ context.Context, _, secretName string, parameters keyvault.SecretSetParameters) (keyvault.SecretBundle, error) {
+	secret := keyvault.SecretBundle{
+		Value:      parameters.Value,
+		Tags:       parameters.Tags,
+		Attributes: parameters.SecretAttributes,
+	}
+	m.secrets[secretName] = secret
+	return secret, nil
+}
+
+func TestPushSecretConformance(t *testing.T) {
+	az := &Azure{
+		baseClient: &memoryKeyVault{secrets: make(map[string]keyvault.SecretBundle)},
+		provider:   &esv1.AzureKVProvider{VaultURL: pointer.To(fakeURL)},
+	}
+	conformance.RunPushSecret(t, az, conformance.PushSecretRef{
+		RemoteKey: "conformance-secret",
+	})
+}
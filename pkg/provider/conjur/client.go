@@ -18,9 +18,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
 
 	"github.com/cyberark/conjur-api-go/conjurapi"
 	"github.com/cyberark/conjur-api-go/conjurapi/authn"
+	"github.com/cyberark/conjur-api-go/conjurapi/response"
+	"github.com/tidwall/gjson"
 	corev1 "k8s.io/api/core/v1"
 	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -98,8 +101,24 @@ func (c *Client) DeleteSecret(_ context.Context, _ esv1.PushSecretRemoteRef) err
 	return nil
 }
 
-func (c *Client) SecretExists(_ context.Context, _ esv1.PushSecretRemoteRef) (bool, error) {
-	return false, errors.New("not implemented")
+// SecretExists reports whether the variable exists and, if a property is given, whether its JSON value contains it.
+func (c *Client) SecretExists(ctx context.Context, ref esv1.PushSecretRemoteRef) (bool, error) {
+	conjurClient, err := c.GetConjurClient(ctx)
+	if err != nil {
+		return false, err
+	}
+	secretValue, err := conjurClient.RetrieveSecret(ref.GetRemoteKey())
+	var conjurErr *response.ConjurError
+	if errors.As(err, &conjurErr) && conjurErr.Code == http.StatusNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if ref.GetProperty() == "" {
+		return true, nil
+	}
+	return gjson.Get(string(secretValue), ref.GetProperty()).Exists(), nil
 }
 
 // Validate validates the provider.
@@ -18,8 +18,10 @@ import (
 	"errors"
 	"fmt"
 	"math/rand"
+	"net/http"
 
 	"github.com/cyberark/conjur-api-go/conjurapi"
+	"github.com/cyberark/conjur-api-go/conjurapi/response"
 )
 
 type ConjurMockClient struct {
@@ -30,6 +32,9 @@ func (mc *ConjurMockClient) RetrieveSecret(secret string) (result []byte, err er
 		err = errors.New("error")
 		return nil, err
 	}
+	if secret == "missing" {
+		return nil, &response.ConjurError{Code: http.StatusNotFound, Message: "Not Found"}
+	}
 	if secret == "json_map" {
 		return []byte(`{"key1":"value1","key2":"value2"}`), nil
 	}
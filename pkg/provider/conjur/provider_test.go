@@ -35,6 +35,7 @@ import (
 	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
 	esmeta "github.com/external-secrets/external-secrets/apis/meta/v1"
 	"github.com/external-secrets/external-secrets/pkg/provider/conjur/fake"
+	testingfake "github.com/external-secrets/external-secrets/pkg/provider/testing/fake"
 	utilfake "github.com/external-secrets/external-secrets/pkg/provider/util/fake"
 )
 
@@ -439,6 +440,50 @@ func TestGetSecretMap(t *testing.T) {
 	}
 }
 
+func TestSecretExists(t *testing.T) {
+	cases := map[string]struct {
+		ref     testingfake.PushSecretData
+		want    bool
+		wantErr bool
+	}{
+		"Exists": {
+			ref:  testingfake.PushSecretData{RemoteKey: "path/to/secret"},
+			want: true,
+		},
+		"PropertyExists": {
+			ref:  testingfake.PushSecretData{RemoteKey: "json_map", Property: "key1"},
+			want: true,
+		},
+		"PropertyMissing": {
+			ref:  testingfake.PushSecretData{RemoteKey: "json_map", Property: "key3"},
+			want: false,
+		},
+		"Missing": {
+			ref:  testingfake.PushSecretData{RemoteKey: "missing"},
+			want: false,
+		},
+		"Error": {
+			ref:     testingfake.PushSecretData{RemoteKey: "error"},
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			kube := clientfake.NewClientBuilder().WithObjects(makeFakeAPIKeySecrets()...).Build()
+			store := makeAPIKeySecretStore(svcURL, "conjur-hostid", "conjur-apikey", "myconjuraccount")
+			provider, _ := newConjurProvider(context.Background(), store, kube, "default", nil, &ConjurMockAPIClient{})
+			got, err := provider.SecretExists(context.Background(), tc.ref)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("conjur.SecretExists(...): error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("conjur.SecretExists(...): want %v got %v", tc.want, got)
+			}
+		})
+	}
+}
+
 func TestGetCA(t *testing.T) {
 	type args struct {
 		store     esv1.GenericStore
@@ -17,7 +17,6 @@ package doppler
 import (
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"net/url"
 	"strings"
@@ -119,8 +118,19 @@ func (c *Client) DeleteSecret(_ context.Context, ref esv1.PushSecretRemoteRef) e
 	return nil
 }
 
-func (c *Client) SecretExists(_ context.Context, _ esv1.PushSecretRemoteRef) (bool, error) {
-	return false, errors.New("not implemented")
+func (c *Client) SecretExists(_ context.Context, ref esv1.PushSecretRemoteRef) (bool, error) {
+	_, err := c.doppler.GetSecret(dClient.SecretRequest{
+		Name:    ref.GetRemoteKey(),
+		Project: c.project,
+		Config:  c.config,
+	})
+	if dClient.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf(errGetSecret, ref.GetRemoteKey(), err)
+	}
+	return true, nil
 }
 
 func (c *Client) PushSecret(_ context.Context, secret *corev1.Secret, data esv1.PushSecretData) error {
@@ -18,6 +18,7 @@ import (
 	"bytes"
 	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -52,6 +53,8 @@ type APIError struct {
 	Err     error
 	Message string
 	Data    string
+	// StatusCode is the status of the response, if the API returned an error.
+	StatusCode int
 }
 
 type apiResponse struct {
@@ -163,7 +166,7 @@ func (c *DopplerClient) GetSecret(request SecretRequest) (*SecretResponse, error
 	}
 
 	if data.Value.Computed == nil {
-		return nil, &APIError{Message: fmt.Sprintf("secret '%s' not found", request.Name)}
+		return nil, &APIError{Message: fmt.Sprintf("secret '%s' not found", request.Name), StatusCode: http.StatusNotFound}
 	}
 
 	return &SecretResponse{Name: data.Name, Value: *data.Value.Computed}, nil
@@ -329,9 +332,9 @@ func (c *DopplerClient) performRequest(path, method string, headers headers, par
 			if err != nil {
 				return response, &APIError{Err: err, Message: "unable to unmarshal error JSON payload"}
 			}
-			return response, &APIError{Err: nil, Message: strings.Join(errResponse.Messages, "\n")}
+			return response, &APIError{Err: nil, Message: strings.Join(errResponse.Messages, "\n"), StatusCode: r.StatusCode}
 		}
-		return nil, &APIError{Err: fmt.Errorf("%d status code; %d bytes", r.StatusCode, len(bodyResponse)), Message: "unable to load response"}
+		return nil, &APIError{Err: fmt.Errorf("%d status code; %d bytes", r.StatusCode, len(bodyResponse)), Message: "unable to load response", StatusCode: r.StatusCode}
 	}
 
 	if success && err != nil {
@@ -344,6 +347,12 @@ func isSuccess(statusCode int) bool {
 	return (statusCode >= 200 && statusCode <= 299) || (statusCode >= 300 && statusCode <= 399)
 }
 
+// IsNotFound returns true if the error is an APIError for a missing secret.
+func IsNotFound(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
 func (e *APIError) Error() string {
 	message := fmt.Sprintf("Doppler API Client Error: %s", e.Message)
 	if underlyingError := e.Err; underlyingError != nil {
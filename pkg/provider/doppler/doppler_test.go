@@ -17,6 +17,7 @@ package doppler
 import (
 	"context"
 	"errors"
+	"net/http"
 	"strings"
 	"testing"
 
@@ -28,6 +29,7 @@ import (
 	v1 "github.com/external-secrets/external-secrets/apis/meta/v1"
 	"github.com/external-secrets/external-secrets/pkg/provider/doppler/client"
 	"github.com/external-secrets/external-secrets/pkg/provider/doppler/fake"
+	"github.com/external-secrets/external-secrets/pkg/provider/testing/conformance"
 )
 
 const (
@@ -376,6 +378,43 @@ func TestPushSecret(t *testing.T) {
 	}
 }
 
+// memoryDopplerClient keeps the secrets of a single config in memory.
+type memoryDopplerClient struct {
+	fake.DopplerClient
+	secrets client.Secrets
+}
+
+func (m *memoryDopplerClient) GetSecret(request client.SecretRequest) (*client.SecretResponse, error) {
+	value, ok := m.secrets[request.Name]
+	if !ok {
+		return nil, &client.APIError{Message: "secret not found", StatusCode: http.StatusNotFound}
+	}
+	return &client.SecretResponse{Name: request.Name, Value: value}, nil
+}
+
+func (m *memoryDopplerClient) GetSecrets(_ client.SecretsRequest) (*client.SecretsResponse, error) {
+	return &client.SecretsResponse{Secrets: m.secrets, Modified: true}, nil
+}
+
+func (m *memoryDopplerClient) UpdateSecrets(request client.UpdateSecretsRequest) error {
+	for name, value := range request.Secrets {
+		m.secrets[name] = value
+	}
+	for _, change := range request.ChangeRequests {
+		if change.ShouldDelete {
+			delete(m.secrets, change.Name)
+		}
+	}
+	return nil
+}
+
+func TestPushSecretConformance(t *testing.T) {
+	c := Client{doppler: &memoryDopplerClient{secrets: client.Secrets{}}}
+	conformance.RunPushSecret(t, &c, conformance.PushSecretRef{
+		RemoteKey: validRemoteKey,
+	})
+}
+
 type storeModifier func(*esv1.SecretStore) *esv1.SecretStore
 
 func makeSecretStore(fn ...storeModifier) *esv1.SecretStore {
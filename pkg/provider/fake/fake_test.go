@@ -29,6 +29,7 @@ import (
 
 	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
 	esv1alpha1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1alpha1"
+	"github.com/external-secrets/external-secrets/pkg/provider/testing/conformance"
 	testingfake "github.com/external-secrets/external-secrets/pkg/provider/testing/fake"
 )
 
@@ -393,6 +394,26 @@ func TestSetSecret(t *testing.T) {
 	}
 }
 
+func TestPushSecretConformance(t *testing.T) {
+	p := &Provider{}
+	cl, err := p.NewClient(context.Background(), &esv1.SecretStore{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "secret-store-conformance",
+		},
+		Spec: esv1.SecretStoreSpec{
+			Provider: &esv1.SecretStoreProvider{
+				Fake: &esv1.FakeProvider{},
+			},
+		},
+	}, nil, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	conformance.RunPushSecret(t, cl, conformance.PushSecretRef{
+		RemoteKey: "/foo",
+	})
+}
+
 type secretExistsTestCase struct {
 	name      string
 	input     []esv1.FakeProviderData
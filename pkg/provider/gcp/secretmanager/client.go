@@ -20,6 +20,7 @@ import (
 	"errors"
 	"fmt"
 	"maps"
+	"path"
 	"slices"
 	"strconv"
 	"strings"
@@ -477,12 +478,20 @@ func (c *Client) extractProjectIDNumber(secretFullName string) string {
 
 // GetSecret returns a single secret from the provider.
 func (c *Client) GetSecret(ctx context.Context, ref esv1.ExternalSecretDataRemoteRef) ([]byte, error) {
+	secret, _, err := c.GetSecretWithMetadata(ctx, ref)
+	return secret, err
+}
+
+// GetSecretWithMetadata returns the secret along with the number of the version it was read from.
+// The creation time of the version is not returned, as it would need another call.
+func (c *Client) GetSecretWithMetadata(ctx context.Context, ref esv1.ExternalSecretDataRemoteRef) ([]byte, *esv1.SecretMetadata, error) {
 	if utils.IsNil(c.smClient) || c.store.ProjectID == "" {
-		return nil, errors.New(errUninitalizedGCPProvider)
+		return nil, nil, errors.New(errUninitalizedGCPProvider)
 	}
 
 	if ref.MetadataPolicy == esv1.ExternalSecretMetadataPolicyFetch {
-		return c.getSecretMetadata(ctx, ref)
+		secret, err := c.getSecretMetadata(ctx, ref)
+		return secret, nil, err
 	}
 
 	version := ref.Version
@@ -500,21 +509,22 @@ func (c *Client) GetSecret(ctx context.Context, ref esv1.ExternalSecretDataRemot
 	metrics.ObserveAPICall(constants.ProviderGCPSM, constants.CallGCPSMAccessSecretVersion, err)
 	err = parseError(err)
 	if err != nil {
-		return nil, fmt.Errorf(errClientGetSecretAccess, err)
+		return nil, nil, fmt.Errorf(errClientGetSecretAccess, err)
 	}
+	versionMetadata := &esv1.SecretMetadata{Version: path.Base(result.GetName())}
 
 	if ref.Property == "" {
 		if result.Payload.Data != nil {
-			return result.Payload.Data, nil
+			return result.Payload.Data, versionMetadata, nil
 		}
-		return nil, fmt.Errorf("invalid secret received. no secret string for key: %s", ref.Key)
+		return nil, nil, fmt.Errorf("invalid secret received. no secret string for key: %s", ref.Key)
 	}
 
 	val := getDataByProperty(result.Payload.Data, ref.Property)
 	if !val.Exists() {
-		return nil, fmt.Errorf("key %s does not exist in secret %s", ref.Property, ref.Key)
+		return nil, nil, fmt.Errorf("key %s does not exist in secret %s", ref.Property, ref.Key)
 	}
-	return []byte(val.String()), nil
+	return []byte(val.String()), versionMetadata, nil
 }
 
 func (c *Client) getSecretMetadata(ctx context.Context, ref esv1.ExternalSecretDataRemoteRef) ([]byte, error) {
@@ -37,6 +37,7 @@ import (
 	"github.com/external-secrets/external-secrets/apis/externalsecrets/v1alpha1"
 	v1 "github.com/external-secrets/external-secrets/apis/meta/v1"
 	fakesm "github.com/external-secrets/external-secrets/pkg/provider/gcp/secretmanager/fake"
+	"github.com/external-secrets/external-secrets/pkg/provider/testing/conformance"
 	testingfake "github.com/external-secrets/external-secrets/pkg/provider/testing/fake"
 )
 
@@ -1385,3 +1386,67 @@ func TestValidateStore(t *testing.T) {
 		})
 	}
 }
+
+// memorySMClient is an in-memory Secret Manager which keeps the versions of each secret.
+type memorySMClient struct {
+	GoogleSecretManagerClient
+	secrets  map[string]*secretmanagerpb.Secret
+	versions map[string][][]byte
+}
+
+func (m *memorySMClient) GetSecret(_ context.Context, req *secretmanagerpb.GetSecretRequest, _ ...gax.CallOption) (*secretmanagerpb.Secret, error) {
+	secret, ok := m.secrets[req.Name]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "secret not found")
+	}
+	return secret, nil
+}
+
+func (m *memorySMClient) CreateSecret(_ context.Context, req *secretmanagerpb.CreateSecretRequest, _ ...gax.CallOption) (*secretmanagerpb.Secret, error) {
+	secret := &secretmanagerpb.Secret{
+		Name:   fmt.Sprintf("%s/secrets/%s", req.Parent, req.SecretId),
+		Labels: req.Secret.Labels,
+	}
+	m.secrets[secret.Name] = secret
+	return secret, nil
+}
+
+func (m *memorySMClient) UpdateSecret(_ context.Context, req *secretmanagerpb.UpdateSecretRequest, _ ...gax.CallOption) (*secretmanagerpb.Secret, error) {
+	m.secrets[req.Secret.Name] = req.Secret
+	return req.Secret, nil
+}
+
+func (m *memorySMClient) AccessSecretVersion(_ context.Context, req *secretmanagerpb.AccessSecretVersionRequest, _ ...gax.CallOption) (*secretmanagerpb.AccessSecretVersionResponse, error) {
+	name, _, _ := strings.Cut(req.Name, "/versions/")
+	versions := m.versions[name]
+	if len(versions) == 0 {
+		return nil, status.Error(codes.NotFound, "version not found")
+	}
+	return &secretmanagerpb.AccessSecretVersionResponse{
+		Name:    fmt.Sprintf("%s/versions/%d", name, len(versions)),
+		Payload: &secretmanagerpb.SecretPayload{Data: versions[len(versions)-1]},
+	}, nil
+}
+
+func (m *memorySMClient) AddSecretVersion(_ context.Context, req *secretmanagerpb.AddSecretVersionRequest, _ ...gax.CallOption) (*secretmanagerpb.SecretVersion, error) {
+	m.versions[req.Parent] = append(m.versions[req.Parent], req.Payload.Data)
+	return &secretmanagerpb.SecretVersion{Name: fmt.Sprintf("%s/versions/%d", req.Parent, len(m.versions[req.Parent]))}, nil
+}
+
+func TestPushSecretConformance(t *testing.T) {
+	for _, property := range []string{"", "conformance"} {
+		t.Run("property="+property, func(t *testing.T) {
+			client := &Client{
+				smClient: &memorySMClient{
+					secrets:  make(map[string]*secretmanagerpb.Secret),
+					versions: make(map[string][][]byte),
+				},
+				store: &esv1.GCPSMProvider{ProjectID: "foo"},
+			}
+			conformance.RunPushSecret(t, client, conformance.PushSecretRef{
+				RemoteKey: "conformance-secret",
+				Property:  property,
+			})
+		})
+	}
+}
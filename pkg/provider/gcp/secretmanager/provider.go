@@ -36,6 +36,7 @@ type Provider struct{}
 
 // https://github.com/external-secrets/external-secrets/issues/644
 var _ esv1.SecretsClient = &Client{}
+var _ esv1.MetadataSecretsClient = &Client{}
 var _ esv1.Provider = &Provider{}
 
 func init() {
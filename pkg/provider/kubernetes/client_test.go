@@ -31,6 +31,7 @@ import (
 
 	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
 	"github.com/external-secrets/external-secrets/apis/externalsecrets/v1alpha1"
+	"github.com/external-secrets/external-secrets/pkg/provider/testing/conformance"
 	testingfake "github.com/external-secrets/external-secrets/pkg/provider/testing/fake"
 )
 
@@ -1373,3 +1374,14 @@ func TestPushSecret(t *testing.T) {
 		})
 	}
 }
+
+func TestPushSecretConformance(t *testing.T) {
+	p := &Client{
+		userSecretClient: &fakeClient{t: t, secretMap: map[string]*v1.Secret{}},
+		store:            &esv1.KubernetesProvider{},
+	}
+	conformance.RunPushSecret(t, p, conformance.PushSecretRef{
+		RemoteKey: "mysec",
+		Property:  "token",
+	})
+}
@@ -31,6 +31,7 @@ import (
 	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
 	esmeta "github.com/external-secrets/external-secrets/apis/meta/v1"
 	"github.com/external-secrets/external-secrets/pkg/provider/onepassword/fake"
+	"github.com/external-secrets/external-secrets/pkg/provider/testing/conformance"
 	"github.com/external-secrets/external-secrets/pkg/utils/metadata"
 )
 
@@ -2406,3 +2407,22 @@ func TestProviderOnePasswordPushSecret(t *testing.T) {
 		})
 	}
 }
+
+func TestPushSecretConformance(t *testing.T) {
+	mockClient := fake.NewMockClient().AddPredictableVaultUUID(myVault)
+	mockClient.CreateItemValidateFunc = func(item *onepassword.Item, _ string) (*onepassword.Item, error) {
+		item.ID = item.Title + "-id"
+		return item, nil
+	}
+	mockClient.UpdateItemValidateFunc = func(item *onepassword.Item, _ string) (*onepassword.Item, error) {
+		return item, nil
+	}
+	provider := &ProviderOnePassword{
+		vaults: map[string]int{myVault: 1},
+		client: mockClient,
+	}
+	conformance.RunPushSecret(t, provider, conformance.PushSecretRef{
+		RemoteKey: myItem,
+		Property:  key1,
+	})
+}
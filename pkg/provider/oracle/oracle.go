@@ -171,8 +171,18 @@ func (vms *VaultManagementService) DeleteSecret(ctx context.Context, remoteRef e
 	}
 }
 
-func (vms *VaultManagementService) SecretExists(_ context.Context, _ esv1.PushSecretRemoteRef) (bool, error) {
-	return false, errors.New("not implemented")
+// SecretExists reports whether the secret exists. Secrets scheduled for deletion are reported as missing,
+// the same way DeleteSecret treats them as already deleted.
+func (vms *VaultManagementService) SecretExists(ctx context.Context, remoteRef esv1.PushSecretRemoteRef) (bool, error) {
+	resp, action, err := vms.getSecretBundleWithCode(ctx, remoteRef.GetRemoteKey())
+	switch action {
+	case SecretNotFound:
+		return false, nil
+	case SecretExists:
+		return resp.TimeOfDeletion == nil, nil
+	default:
+		return false, sanitizeOCISDKErr(err)
+	}
 }
 
 func (vms *VaultManagementService) GetAllSecrets(ctx context.Context, ref esv1.ExternalSecretFind) (map[string][]byte, error) {
@@ -41,6 +41,7 @@ import (
 	esv1alpha1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1alpha1"
 	esmeta "github.com/external-secrets/external-secrets/apis/meta/v1"
 	fakeoracle "github.com/external-secrets/external-secrets/pkg/provider/oracle/fake"
+	"github.com/external-secrets/external-secrets/pkg/provider/testing/conformance"
 	testingfake "github.com/external-secrets/external-secrets/pkg/provider/testing/fake"
 )
 
@@ -743,6 +744,41 @@ func TestOracleVaultDeleteSecret(t *testing.T) {
 	}
 }
 
+func TestOracleVaultSecretExists(t *testing.T) {
+	vms := &VaultManagementService{
+		Client: &fakeoracle.OracleMockClient{
+			SecretBundles: map[string]secrets.SecretBundle{
+				s1id: s1bundle,
+				s3id: s3bundle,
+			},
+		},
+	}
+	var testCases = map[string]struct {
+		remoteKey string
+		want      bool
+	}{
+		"secret exists": {
+			remoteKey: s1id,
+			want:      true,
+		},
+		"secret not found": {
+			remoteKey: s2id,
+			want:      false,
+		},
+		"secret is deleting": {
+			remoteKey: s3id,
+			want:      false,
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			exists, err := vms.SecretExists(context.Background(), esv1alpha1.PushSecretRemoteRef{RemoteKey: testCase.remoteKey})
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.want, exists)
+		})
+	}
+}
+
 var (
 	s1id      = "test1"
 	s2id      = "mysecret"
@@ -784,3 +820,55 @@ func makeSecretSummary(id string, deleting bool) vault.SecretSummary {
 		TimeOfDeletion: deletionTime,
 	}
 }
+
+// memoryVault is an in-memory OCI Vault which keeps the current content of each secret.
+type memoryVault struct {
+	VaultInterface
+	// contents maps the ids of the secrets to their base64 encoded contents.
+	contents map[string]string
+	// ids maps the names of the secrets to their ids.
+	ids map[string]string
+}
+
+func (m *memoryVault) GetSecretBundleByName(_ context.Context, request secrets.GetSecretBundleByNameRequest) (secrets.GetSecretBundleByNameResponse, error) {
+	id, ok := m.ids[*request.SecretName]
+	if !ok {
+		return secrets.GetSecretBundleByNameResponse{}, &fakeoracle.ServiceError{Code: 404}
+	}
+	content := m.contents[id]
+	return secrets.GetSecretBundleByNameResponse{
+		SecretBundle: secrets.SecretBundle{
+			SecretId:            &id,
+			SecretBundleContent: secrets.Base64SecretBundleContentDetails{Content: &content},
+		},
+	}, nil
+}
+
+func (m *memoryVault) CreateSecret(_ context.Context, request vault.CreateSecretRequest) (vault.CreateSecretResponse, error) {
+	id := "ocid-" + *request.SecretName
+	m.ids[*request.SecretName] = id
+	m.contents[id] = *request.SecretContent.(vault.Base64SecretContentDetails).Content
+	return vault.CreateSecretResponse{}, nil
+}
+
+func (m *memoryVault) UpdateSecret(_ context.Context, request vault.UpdateSecretRequest) (vault.UpdateSecretResponse, error) {
+	m.contents[*request.SecretId] = *request.SecretContent.(vault.Base64SecretContentDetails).Content
+	return vault.UpdateSecretResponse{}, nil
+}
+
+func TestPushSecretConformance(t *testing.T) {
+	memory := &memoryVault{
+		contents: make(map[string]string),
+		ids:      make(map[string]string),
+	}
+	vms := &VaultManagementService{
+		Client:        memory,
+		VaultClient:   memory,
+		vault:         "vault",
+		compartment:   "compartment",
+		encryptionKey: "key",
+	}
+	conformance.RunPushSecret(t, vms, conformance.PushSecretRef{
+		RemoteKey: "conformance-secret",
+	})
+}
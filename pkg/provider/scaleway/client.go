@@ -264,8 +264,46 @@ func (c *client) DeleteSecret(ctx context.Context, remoteRef esv1.PushSecretRemo
 	return nil
 }
 
-func (c *client) SecretExists(_ context.Context, _ esv1.PushSecretRemoteRef) (bool, error) {
-	return false, errors.New("not implemented")
+func (c *client) SecretExists(ctx context.Context, remoteRef esv1.PushSecretRemoteRef) (bool, error) {
+	scwRef, err := decodeScwSecretRef(remoteRef.GetRemoteKey())
+	if err != nil {
+		return false, err
+	}
+
+	listSecretReq := &smapi.ListSecretsRequest{
+		ProjectID: &c.projectID,
+		Page:      scw.Int32Ptr(1),
+		PageSize:  scw.Uint32Ptr(1),
+	}
+
+	switch scwRef.RefType {
+	case refTypeID:
+		_, err := c.api.GetSecret(&smapi.GetSecretRequest{
+			SecretID: scwRef.Value,
+		}, scw.WithContext(ctx))
+		var errNotFound *scw.ResourceNotFoundError
+		if errors.As(err, &errNotFound) {
+			return false, nil
+		}
+		return err == nil, err
+	case refTypeName:
+		listSecretReq.Name = &scwRef.Value
+	case refTypePath:
+		name, path, ok := splitNameAndPath(scwRef.Value)
+		if !ok {
+			return false, errors.New("ref is not a path")
+		}
+		listSecretReq.Name = &name
+		listSecretReq.Path = &path
+	default:
+		return false, fmt.Errorf("invalid secret reference: %q", scwRef.Value)
+	}
+
+	listSecrets, err := c.api.ListSecrets(listSecretReq, scw.WithContext(ctx))
+	if err != nil {
+		return false, err
+	}
+	return len(listSecrets.Secrets) > 0, nil
 }
 
 func (c *client) Validate() (esv1.ValidationResult, error) {
@@ -19,10 +19,13 @@ import (
 	"fmt"
 	"testing"
 
+	smapi "github.com/scaleway/scaleway-sdk-go/api/secret/v1beta1"
+	"github.com/scaleway/scaleway-sdk-go/scw"
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
 
 	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	"github.com/external-secrets/external-secrets/pkg/provider/testing/conformance"
 	testingfake "github.com/external-secrets/external-secrets/pkg/provider/testing/fake"
 	"github.com/external-secrets/external-secrets/pkg/utils"
 )
@@ -479,3 +482,67 @@ func TestSplitNameAndPath(t *testing.T) {
 		})
 	}
 }
+
+func TestSecretExists(t *testing.T) {
+	ctx := context.Background()
+	c := newTestClient()
+
+	secret := db.secret("secret-2")
+	byPath := db.secret("nested-secret")
+
+	testCases := map[string]struct {
+		ref  testingfake.PushSecretData
+		want bool
+	}{
+		"By id": {
+			ref:  testingfake.PushSecretData{RemoteKey: "id:" + secret.id},
+			want: true,
+		},
+		"By name": {
+			ref:  testingfake.PushSecretData{RemoteKey: "name:" + secret.name},
+			want: true,
+		},
+		"By path": {
+			ref:  testingfake.PushSecretData{RemoteKey: "path:" + byPath.path + "/" + byPath.name},
+			want: true,
+		},
+		"Secret Not Found": {
+			ref:  testingfake.PushSecretData{RemoteKey: "name:not-a-secret"},
+			want: false,
+		},
+	}
+
+	for tcName, tc := range testCases {
+		t.Run(tcName, func(t *testing.T) {
+			got, err := c.SecretExists(ctx, tc.ref)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+// enabledVersionsAPI creates enabled versions like the Scaleway API does.
+// The shared fake leaves them without status, which the fixtures of the other tests depend on.
+type enabledVersionsAPI struct {
+	*fakeSecretAPI
+}
+
+func (f enabledVersionsAPI) CreateSecretVersion(request *smapi.CreateSecretVersionRequest, opts ...scw.RequestOption) (*smapi.SecretVersion, error) {
+	version, err := f.fakeSecretAPI.CreateSecretVersion(request, opts...)
+	if err != nil {
+		return nil, err
+	}
+	secret := f._secretsByID[request.SecretID]
+	secret.versions[len(secret.versions)-1].status = "enabled"
+	return version, nil
+}
+
+func TestPushSecretConformance(t *testing.T) {
+	c := &client{
+		api:   enabledVersionsAPI{buildDB(&fakeSecretAPI{})},
+		cache: newCache(),
+	}
+	conformance.RunPushSecret(t, c, conformance.PushSecretRef{
+		RemoteKey: "name:conformance-secret",
+	})
+}
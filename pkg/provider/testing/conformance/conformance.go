@@ -0,0 +1,84 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package conformance holds the tests shared by providers that support PushSecret.
+package conformance
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	"github.com/external-secrets/external-secrets/pkg/provider/testing/fake"
+)
+
+const secretKey = "conformance"
+
+// PushSecretRef is the remote ref the conformance test pushes to.
+// The backend of the client must not contain it yet.
+type PushSecretRef struct {
+	RemoteKey string
+	// Property is required by providers that push single keys into a property of the remote secret.
+	Property string
+}
+
+// RunPushSecret checks the contract the PushSecret update policies rely on:
+// SecretExists reports a missing secret without an error, PushSecret creates it,
+// SecretExists then reports it and GetSecret reads back the pushed value, also after it was updated.
+func RunPushSecret(t *testing.T, c esv1.SecretsClient, ref PushSecretRef) {
+	t.Helper()
+	ctx := context.Background()
+	data := fake.PushSecretData{
+		SecretKey: secretKey,
+		RemoteKey: ref.RemoteKey,
+		Property:  ref.Property,
+	}
+
+	exists, err := c.SecretExists(ctx, data)
+	if err != nil {
+		t.Fatalf("SecretExists() of a missing secret returned error: %v", err)
+	}
+	if exists {
+		t.Fatalf("SecretExists() of a missing secret = true, want false")
+	}
+
+	for _, value := range []string{"initial-value", "updated-value"} {
+		secret := &corev1.Secret{
+			Data: map[string][]byte{secretKey: []byte(value)},
+		}
+		if err := c.PushSecret(ctx, secret, data); err != nil {
+			t.Fatalf("PushSecret(%q) returned error: %v", value, err)
+		}
+		exists, err := c.SecretExists(ctx, data)
+		if err != nil {
+			t.Fatalf("SecretExists() of a pushed secret returned error: %v", err)
+		}
+		if !exists {
+			t.Fatalf("SecretExists() of a pushed secret = false, want true")
+		}
+		got, err := c.GetSecret(ctx, esv1.ExternalSecretDataRemoteRef{
+			Key:      ref.RemoteKey,
+			Property: ref.Property,
+		})
+		if err != nil {
+			t.Fatalf("GetSecret() of a pushed secret returned error: %v", err)
+		}
+		if !bytes.Equal(got, []byte(value)) {
+			t.Fatalf("GetSecret() = %q, want the pushed value %q", got, value)
+		}
+	}
+}
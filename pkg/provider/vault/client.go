@@ -35,6 +35,10 @@ import (
 )
 
 var _ esv1.SecretsClient = &client{}
+var _ esv1.PoolableClient = &client{}
+var _ esv1.BatchSecretsClient = &client{}
+var _ esv1.MetadataSecretsClient = &client{}
+var _ esv1.MetadataBatchSecretsClient = &client{}
 
 type client struct {
 	kube      kclient.Client
@@ -117,6 +121,10 @@ func (c *client) configureClientTLS(ctx context.Context, cfg *vault.Config) erro
 	return nil
 }
 
+// Reset implements esv1.PoolableClient, the client has no state to reset.
+// Its token is reused until the client is closed.
+func (c *client) Reset() {}
+
 func (c *client) Close(ctx context.Context) error {
 	// Revoke the token if we have one set, it wasn't sourced from a TokenSecretRef,
 	// and token caching isn't enabled
@@ -20,6 +20,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/tidwall/gjson"
 
@@ -45,32 +46,89 @@ const (
 //  2. get a key from the secret.
 //     Nested values are supported by specifying a gjson expression
 func (c *client) GetSecret(ctx context.Context, ref esv1.ExternalSecretDataRemoteRef) ([]byte, error) {
+	secret, _, err := c.GetSecretWithMetadata(ctx, ref)
+	return secret, err
+}
+
+// GetSecretWithMetadata returns the secret along with the version and the created_time of the version
+// it was read from. Secrets of KV v1 stores have no metadata.
+func (c *client) GetSecretWithMetadata(ctx context.Context, ref esv1.ExternalSecretDataRemoteRef) ([]byte, *esv1.SecretMetadata, error) {
 	var data map[string]any
+	var versionMetadata *esv1.SecretMetadata
 	var err error
 	if ref.MetadataPolicy == esv1.ExternalSecretMetadataPolicyFetch {
 		if c.store.Version == esv1.VaultKVStoreV1 {
-			return nil, errors.New(errUnsupportedMetadataKvVersion)
+			return nil, nil, errors.New(errUnsupportedMetadataKvVersion)
 		}
 
 		metadata, err := c.readSecretMetadata(ctx, ref.Key)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		if len(metadata) == 0 {
-			return nil, nil
+			return nil, nil, nil
 		}
 		data = make(map[string]any, len(metadata))
 		for k, v := range metadata {
 			data[k] = v
 		}
 	} else {
-		data, err = c.readSecret(ctx, ref.Key, ref.Version)
+		data, versionMetadata, err = c.readSecretWithMetadata(ctx, ref.Key, ref.Version)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 	}
 
-	return getSecretValue(data, ref.Property)
+	secret, err := getSecretValue(data, ref.Property)
+	if err != nil {
+		return nil, nil, err
+	}
+	return secret, versionMetadata, nil
+}
+
+// GetSecrets reads every secret referenced by refs once, so refs of different properties
+// of the same secret share a single request. Refs fetching metadata are fetched one by one.
+func (c *client) GetSecrets(ctx context.Context, refs []esv1.ExternalSecretDataRemoteRef) ([][]byte, []error, error) {
+	secrets, _, errs, err := c.GetSecretsWithMetadata(ctx, refs)
+	return secrets, errs, err
+}
+
+// GetSecretsWithMetadata reads the secrets like GetSecrets, along with the metadata of their versions.
+func (c *client) GetSecretsWithMetadata(ctx context.Context, refs []esv1.ExternalSecretDataRemoteRef) ([][]byte, []*esv1.SecretMetadata, []error, error) {
+	type secretVersion struct {
+		key     string
+		version string
+	}
+	type readResult struct {
+		data     map[string]any
+		metadata *esv1.SecretMetadata
+		err      error
+	}
+	read := make(map[secretVersion]readResult)
+	secrets := make([][]byte, len(refs))
+	metadata := make([]*esv1.SecretMetadata, len(refs))
+	errs := make([]error, len(refs))
+	for i, ref := range refs {
+		if ref.MetadataPolicy == esv1.ExternalSecretMetadataPolicyFetch {
+			secrets[i], errs[i] = c.GetSecret(ctx, ref)
+			continue
+		}
+		sv := secretVersion{key: ref.Key, version: ref.Version}
+		res, ok := read[sv]
+		if !ok {
+			res.data, res.metadata, res.err = c.readSecretWithMetadata(ctx, ref.Key, ref.Version)
+			read[sv] = res
+		}
+		if res.err != nil {
+			errs[i] = res.err
+			continue
+		}
+		secrets[i], errs[i] = getSecretValue(res.data, ref.Property)
+		if errs[i] == nil {
+			metadata[i] = res.metadata
+		}
+	}
+	return secrets, metadata, errs, nil
 }
 
 // GetSecretMap supports two modes of operation:
@@ -118,6 +176,11 @@ func (c *client) SecretExists(ctx context.Context, ref esv1.PushSecretRemoteRef)
 }
 
 func (c *client) readSecret(ctx context.Context, path, version string) (map[string]any, error) {
+	data, _, err := c.readSecretWithMetadata(ctx, path, version)
+	return data, err
+}
+
+func (c *client) readSecretWithMetadata(ctx context.Context, path, version string) (map[string]any, *esv1.SecretMetadata, error) {
 	dataPath := c.buildPath(path)
 
 	// path formated according to vault docs for v1 and v2 API
@@ -131,29 +194,49 @@ func (c *client) readSecret(ctx context.Context, path, version string) (map[stri
 	vaultSecret, err := c.logical.ReadWithDataWithContext(ctx, dataPath, params)
 	metrics.ObserveAPICall(constants.ProviderHCVault, constants.CallHCVaultReadSecretData, err)
 	if err != nil {
-		return nil, fmt.Errorf(errReadSecret, err)
+		return nil, nil, fmt.Errorf(errReadSecret, err)
 	}
 	if vaultSecret == nil {
-		return nil, esv1.NoSecretError{}
+		return nil, nil, esv1.NoSecretError{}
 	}
 	secretData := vaultSecret.Data
+	var versionMetadata *esv1.SecretMetadata
 	if c.store.Version == esv1.VaultKVStoreV2 {
 		// Vault KV2 has data embedded within sub-field
 		// reference - https://www.vaultproject.io/api/secret/kv/kv-v2#read-secret-version
 		dataInt, ok := vaultSecret.Data["data"]
 		if !ok {
-			return nil, errors.New(errDataField)
+			return nil, nil, errors.New(errDataField)
 		}
 		if dataInt == nil {
-			return nil, esv1.NoSecretError{}
+			return nil, nil, esv1.NoSecretError{}
 		}
 		secretData, ok = dataInt.(map[string]any)
 		if !ok {
-			return nil, errors.New(errJSONUnmarshall)
+			return nil, nil, errors.New(errJSONUnmarshall)
 		}
+		versionMetadata = kvVersionMetadata(vaultSecret.Data["metadata"])
 	}
 
-	return secretData, nil
+	return secretData, versionMetadata, nil
+}
+
+// kvVersionMetadata reads the version and created_time from the metadata of a KV v2 secret version.
+func kvVersionMetadata(metadata any) *esv1.SecretMetadata {
+	m, ok := metadata.(map[string]any)
+	if !ok {
+		return nil
+	}
+	versionMetadata := &esv1.SecretMetadata{}
+	if version, ok := m["version"]; ok && version != nil {
+		versionMetadata.Version = fmt.Sprint(version)
+	}
+	if created, ok := m["created_time"].(string); ok {
+		if t, err := time.Parse(time.RFC3339Nano, created); err == nil {
+			versionMetadata.LastModified = t
+		}
+	}
+	return versionMetadata
 }
 
 func getSecretValue(data map[string]any, property string) ([]byte, error) {
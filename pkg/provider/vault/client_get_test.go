@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	vault "github.com/hashicorp/vault/api"
@@ -967,3 +968,20 @@ func EquateErrors() cmp.Option {
 		return a.Error() == b.Error()
 	})
 }
+
+func TestKVVersionMetadata(t *testing.T) {
+	got := kvVersionMetadata(map[string]any{
+		"version":      json.Number("3"),
+		"created_time": "2026-10-17T08:00:00.123456Z",
+	})
+	want := &esv1.SecretMetadata{
+		Version:      "3",
+		LastModified: time.Date(2026, 10, 17, 8, 0, 0, 123456000, time.UTC),
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected metadata (-want, +got)\n%s", diff)
+	}
+	if kvVersionMetadata(nil) != nil {
+		t.Errorf("expected no metadata for secrets without metadata")
+	}
+}
@@ -16,14 +16,17 @@ package vault
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strings"
 	"testing"
 
+	vault "github.com/hashicorp/vault/api"
 	corev1 "k8s.io/api/core/v1"
 
 	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	"github.com/external-secrets/external-secrets/pkg/provider/testing/conformance"
 	testingfake "github.com/external-secrets/external-secrets/pkg/provider/testing/fake"
 	"github.com/external-secrets/external-secrets/pkg/provider/vault/fake"
 	"github.com/external-secrets/external-secrets/pkg/provider/vault/util"
@@ -700,3 +703,49 @@ func TestPushSecret(t *testing.T) {
 		})
 	}
 }
+
+// newMemoryLogical returns a Logical which stores the written data in memory.
+// The data is encoded as JSON, like the responses of Vault.
+func newMemoryLogical(t *testing.T) fake.Logical {
+	paths := make(map[string]map[string]any)
+	return fake.Logical{
+		ReadWithDataWithContextFn: func(_ context.Context, path string, _ map[string][]string) (*vault.Secret, error) {
+			data, ok := paths[path]
+			if !ok {
+				return nil, nil
+			}
+			return &vault.Secret{Data: data}, nil
+		},
+		WriteWithContextFn: func(_ context.Context, path string, data map[string]any) (*vault.Secret, error) {
+			raw, err := json.Marshal(data)
+			if err != nil {
+				t.Fatalf("could not encode %s: %v", path, err)
+			}
+			stored := make(map[string]any)
+			if err := json.Unmarshal(raw, &stored); err != nil {
+				t.Fatalf("could not decode %s: %v", path, err)
+			}
+			paths[path] = stored
+			return nil, nil
+		},
+	}
+}
+
+func TestPushSecretConformance(t *testing.T) {
+	for _, version := range []esv1.VaultKVStoreVersion{esv1.VaultKVStoreV1, esv1.VaultKVStoreV2} {
+		t.Run(string(version), func(t *testing.T) {
+			path := "secret"
+			c := &client{
+				logical: newMemoryLogical(t),
+				store: &esv1.VaultProvider{
+					Path:    &path,
+					Version: version,
+				},
+			}
+			conformance.RunPushSecret(t, c, conformance.PushSecretRef{
+				RemoteKey: "conformance-secret",
+				Property:  "conformance",
+			})
+		})
+	}
+}
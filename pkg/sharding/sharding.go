@@ -0,0 +1,418 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package sharding splits the reconciliation of ExternalSecrets across controller replicas.
+// ExternalSecrets are assigned to a fixed number of shards by a hash of their namespace or UID,
+// and every shard is held by a single replica at a time using a Lease.
+package sharding
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+	"github.com/external-secrets/external-secrets/pkg/metrics"
+)
+
+// Key is what ExternalSecrets are assigned to shards by.
+type Key string
+
+const (
+	// KeyNamespace assigns all ExternalSecrets of a namespace to the same shard.
+	KeyNamespace Key = "namespace"
+	// KeyUID spreads the ExternalSecrets of a namespace across all shards.
+	KeyUID Key = "uid"
+)
+
+const (
+	labelGroup = "external-secrets.io/shard-group"
+	labelRole  = "external-secrets.io/shard-role"
+	labelShard = "external-secrets.io/shard"
+
+	roleShard  = "shard"
+	roleMember = "member"
+
+	// member leases of replicas which are gone are deleted once they expired this many times over.
+	staleMemberFactor = 10
+
+	eventBufferSize = 1024
+
+	inClusterNamespaceFile = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+)
+
+// Options configure a Sharder.
+type Options struct {
+	// Shards is the number of shards.
+	Shards int
+	// Key is what ExternalSecrets are assigned to shards by.
+	Key Key
+	// Group is the name of the replicas sharing the shards, it prefixes the names of their Leases.
+	Group string
+	// Namespace of the Leases, defaults to the namespace the controller runs in.
+	Namespace string
+	// Identity of this replica.
+	Identity string
+	// LeaseDuration is how long a replica holds a shard without renewing its Lease.
+	LeaseDuration time.Duration
+	// RenewInterval is how often Leases are renewed and the shards are rebalanced.
+	RenewInterval time.Duration
+}
+
+// Sharder holds the shards of a replica.
+// Every replica renews a member Lease, and holds up to an equal share of the shards
+// among the live members. Replicas release the shards above their share when others join,
+// and take over the shards of replicas which are gone once their Leases expired.
+type Sharder struct {
+	opts   Options
+	leases client.Client
+	reader client.Reader
+	log    logr.Logger
+	now    func() time.Time
+
+	mu sync.RWMutex
+	// owned holds the shards of this replica, and until when their Lease is valid.
+	owned map[int]time.Time
+
+	events chan event.GenericEvent
+}
+
+// New creates a Sharder. leases should not be cached, reader lists the ExternalSecrets of acquired shards.
+func New(opts Options, leases client.Client, reader client.Reader, log logr.Logger) (*Sharder, error) {
+	if opts.Shards < 1 {
+		return nil, fmt.Errorf("invalid number of shards %d", opts.Shards)
+	}
+	if opts.Key != KeyNamespace && opts.Key != KeyUID {
+		return nil, fmt.Errorf("invalid shard key %q, must be %q or %q", opts.Key, KeyNamespace, KeyUID)
+	}
+	if opts.LeaseDuration < time.Second {
+		return nil, fmt.Errorf("shard lease duration %v must be at least 1s", opts.LeaseDuration)
+	}
+	if opts.RenewInterval <= 0 || opts.LeaseDuration <= opts.RenewInterval {
+		return nil, fmt.Errorf("shard lease duration %v must be longer than the renew interval %v", opts.LeaseDuration, opts.RenewInterval)
+	}
+	if opts.Identity == "" {
+		return nil, errors.New("identity of the replica must not be empty")
+	}
+	if opts.Namespace == "" {
+		ns, err := os.ReadFile(inClusterNamespaceFile)
+		if err != nil {
+			return nil, fmt.Errorf("could not determine the namespace of the shard leases: %w", err)
+		}
+		opts.Namespace = strings.TrimSpace(string(ns))
+	}
+	return &Sharder{
+		opts:   opts,
+		leases: leases,
+		reader: reader,
+		log:    log,
+		now:    time.Now,
+		owned:  make(map[int]time.Time),
+		events: make(chan event.GenericEvent, eventBufferSize),
+	}, nil
+}
+
+// Shard returns the shard of an object.
+func (s *Sharder) Shard(obj client.Object) int {
+	key := obj.GetNamespace()
+	if s.opts.Key == KeyUID {
+		key = string(obj.GetUID())
+	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	return int(h.Sum32() % uint32(s.opts.Shards))
+}
+
+// Owns reports whether an object belongs to a shard held by this replica.
+func (s *Sharder) Owns(obj client.Object) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	until, ok := s.owned[s.Shard(obj)]
+	return ok && s.now().Before(until)
+}
+
+// Events returns the ExternalSecrets of shards acquired by this replica, so they are reconciled right away.
+func (s *Sharder) Events() <-chan event.GenericEvent {
+	return s.events
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable, shards are held by all replicas.
+func (s *Sharder) NeedLeaderElection() bool {
+	return false
+}
+
+// Start renews the Leases until ctx is done, then releases them so other replicas take over right away.
+func (s *Sharder) Start(ctx context.Context) error {
+	ticker := time.NewTicker(s.opts.RenewInterval)
+	defer ticker.Stop()
+	for {
+		if err := s.sync(ctx); err != nil {
+			s.log.Error(err, "could not sync shards")
+		}
+		select {
+		case <-ctx.Done():
+			releaseCtx, cancel := context.WithTimeout(context.Background(), s.opts.RenewInterval)
+			defer cancel()
+			s.releaseAll(releaseCtx)
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// sync renews the member Lease of this replica and balances the shards among the live members.
+func (s *Sharder) sync(ctx context.Context) error {
+	now := s.now()
+	if err := s.renew(ctx, s.memberLeaseName(), roleMember, -1, now); err != nil {
+		return fmt.Errorf("could not renew member lease: %w", err)
+	}
+
+	var list coordinationv1.LeaseList
+	if err := s.leases.List(ctx, &list, client.InNamespace(s.opts.Namespace), client.MatchingLabels{labelGroup: s.opts.Group}); err != nil {
+		return fmt.Errorf("could not list leases: %w", err)
+	}
+	members := 0
+	shards := make(map[int]*coordinationv1.Lease)
+	for i := range list.Items {
+		lease := &list.Items[i]
+		switch lease.Labels[labelRole] {
+		case roleMember:
+			if !s.expired(lease, now, 1) {
+				members++
+			} else if s.expired(lease, now, staleMemberFactor) {
+				if err := s.leases.Delete(ctx, lease); client.IgnoreNotFound(err) != nil {
+					s.log.Error(err, "could not delete stale member lease", "lease", lease.Name)
+				}
+			}
+		case roleShard:
+			shard, err := strconv.Atoi(lease.Labels[labelShard])
+			if err == nil && shard >= 0 && shard < s.opts.Shards {
+				shards[shard] = lease
+			}
+		}
+	}
+	// the member lease of this replica was just renewed
+	members = max(members, 1)
+	share := (s.opts.Shards + members - 1) / members
+
+	var held []int
+	for shard := range s.opts.Shards {
+		if lease := shards[shard]; lease != nil && s.holds(lease, now) {
+			held = append(held, shard)
+		}
+	}
+	// release the shards above the share of this replica, so joining replicas get theirs
+	for len(held) > share {
+		shard := held[len(held)-1]
+		held = held[:len(held)-1]
+		s.release(ctx, shard, shards[shard])
+	}
+	renewed := held[:0]
+	for _, shard := range held {
+		if err := s.renew(ctx, s.shardLeaseName(shard), roleShard, shard, now); err != nil {
+			s.log.Error(err, "could not renew shard lease", "shard", shard)
+			continue
+		}
+		renewed = append(renewed, shard)
+	}
+	held = renewed
+	// take over free shards, and those of replicas which are gone
+	for shard := 0; shard < s.opts.Shards && len(held) < share; shard++ {
+		lease := shards[shard]
+		if slices.Contains(held, shard) || (lease != nil && lease.Spec.HolderIdentity != nil &&
+			*lease.Spec.HolderIdentity != "" && !s.expired(lease, now, 1)) {
+			continue
+		}
+		if err := s.renew(ctx, s.shardLeaseName(shard), roleShard, shard, now); err != nil {
+			// another replica was faster
+			s.log.V(1).Info("could not acquire shard", "shard", shard, "error", err.Error())
+			continue
+		}
+		held = append(held, shard)
+	}
+
+	s.setOwned(ctx, held, now.Add(s.opts.LeaseDuration))
+	metrics.SetShardMembers(members)
+	return nil
+}
+
+// setOwned updates the shards of this replica and requests reconciles for the ExternalSecrets of acquired ones.
+func (s *Sharder) setOwned(ctx context.Context, held []int, until time.Time) {
+	s.mu.Lock()
+	var acquired []int
+	owned := make(map[int]time.Time, len(held))
+	for _, shard := range held {
+		if _, ok := s.owned[shard]; !ok {
+			acquired = append(acquired, shard)
+		}
+		owned[shard] = until
+	}
+	for shard := range s.owned {
+		if _, ok := owned[shard]; !ok {
+			s.log.Info("released shard", "shard", shard)
+			metrics.SetShardOwned(shard, false)
+		}
+	}
+	s.owned = owned
+	s.mu.Unlock()
+
+	if len(acquired) == 0 {
+		return
+	}
+	for _, shard := range acquired {
+		s.log.Info("acquired shard", "shard", shard)
+		metrics.SetShardOwned(shard, true)
+	}
+	// the sync loop must not wait for the reconciles to be queued, or the leases would expire
+	go s.resync(ctx, acquired)
+}
+
+// resync sends the ExternalSecrets of shards to Events.
+func (s *Sharder) resync(ctx context.Context, shards []int) {
+	var list esv1.ExternalSecretList
+	if err := s.reader.List(ctx, &list); err != nil {
+		s.log.Error(err, "could not list ExternalSecrets of acquired shards")
+		return
+	}
+	for i := range list.Items {
+		es := &list.Items[i]
+		if !slices.Contains(shards, s.Shard(es)) {
+			continue
+		}
+		select {
+		case s.events <- event.GenericEvent{Object: es}:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// releaseAll releases the shards and the member Lease of this replica.
+func (s *Sharder) releaseAll(ctx context.Context) {
+	s.mu.RLock()
+	var held []int
+	for shard := range s.owned {
+		held = append(held, shard)
+	}
+	s.mu.RUnlock()
+	for _, shard := range held {
+		lease := &coordinationv1.Lease{}
+		if err := s.leases.Get(ctx, client.ObjectKey{Namespace: s.opts.Namespace, Name: s.shardLeaseName(shard)}, lease); err != nil {
+			continue
+		}
+		// the shard may have been taken over in the meantime
+		if ptr.Deref(lease.Spec.HolderIdentity, "") == s.opts.Identity {
+			s.release(ctx, shard, lease)
+		}
+	}
+	member := &coordinationv1.Lease{ObjectMeta: metav1.ObjectMeta{Namespace: s.opts.Namespace, Name: s.memberLeaseName()}}
+	if err := s.leases.Delete(ctx, member); client.IgnoreNotFound(err) != nil {
+		s.log.Error(err, "could not delete member lease")
+	}
+}
+
+// release stops reconciling a shard before giving up its Lease.
+func (s *Sharder) release(ctx context.Context, shard int, lease *coordinationv1.Lease) {
+	s.mu.Lock()
+	delete(s.owned, shard)
+	s.mu.Unlock()
+	metrics.SetShardOwned(shard, false)
+	s.log.Info("releasing shard", "shard", shard)
+
+	lease.Spec.HolderIdentity = nil
+	lease.Spec.RenewTime = nil
+	if err := s.leases.Update(ctx, lease); err != nil {
+		// the lease expires on its own
+		s.log.Error(err, "could not release shard lease", "shard", shard)
+	}
+}
+
+// renew creates or renews a Lease held by this replica.
+// It fails if the Lease changed in the meantime, e.g. because another replica acquired it.
+func (s *Sharder) renew(ctx context.Context, name, role string, shard int, now time.Time) error {
+	renewTime := metav1.NewMicroTime(now)
+	lease := &coordinationv1.Lease{}
+	err := s.leases.Get(ctx, client.ObjectKey{Namespace: s.opts.Namespace, Name: name}, lease)
+	if apierrors.IsNotFound(err) {
+		lease = &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: s.opts.Namespace,
+				Name:      name,
+				Labels:    map[string]string{labelGroup: s.opts.Group, labelRole: role},
+			},
+			Spec: coordinationv1.LeaseSpec{
+				HolderIdentity:       ptr.To(s.opts.Identity),
+				LeaseDurationSeconds: ptr.To(int32(s.opts.LeaseDuration.Seconds())),
+				AcquireTime:          &renewTime,
+				RenewTime:            &renewTime,
+			},
+		}
+		if role == roleShard {
+			lease.Labels[labelShard] = strconv.Itoa(shard)
+		}
+		return s.leases.Create(ctx, lease)
+	}
+	if err != nil {
+		return err
+	}
+	if !s.holds(lease, now) {
+		if lease.Spec.HolderIdentity != nil && *lease.Spec.HolderIdentity != "" && !s.expired(lease, now, 1) {
+			return fmt.Errorf("lease %s is held by %s", name, *lease.Spec.HolderIdentity)
+		}
+		lease.Spec.HolderIdentity = ptr.To(s.opts.Identity)
+		lease.Spec.AcquireTime = &renewTime
+		lease.Spec.LeaseTransitions = ptr.To(ptr.Deref(lease.Spec.LeaseTransitions, 0) + 1)
+	}
+	lease.Spec.LeaseDurationSeconds = ptr.To(int32(s.opts.LeaseDuration.Seconds()))
+	lease.Spec.RenewTime = &renewTime
+	// the update fails on a conflict if another replica changed the lease since it was read
+	return s.leases.Update(ctx, lease)
+}
+
+// holds reports whether a Lease is held by this replica.
+func (s *Sharder) holds(lease *coordinationv1.Lease, now time.Time) bool {
+	return ptr.Deref(lease.Spec.HolderIdentity, "") == s.opts.Identity && !s.expired(lease, now, 1)
+}
+
+// expired reports whether a Lease was not renewed for factor times its duration.
+func (s *Sharder) expired(lease *coordinationv1.Lease, now time.Time, factor int) bool {
+	if lease.Spec.RenewTime == nil || lease.Spec.LeaseDurationSeconds == nil {
+		return true
+	}
+	duration := time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second
+	return now.After(lease.Spec.RenewTime.Add(time.Duration(factor) * duration))
+}
+
+func (s *Sharder) shardLeaseName(shard int) string {
+	return fmt.Sprintf("%s-shard-%d", s.opts.Group, shard)
+}
+
+func (s *Sharder) memberLeaseName() string {
+	return fmt.Sprintf("%s-member-%s", s.opts.Group, s.opts.Identity)
+}
@@ -0,0 +1,147 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sharding
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	esv1 "github.com/external-secrets/external-secrets/apis/externalsecrets/v1"
+)
+
+const testShards = 4
+
+func newTestSharder(t *testing.T, kube client.Client, identity string, now *time.Time) *Sharder {
+	t.Helper()
+	s, err := New(Options{
+		Shards:        testShards,
+		Key:           KeyUID,
+		Group:         "external-secrets-default",
+		Namespace:     "external-secrets",
+		Identity:      identity,
+		LeaseDuration: 15 * time.Second,
+		RenewInterval: 5 * time.Second,
+	}, kube, kube, logr.Discard())
+	require.NoError(t, err)
+	s.now = func() time.Time { return *now }
+	return s
+}
+
+func ownedShards(s *Sharder) []int {
+	var owned []int
+	for shard := range testShards {
+		if _, ok := s.owned[shard]; ok {
+			owned = append(owned, shard)
+		}
+	}
+	return owned
+}
+
+func TestSharder(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(esv1.AddToScheme(scheme))
+	var objects []client.Object
+	for i := range 20 {
+		objects = append(objects, &esv1.ExternalSecret{ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("es-%d", i),
+			Namespace: "default",
+			UID:       types.UID(fmt.Sprintf("uid-%d", i)),
+		}})
+	}
+	kube := fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+	now := time.Now()
+
+	// a single replica holds all shards, and requests reconciles of their ExternalSecrets
+	a := newTestSharder(t, kube, "a", &now)
+	require.NoError(t, a.sync(ctx))
+	assert.Equal(t, []int{0, 1, 2, 3}, ownedShards(a))
+	for range objects {
+		select {
+		case e := <-a.Events():
+			assert.True(t, a.Owns(e.Object))
+		case <-time.After(time.Second):
+			t.Fatal("expected an event for every ExternalSecret")
+		}
+	}
+
+	// a joining replica gets its share once the first replica released it
+	b := newTestSharder(t, kube, "b", &now)
+	require.NoError(t, b.sync(ctx))
+	assert.Empty(t, ownedShards(b))
+	require.NoError(t, a.sync(ctx))
+	assert.Equal(t, []int{0, 1}, ownedShards(a))
+	require.NoError(t, b.sync(ctx))
+	assert.Equal(t, []int{2, 3}, ownedShards(b))
+
+	// every ExternalSecret is owned by exactly one replica
+	for _, obj := range objects {
+		assert.NotEqual(t, a.Owns(obj), b.Owns(obj), obj.GetName())
+	}
+
+	// shards are not owned once their lease expired without being renewed
+	now = now.Add(20 * time.Second)
+	for _, obj := range objects {
+		assert.False(t, a.Owns(obj), obj.GetName())
+	}
+
+	// the shards of a replica which is gone are taken over
+	require.NoError(t, a.sync(ctx))
+	assert.Equal(t, []int{0, 1, 2, 3}, ownedShards(a))
+
+	// a replica which shuts down releases its shards right away, but not those taken over by others
+	b.releaseAll(ctx)
+	lease := &coordinationv1.Lease{}
+	require.NoError(t, kube.Get(ctx, client.ObjectKey{Namespace: "external-secrets", Name: "external-secrets-default-shard-2"}, lease))
+	assert.Equal(t, "a", *lease.Spec.HolderIdentity)
+	require.NoError(t, b.sync(ctx))
+	require.NoError(t, a.sync(ctx))
+	assert.Equal(t, []int{0, 1}, ownedShards(a))
+	a.releaseAll(ctx)
+	require.NoError(t, b.sync(ctx))
+	assert.Equal(t, []int{0, 1, 2, 3}, ownedShards(b))
+	var leases coordinationv1.LeaseList
+	require.NoError(t, kube.List(ctx, &leases, client.MatchingLabels{labelRole: roleMember}))
+	require.Len(t, leases.Items, 1)
+	assert.Equal(t, "external-secrets-default-member-b", leases.Items[0].Name)
+}
+
+func TestShard(t *testing.T) {
+	s, err := New(Options{Shards: 3, Key: KeyNamespace, Namespace: "default", Identity: "a", LeaseDuration: 15 * time.Second, RenewInterval: 5 * time.Second}, nil, nil, logr.Discard())
+	require.NoError(t, err)
+	first := &esv1.ExternalSecret{ObjectMeta: metav1.ObjectMeta{Name: "first", Namespace: "team-a", UID: "1"}}
+	second := &esv1.ExternalSecret{ObjectMeta: metav1.ObjectMeta{Name: "second", Namespace: "team-a", UID: "2"}}
+	assert.Equal(t, s.Shard(first), s.Shard(second))
+
+	_, err = New(Options{Shards: 3, Key: "name", Namespace: "default", Identity: "a", LeaseDuration: 15 * time.Second, RenewInterval: 5 * time.Second}, nil, nil, logr.Discard())
+	assert.Error(t, err)
+	_, err = New(Options{Shards: 3, Key: KeyUID, Namespace: "default", Identity: "a", LeaseDuration: 5 * time.Second, RenewInterval: 5 * time.Second}, nil, nil, logr.Discard())
+	assert.Error(t, err)
+}
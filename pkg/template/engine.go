@@ -32,3 +32,13 @@ func EngineForVersion(version esapi.TemplateEngineVersion) (ExecFunc, error) {
 	}
 	return nil, fmt.Errorf("unsupported template engine version: %s", version)
 }
+
+// EngineWithMetadata returns the engine of the given version, which also exposes
+// the metadata of the remote secrets to templates, e.g. {{ .metadata.password.version }}.
+func EngineWithMetadata(version esapi.TemplateEngineVersion, metadata map[string]map[string]string) (ExecFunc, error) {
+	switch version { //nolint:gocritic
+	case esapi.TemplateEngineV2:
+		return v2.ExecuteWithMetadata(metadata), nil
+	}
+	return nil, fmt.Errorf("unsupported template engine version: %s", version)
+}
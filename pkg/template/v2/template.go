@@ -63,6 +63,9 @@ const (
 	errDecodeCertWithPass   = "unable to decode pkcs12 certificate with password: %s"
 	errParsePrivKey         = "unable to parse private key type"
 
+	// metadataKey is the key of the metadata of the remote secrets in the data of templates.
+	metadataKey = "metadata"
+
 	pemTypeCertificate = "CERTIFICATE"
 	pemTypeKey         = "PRIVATE KEY"
 )
@@ -104,9 +107,9 @@ func applyToTarget(k string, val []byte, target esapi.TemplateTarget, secret *co
 	}
 }
 
-func valueScopeApply(tplMap, data map[string][]byte, target esapi.TemplateTarget, secret *corev1.Secret) error {
+func valueScopeApply(tplMap, data map[string][]byte, metadata map[string]map[string]string, target esapi.TemplateTarget, secret *corev1.Secret) error {
 	for k, v := range tplMap {
-		val, err := execute(k, string(v), data)
+		val, err := execute(k, string(v), data, metadata)
 		if err != nil {
 			return fmt.Errorf(errExecute, k, err)
 		}
@@ -115,8 +118,8 @@ func valueScopeApply(tplMap, data map[string][]byte, target esapi.TemplateTarget
 	return nil
 }
 
-func mapScopeApply(tpl string, data map[string][]byte, target esapi.TemplateTarget, secret *corev1.Secret) error {
-	val, err := execute(tpl, tpl, data)
+func mapScopeApply(tpl string, data map[string][]byte, metadata map[string]map[string]string, target esapi.TemplateTarget, secret *corev1.Secret) error {
+	val, err := execute(tpl, tpl, data, metadata)
 	if err != nil {
 		return fmt.Errorf(errExecute, tpl, err)
 	}
@@ -133,19 +136,32 @@ func mapScopeApply(tpl string, data map[string][]byte, target esapi.TemplateTarg
 
 // Execute renders the secret data as template. If an error occurs processing is stopped immediately.
 func Execute(tpl, data map[string][]byte, scope esapi.TemplateScope, target esapi.TemplateTarget, secret *corev1.Secret) error {
+	return execTemplates(tpl, data, nil, scope, target, secret)
+}
+
+// ExecuteWithMetadata returns an Execute which also exposes the metadata of the remote secrets
+// as .metadata.<key>.<field>, e.g. {{ .metadata.password.version }}.
+// A secret key named metadata takes precedence over the metadata.
+func ExecuteWithMetadata(metadata map[string]map[string]string) func(tpl, data map[string][]byte, scope esapi.TemplateScope, target esapi.TemplateTarget, secret *corev1.Secret) error {
+	return func(tpl, data map[string][]byte, scope esapi.TemplateScope, target esapi.TemplateTarget, secret *corev1.Secret) error {
+		return execTemplates(tpl, data, metadata, scope, target, secret)
+	}
+}
+
+func execTemplates(tpl, data map[string][]byte, metadata map[string]map[string]string, scope esapi.TemplateScope, target esapi.TemplateTarget, secret *corev1.Secret) error {
 	if tpl == nil {
 		return nil
 	}
 	switch scope {
 	case esapi.TemplateScopeKeysAndValues:
 		for _, v := range tpl {
-			err := mapScopeApply(string(v), data, target, secret)
+			err := mapScopeApply(string(v), data, metadata, target, secret)
 			if err != nil {
 				return err
 			}
 		}
 	case esapi.TemplateScopeValues:
-		err := valueScopeApply(tpl, data, target, secret)
+		err := valueScopeApply(tpl, data, metadata, target, secret)
 		if err != nil {
 			return err
 		}
@@ -155,11 +171,14 @@ func Execute(tpl, data map[string][]byte, scope esapi.TemplateScope, target esap
 	return nil
 }
 
-func execute(k, val string, data map[string][]byte) ([]byte, error) {
-	strValData := make(map[string]string, len(data))
+func execute(k, val string, data map[string][]byte, metadata map[string]map[string]string) ([]byte, error) {
+	strValData := make(map[string]any, len(data)+1)
 	for k := range data {
 		strValData[k] = string(data[k])
 	}
+	if _, ok := data[metadataKey]; !ok && metadata != nil {
+		strValData[metadataKey] = metadata
+	}
 
 	t, err := tpl.New(k).
 		Option("missingkey=error").
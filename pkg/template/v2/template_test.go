@@ -638,6 +638,31 @@ func TestExecuteInvalidTemplateScope(t *testing.T) {
 	assert.ErrorContains(t, err, "expected 'Values' or 'KeysAndValues'")
 }
 
+func TestExecuteWithMetadata(t *testing.T) {
+	metadata := map[string]map[string]string{
+		"password": {"version": "3", "lastModified": "2026-10-17T08:00:00Z"},
+	}
+	tpl := map[string][]byte{
+		"password":         []byte("{{ .password }}"),
+		"password-version": []byte("{{ .metadata.password.version }}"),
+		"password-updated": []byte("{{ .metadata.password.lastModified }}"),
+	}
+	sec := &corev1.Secret{}
+	err := ExecuteWithMetadata(metadata)(tpl, map[string][]byte{"password": []byte("secret")}, esapi.TemplateScopeValues, esapi.TemplateTargetData, sec)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]byte{
+		"password":         []byte("secret"),
+		"password-version": []byte("3"),
+		"password-updated": []byte("2026-10-17T08:00:00Z"),
+	}, sec.Data)
+
+	// a secret key named metadata takes precedence
+	sec = &corev1.Secret{}
+	err = ExecuteWithMetadata(metadata)(map[string][]byte{"out": []byte("{{ .metadata }}")}, map[string][]byte{"metadata": []byte("value")}, esapi.TemplateScopeValues, esapi.TemplateTargetData, sec)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("value"), sec.Data["out"])
+}
+
 func TestScopeKeysAndValues(t *testing.T) {
 	tbl := []struct {
 		name               string
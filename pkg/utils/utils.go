@@ -17,6 +17,7 @@ package utils
 import (
 	"bytes"
 	"context"
+	"crypto/hmac"
 	"crypto/sha3"
 	"crypto/x509"
 	"encoding/base64"
@@ -24,6 +25,7 @@ import (
 	"encoding/pem"
 	"errors"
 	"fmt"
+	"hash"
 	"maps"
 	"net"
 	"net/url"
@@ -459,6 +461,14 @@ func ObjectHash(object any) string {
 	return fmt.Sprintf("%x", sha3.Sum224([]byte(textualVersion)))
 }
 
+// KeyedHash calculates the sha3 HMAC of an object, keyed by a secret key and the UID of the resource it is recorded in.
+// Unlike ObjectHash, it can be written to the status of a resource without allowing to guess the values offline.
+func KeyedHash(key []byte, uid string, object any) string {
+	mac := hmac.New(func() hash.Hash { return sha3.New224() }, append(slices.Clone(key), uid...))
+	_, _ = fmt.Fprintf(mac, "%+v", object)
+	return fmt.Sprintf("%x", mac.Sum(nil))
+}
+
 func ErrorContains(out error, want string) bool {
 	if out == nil {
 		return want == ""
@@ -83,6 +83,22 @@ func TestObjectHash(t *testing.T) {
 	}
 }
 
+func TestKeyedHash(t *testing.T) {
+	hash := KeyedHash([]byte("key"), "uid", []byte("value"))
+	if hash == ObjectHash([]byte("value")) {
+		t.Errorf("KeyedHash() must not match ObjectHash()")
+	}
+	if hash != KeyedHash([]byte("key"), "uid", []byte("value")) {
+		t.Errorf("KeyedHash() is not stable")
+	}
+	if hash == KeyedHash([]byte("other"), "uid", []byte("value")) {
+		t.Errorf("KeyedHash() must depend on the key")
+	}
+	if hash == KeyedHash([]byte("key"), "other", []byte("value")) {
+		t.Errorf("KeyedHash() must depend on the uid")
+	}
+}
+
 func TestIsNil(t *testing.T) {
 	tbl := []struct {
 		name string